@@ -56,6 +56,9 @@ enum {
     _SYS_EXECVE = 59,
     _SYS_EXECVEAT = 322,
     _DO_EXIT = 351,
+
+    // syscall allow-lists
+    _SYSCALL_VIOLATION = 353,
 };
 
 typedef struct __attribute__((__packed__)) sys_context {
@@ -841,3 +844,94 @@ int trace_ret_listen(struct pt_regs *ctx)
 {
     return trace_ret_generic(_SYS_LISTEN, ctx, ARG_TYPE0(INT_T)|ARG_TYPE1(INT_T));
 }
+
+// == Syscall Hooks (Allow-lists) == //
+
+#ifndef MONITOR_HOST
+
+#define MAX_SYSCALL_NR  512
+
+typedef struct ns_key {
+    u32 pid_ns;
+    u32 mnt_ns;
+} ns_key_t;
+
+typedef struct syscall_bitmap {
+    u64 bits[MAX_SYSCALL_NR / 64];
+} syscall_bitmap_t;
+
+BPF_HASH(syscall_allow_map, ns_key_t, syscall_bitmap_t);
+
+// a violation of the same syscall in a container is submitted at most once per interval
+#define SYSCALL_VIOLATION_INTERVAL  1000000000ULL
+
+typedef struct violation_key {
+    u32 pid_ns;
+    u32 mnt_ns;
+    u32 nr;
+} violation_key_t;
+
+BPF_TABLE("lru_hash", violation_key_t, u64, syscall_violation_map, 10240);
+
+TRACEPOINT_PROBE(raw_syscalls, sys_enter)
+{
+    sys_context_t context = {};
+    ns_key_t key = {};
+
+    int nr = args->id;
+    if (nr < 0 || nr >= MAX_SYSCALL_NR)
+        return 0;
+
+    if (skip_syscall())
+        return 0;
+
+    struct task_struct *task = (struct task_struct *)bpf_get_current_task();
+
+    key.pid_ns = get_task_pid_ns_id(task);
+    key.mnt_ns = get_task_mnt_ns_id(task);
+
+    // no allow-list for the container
+    syscall_bitmap_t *allowed = syscall_allow_map.lookup(&key);
+    if (allowed == NULL)
+        return 0;
+
+    // only the syscalls out of the allow-list are submitted
+    if (allowed->bits[(nr / 64) & (MAX_SYSCALL_NR / 64 - 1)] & (1ULL << (nr % 64)))
+        return 0;
+
+    // rate-limit the violations of the same syscall (e.g., a missing futex)
+    violation_key_t vkey = {};
+
+    vkey.pid_ns = key.pid_ns;
+    vkey.mnt_ns = key.mnt_ns;
+    vkey.nr = nr;
+
+    u64 now = bpf_ktime_get_ns();
+
+    u64 *last = syscall_violation_map.lookup(&vkey);
+    if (last != NULL && now - *last < SYSCALL_VIOLATION_INTERVAL)
+        return 0;
+
+    syscall_violation_map.update(&vkey, &now);
+
+    init_context(&context);
+
+    context.event_id = _SYSCALL_VIOLATION;
+    context.argnum = 1;
+    context.retval = 0;
+
+    set_buffer_offset(sizeof(sys_context_t));
+
+    bufs_t *bufs_p = get_buffer();
+    if (bufs_p == NULL)
+        return 0;
+
+    save_context_to_buffer(bufs_p, (void*)&context);
+    save_to_buffer(bufs_p, (void*)&nr, sizeof(int), INT_T);
+
+    events_perf_submit((struct pt_regs *)args);
+
+    return 0;
+}
+
+#endif /* !MONITOR_HOST */
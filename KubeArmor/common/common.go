@@ -1,11 +1,14 @@
 package common
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net"
 	"os"
 	"os/exec"
+	"path"
 	"reflect"
 	"strconv"
 	"strings"
@@ -26,6 +29,14 @@ func Clone(src, dst interface{}) {
 	json.Unmarshal(arr, dst)
 }
 
+// GetSpecHash Function
+func GetSpecHash(spec interface{}) string {
+	// json.Marshal sorts map keys, so the same spec always has the same hash
+	arr, _ := json.Marshal(spec)
+	hash := sha256.Sum256(arr)
+	return hex.EncodeToString(hash[:])
+}
+
 // ContainsElement Function
 func ContainsElement(slice interface{}, element interface{}) bool {
 	switch reflect.TypeOf(slice).Kind() {
@@ -323,6 +334,39 @@ func IsK8sEnv() bool {
 	return false
 }
 
+// =========== //
+// == Paths == //
+// =========== //
+
+// GetCanonicalPath Function
+func GetCanonicalPath(p string) (string, error) {
+	if !path.IsAbs(p) {
+		return "", fmt.Errorf("%q is not an absolute path", p)
+	}
+
+	return path.Clean(p), nil
+}
+
+// GetCanonicalResource Function
+func GetCanonicalResource(resource string) string {
+	// a process resource can be followed by arguments
+	target, args := resource, ""
+	if idx := strings.Index(resource, " "); idx != -1 {
+		target, args = resource[:idx], resource[idx:]
+	}
+
+	if !path.IsAbs(target) {
+		return resource
+	}
+
+	cleaned := path.Clean(target)
+	if strings.HasSuffix(target, "/") && cleaned != "/" {
+		cleaned = cleaned + "/"
+	}
+
+	return cleaned + args
+}
+
 // ==================== //
 // == Identity Match == //
 // ==================== //
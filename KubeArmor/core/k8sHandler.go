@@ -220,16 +220,16 @@ func (kh *K8sHandler) GetContainerRuntime() string {
 func (kh *K8sHandler) GetNodeIdentities() []string {
 	nodeIdentities := []string{}
 
-	if !kl.IsK8sEnv() { // not Kubernetes
-		return nodeIdentities
-	}
-
 	// get a host name
 	hostName := kl.GetHostName()
 
-	// add the host name
+	// add the host name (also for host policies in standalone mode)
 	nodeIdentities = append(nodeIdentities, "hostName="+hostName)
 
+	if !kl.IsK8sEnv() { // not Kubernetes
+		return nodeIdentities
+	}
+
 	// get a node from k8s api client
 	node, err := kh.K8sClient.CoreV1().Nodes().Get(context.Background(), hostName, metav1.GetOptions{})
 	if err != nil {
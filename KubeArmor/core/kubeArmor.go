@@ -8,6 +8,8 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/fsnotify/fsnotify"
+
 	kg "github.com/accuknox/KubeArmor/KubeArmor/log"
 	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
 
@@ -32,9 +34,13 @@ func init() {
 // KubeArmorDaemon Structure
 type KubeArmorDaemon struct {
 	// options
-	EnableAuditd     bool
-	EnableHostPolicy bool
-	EnableSystemLog  bool
+	EnableAuditd             bool
+	EnableHostPolicy         bool
+	EnableSystemLog          bool
+	EnableWorkloadEnrichment bool
+	EnableSharedPidNs        bool
+	EnableDecisionPoint      bool
+	EnableLogStream          bool
 
 	// containers (from docker)
 	Containers     map[string]tp.Container
@@ -44,6 +50,9 @@ type KubeArmorDaemon struct {
 	ContainerGroups     []tp.ContainerGroup
 	ContainerGroupsLock *sync.RWMutex
 
+	// namespace/pod -> the reported label conflicts (protected by ContainerGroupsLock)
+	LabelConflicts map[string][]string
+
 	// K8s pods
 	K8sPods     []tp.K8sPod
 	K8sPodsLock *sync.RWMutex
@@ -56,6 +65,11 @@ type KubeArmorDaemon struct {
 	HostSecurityPolicies     []tp.HostSecurityPolicy
 	HostSecurityPoliciesLock *sync.RWMutex
 
+	// policy file -> the policies loaded from the file (standalone mode)
+	PolicyFiles      map[string]PolicyFile
+	PolicyFilesLock  *sync.Mutex
+	PolicyDirWatcher *fsnotify.Watcher
+
 	// container id -> (host) pid
 	ActivePidMap     map[string]tp.PidMap
 	ActiveHostPidMap map[string]tp.PidMap
@@ -82,12 +96,16 @@ type KubeArmorDaemon struct {
 }
 
 // NewKubeArmorDaemon Function
-func NewKubeArmorDaemon(enableAuditd, enableHostPolicy, enableSystemLog bool) *KubeArmorDaemon {
+func NewKubeArmorDaemon(enableAuditd, enableHostPolicy, enableSystemLog, enableWorkloadEnrichment, enableSharedPidNs, enableDecisionPoint, enableLogStream bool) *KubeArmorDaemon {
 	dm := new(KubeArmorDaemon)
 
 	dm.EnableAuditd = enableAuditd
 	dm.EnableHostPolicy = enableHostPolicy
 	dm.EnableSystemLog = enableSystemLog
+	dm.EnableWorkloadEnrichment = enableWorkloadEnrichment
+	dm.EnableSharedPidNs = enableSharedPidNs
+	dm.EnableDecisionPoint = enableDecisionPoint
+	dm.EnableLogStream = enableLogStream
 
 	dm.Containers = map[string]tp.Container{}
 	dm.ContainersLock = new(sync.RWMutex)
@@ -95,6 +113,8 @@ func NewKubeArmorDaemon(enableAuditd, enableHostPolicy, enableSystemLog bool) *K
 	dm.ContainerGroups = []tp.ContainerGroup{}
 	dm.ContainerGroupsLock = new(sync.RWMutex)
 
+	dm.LabelConflicts = map[string][]string{}
+
 	dm.K8sPods = []tp.K8sPod{}
 	dm.K8sPodsLock = new(sync.RWMutex)
 
@@ -104,6 +124,9 @@ func NewKubeArmorDaemon(enableAuditd, enableHostPolicy, enableSystemLog bool) *K
 	dm.HostSecurityPolicies = []tp.HostSecurityPolicy{}
 	dm.HostSecurityPoliciesLock = new(sync.RWMutex)
 
+	dm.PolicyFiles = map[string]PolicyFile{}
+	dm.PolicyFilesLock = new(sync.Mutex)
+
 	dm.ActivePidMap = map[string]tp.PidMap{}
 	dm.ActiveHostPidMap = map[string]tp.PidMap{}
 	dm.ActivePidMapLock = new(sync.RWMutex)
@@ -123,6 +146,12 @@ func NewKubeArmorDaemon(enableAuditd, enableHostPolicy, enableSystemLog bool) *K
 
 // DestroyKubeArmorDaemon Function
 func (dm *KubeArmorDaemon) DestroyKubeArmorDaemon() {
+	if dm.PolicyDirWatcher != nil {
+		// stop watching the policy directory
+		dm.PolicyDirWatcher.Close()
+		dm.LogFeeder.Print("Stopped watching the policy directory")
+	}
+
 	if dm.RuntimeEnforcer != nil {
 		// close runtime enforcer
 		dm.CloseRuntimeEnforcer()
@@ -162,12 +191,74 @@ func (dm *KubeArmorDaemon) DestroyKubeArmorDaemon() {
 // ================ //
 
 // InitLogFeeder Function
-func (dm *KubeArmorDaemon) InitLogFeeder(gRPCPort, logPath string) bool {
+func (dm *KubeArmorDaemon) InitLogFeeder(gRPCPort, logPath, metricsPort, tlsCertPath, tlsKeyPath, severityEscalations, quarantineWebhook string, maxUnackedLogs, blockSummaryInterval, backfillSize, backfillAge, maxDecisionEntries, dropLogInterval int) bool {
 	dm.LogFeeder = fd.NewFeeder(gRPCPort, logPath, dm.EnableSystemLog)
 	if dm.LogFeeder == nil {
 		return false
 	}
 
+	// annotate matched policy logs with decision points
+	dm.LogFeeder.EnableDecisionPoint = dm.EnableDecisionPoint
+
+	if tlsCertPath != "none" && tlsKeyPath != "none" {
+		if err := dm.LogFeeder.SetTLSConfig(tlsCertPath, tlsKeyPath); err != nil {
+			kg.Errf("Failed to load a TLS certificate (%s, %s)", tlsCertPath, err.Error())
+			return false
+		}
+	}
+
+	if metricsPort != "none" {
+		if err := dm.LogFeeder.EnableMetrics(metricsPort); err != nil {
+			kg.Errf("Failed to listen a metrics port (%s, %s)", metricsPort, err.Error())
+			return false
+		}
+	}
+
+	if dm.EnableLogStream {
+		if err := dm.LogFeeder.EnableLogStream(); err != nil {
+			kg.Errf("Failed to enable the log stream (%s)", err.Error())
+			return false
+		}
+	}
+
+	if err := dm.LogFeeder.SetSeverityEscalations(severityEscalations); err != nil {
+		kg.Errf("Failed to parse severity escalations (%s, %s)", severityEscalations, err.Error())
+		return false
+	}
+
+	if err := dm.LogFeeder.SetQuarantineWebhook(quarantineWebhook); err != nil {
+		kg.Errf("Failed to set the quarantine webhook (%s)", err.Error())
+		return false
+	}
+
+	if err := dm.LogFeeder.SetMaxUnackedLogs(maxUnackedLogs); err != nil {
+		kg.Errf("Failed to set the maximum number of unacked logs (%s)", err.Error())
+		return false
+	}
+
+	// expose the process trees of containers
+	dm.LogFeeder.SetProcessTree(&dm.ActivePidMap, &dm.ActivePidMapLock)
+
+	if err := dm.LogFeeder.SetBlockSummaryInterval(blockSummaryInterval); err != nil {
+		kg.Errf("Failed to set the interval of Block summaries (%s)", err.Error())
+		return false
+	}
+
+	if err := dm.LogFeeder.SetLogBackfill(backfillSize, backfillAge); err != nil {
+		kg.Errf("Failed to set the bounds of log backfill (%s)", err.Error())
+		return false
+	}
+
+	if err := dm.LogFeeder.SetMaxDecisionEntries(maxDecisionEntries); err != nil {
+		kg.Errf("Failed to set the maximum number of tracked decisions (%s)", err.Error())
+		return false
+	}
+
+	if err := dm.LogFeeder.SetDropLogInterval(dropLogInterval); err != nil {
+		kg.Errf("Failed to set the interval of dropped event logs (%s)", err.Error())
+		return false
+	}
+
 	return true
 }
 
@@ -208,13 +299,31 @@ func (dm *KubeArmorDaemon) CloseRuntimeEnforcer() {
 // ==================== //
 
 // InitSystemMonitor Function
-func (dm *KubeArmorDaemon) InitSystemMonitor() bool {
+func (dm *KubeArmorDaemon) InitSystemMonitor(interpreters, processLimits string, processTreeTTL, maxProcessTreeEntries int) bool {
 	dm.SystemMonitor = mon.NewSystemMonitor(dm.LogFeeder, dm.EnableAuditd, dm.EnableHostPolicy,
 		&dm.Containers, &dm.ContainersLock, &dm.ActivePidMap, &dm.ActiveHostPidMap, &dm.ActivePidMapLock, &dm.ActiveHostMap, &dm.ActiveHostMapLock)
 	if dm.SystemMonitor == nil {
 		return false
 	}
 
+	if interpreters != "none" {
+		dm.SystemMonitor.Interpreters = strings.Split(interpreters, ",")
+	}
+
+	dm.SystemMonitor.EnableSharedPidNs = dm.EnableSharedPidNs
+
+	limits, err := mon.ParseProcessLimits(processLimits)
+	if err != nil {
+		kg.Errf("Failed to parse process limits (%s, %s)", processLimits, err.Error())
+		return false
+	}
+	dm.SystemMonitor.DefaultProcessLimits = limits
+
+	if err := dm.SystemMonitor.SetPidReaper(processTreeTTL, maxProcessTreeEntries); err != nil {
+		kg.Errf("Failed to set the reaper of process trees (%s)", err.Error())
+		return false
+	}
+
 	if err := dm.SystemMonitor.InitBPF(); err != nil {
 		return false
 	}
@@ -299,12 +408,12 @@ func GetOSSigChannel() chan os.Signal {
 // ========== //
 
 // KubeArmor Function
-func KubeArmor(gRPCPort, logPath string, enableAuditd, enableHostPolicy, enableSystemLog bool) {
+func KubeArmor(gRPCPort, logPath, metricsPort, tlsCertPath, tlsKeyPath, interpreters, processLimits, severityEscalations, quarantineWebhook, policyDir string, maxUnackedLogs, blockSummaryInterval, backfillSize, backfillAge, maxDecisionEntries, dropLogInterval, processTreeTTL, maxProcessTreeEntries int, enableAuditd, enableHostPolicy, enableSystemLog, enableWorkloadEnrichment, enableSharedPidNs, enableDecisionPoint, enableLogStream bool) {
 	// create a daemon
-	dm := NewKubeArmorDaemon(enableAuditd, enableHostPolicy, enableSystemLog)
+	dm := NewKubeArmorDaemon(enableAuditd, enableHostPolicy, enableSystemLog, enableWorkloadEnrichment, enableSharedPidNs, enableDecisionPoint, enableLogStream)
 
 	// initialize log feeder
-	if !dm.InitLogFeeder(gRPCPort, logPath) {
+	if !dm.InitLogFeeder(gRPCPort, logPath, metricsPort, tlsCertPath, tlsKeyPath, severityEscalations, quarantineWebhook, maxUnackedLogs, blockSummaryInterval, backfillSize, backfillAge, maxDecisionEntries, dropLogInterval) {
 		kg.Err("Failed to intialize the log feeder")
 		return
 	}
@@ -314,7 +423,7 @@ func KubeArmor(gRPCPort, logPath string, enableAuditd, enableHostPolicy, enableS
 	kg.Print("Started to serve gRPC-based log feeds")
 
 	// initialize system monitor
-	if !dm.InitSystemMonitor() {
+	if !dm.InitSystemMonitor(interpreters, processLimits, processTreeTTL, maxProcessTreeEntries) {
 		dm.LogFeeder.Err("Failed to initialize the system monitor")
 
 		// destroy the daemon
@@ -392,6 +501,17 @@ func KubeArmor(gRPCPort, logPath string, enableAuditd, enableHostPolicy, enableS
 		dm.LogFeeder.Err("Failed to initialize the Kubernetes client")
 	}
 
+	if policyDir != "none" {
+		// load policies from the policy directory (standalone mode)
+		if err := dm.LoadPolicyDir(policyDir); err != nil {
+			dm.LogFeeder.Errf("Failed to load policies from %s (%s)", policyDir, err.Error())
+		} else if err := dm.WatchPolicyDir(policyDir); err != nil {
+			dm.LogFeeder.Errf("Failed to watch %s (%s)", policyDir, err.Error())
+		} else {
+			dm.LogFeeder.Printf("Started to monitor policies in %s", policyDir)
+		}
+	}
+
 	// wait for a while
 	time.Sleep(time.Second * 1)
 
@@ -1,14 +1,18 @@
 package core
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"io"
-	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	kl "github.com/accuknox/KubeArmor/KubeArmor/common"
 	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
@@ -49,6 +53,29 @@ func (dm *KubeArmorDaemon) UpdateContainerGroupWithContainer(action string, cont
 			dm.ContainerGroups[conGroupIdx].Containers = append(dm.ContainerGroups[conGroupIdx].Containers, container.ContainerID)
 			dm.ContainerGroups[conGroupIdx].AppArmorProfiles[container.ContainerID] = container.AppArmorProfile
 		}
+
+		annotations := dm.GetPodAnnotations(container.NamespaceName, container.ContainerGroupName)
+
+		// merge the runtime labels of the container into the labels and identities of the container group
+		podLabels := dm.GetPodLabels(dm.ContainerGroups[conGroupIdx].NamespaceName, dm.ContainerGroups[conGroupIdx].ContainerGroupName)
+		if dm.UpdateContainerGroupLabels(&dm.ContainerGroups[conGroupIdx], podLabels, annotations) {
+			// get security policies according to the updated identities
+			dm.ContainerGroups[conGroupIdx].SecurityPolicies = dm.GetSecurityPolicies(dm.ContainerGroups[conGroupIdx].Identities)
+
+			// update security policies
+			dm.LogFeeder.UpdateSecurityPolicies("UPDATED", dm.ContainerGroups[conGroupIdx])
+		}
+
+		// update the process limits of the container
+		dm.UpdateProcessLimits(container.NamespaceName, container.ContainerGroupName, annotations, []string{container.ContainerID})
+
+		// update the syscall allow-list of the container
+		dm.UpdateSyscallAllowList(container.NamespaceName, container.ContainerGroupName, annotations, []string{container.ContainerID})
+
+		// update the workload of logs
+		if dm.EnableWorkloadEnrichment {
+			dm.LogFeeder.UpdateWorkload(action, dm.ContainerGroups[conGroupIdx])
+		}
 	} else { // DELETED
 		if kl.ContainsElement(dm.ContainerGroups[conGroupIdx].Identities, "containerName="+container.ContainerName) {
 			for idxL, identity := range dm.ContainerGroups[conGroupIdx].Identities {
@@ -69,8 +96,14 @@ func (dm *KubeArmorDaemon) UpdateContainerGroupWithContainer(action string, cont
 			delete(dm.ContainerGroups[conGroupIdx].AppArmorProfiles, container.ContainerID)
 		}
 
+		// remove the syscall allow-list of the container (before NsMap is updated)
+		dm.SystemMonitor.DeleteSyscallAllowList(container.ContainerID)
+
 		// update NsMap
 		dm.SystemMonitor.DeleteContainerIDFromNsMap(container.ContainerID)
+
+		// remove the process limits of the container
+		dm.SystemMonitor.DeleteProcessLimits(container.ContainerID)
 	}
 
 	// enforce security policies
@@ -96,29 +129,17 @@ func (dm *KubeArmorDaemon) UpdateContainerGroupWithPod(action string, pod tp.K8s
 		newGroup.NamespaceName = pod.Metadata["namespaceName"]
 		newGroup.ContainerGroupName = pod.Metadata["podName"]
 
-		newGroup.Labels = []string{}
-		newGroup.Identities = []string{}
-
-		newGroup.Identities = append(newGroup.Identities, "namespaceName="+newGroup.NamespaceName)
-		newGroup.Identities = append(newGroup.Identities, "containerGroupName="+newGroup.ContainerGroupName)
-
-		for k, v := range pod.Labels {
-			if !kl.ContainsElement(newGroup.Labels, k+"="+v) {
-				newGroup.Labels = append(newGroup.Labels, k+"="+v)
-			}
-
-			if kl.ContainsElement([]string{"controller-revision-hash", "pod-template-hash", "pod-template-generation"}, k) {
-				continue
-			}
-
-			if !kl.ContainsElement(newGroup.Identities, k+"="+v) {
-				newGroup.Identities = append(newGroup.Identities, k+"="+v)
-			}
-		}
-
 		newGroup.Containers = []string{}
 		newGroup.AppArmorProfiles = map[string]string{}
 
+		// merge the labels and annotations of the pod
+		newGroup.Identities = []string{}
+		dm.UpdateContainerGroupLabels(&newGroup, pod.Labels, pod.Annotations)
+
+		// set the owning workload
+		newGroup.WorkloadKind = pod.Metadata["workloadKind"]
+		newGroup.WorkloadName = pod.Metadata["workloadName"]
+
 		// update security policies with the identities
 		newGroup.SecurityPolicies = dm.GetSecurityPolicies(newGroup.Identities)
 
@@ -135,9 +156,18 @@ func (dm *KubeArmorDaemon) UpdateContainerGroupWithPod(action string, pod tp.K8s
 		newGroup.HostVolumes = []tp.HostMountedVolume{}
 		newGroup.HostVolumes = append(newGroup.HostVolumes, pod.HostVolumes...)
 
+		// host policies are also evaluated for the pods sharing the host namespaces
+		newGroup.HostPID = pod.HostPID
+		newGroup.HostNetwork = pod.HostNetwork
+
 		// add the container group into the container group list
 		dm.ContainerGroups = append(dm.ContainerGroups, newGroup)
 
+		// update the workload of logs
+		if dm.EnableWorkloadEnrichment {
+			dm.LogFeeder.UpdateWorkload(action, newGroup)
+		}
+
 		// update security profiles
 		dm.RuntimeEnforcer.UpdateSecurityProfiles(action, pod)
 
@@ -159,24 +189,25 @@ func (dm *KubeArmorDaemon) UpdateContainerGroupWithPod(action string, pod tp.K8s
 
 		// update the labels and identities of the container group
 
-		dm.ContainerGroups[conGroupIdx].Labels = []string{}
-		dm.ContainerGroups[conGroupIdx].Identities = []string{}
+		dm.UpdateContainerGroupLabels(&dm.ContainerGroups[conGroupIdx], pod.Labels, pod.Annotations)
 
-		dm.ContainerGroups[conGroupIdx].Identities = append(dm.ContainerGroups[conGroupIdx].Identities, "namespaceName="+dm.ContainerGroups[conGroupIdx].NamespaceName)
-		dm.ContainerGroups[conGroupIdx].Identities = append(dm.ContainerGroups[conGroupIdx].Identities, "containerGroupName="+dm.ContainerGroups[conGroupIdx].ContainerGroupName)
+		// update the process limits of the containers
+		dm.UpdateProcessLimits(pod.Metadata["namespaceName"], pod.Metadata["podName"], pod.Annotations, dm.ContainerGroups[conGroupIdx].Containers)
 
-		for k, v := range pod.Labels {
-			if !kl.ContainsElement(dm.ContainerGroups[conGroupIdx].Labels, k+"="+v) {
-				dm.ContainerGroups[conGroupIdx].Labels = append(dm.ContainerGroups[conGroupIdx].Labels, k+"="+v)
-			}
+		// update the syscall allow-lists of the containers
+		dm.UpdateSyscallAllowList(pod.Metadata["namespaceName"], pod.Metadata["podName"], pod.Annotations, dm.ContainerGroups[conGroupIdx].Containers)
 
-			if kl.ContainsElement([]string{"controller-revision-hash", "pod-template-hash", "pod-template-generation"}, k) {
-				continue
-			}
+		// update the owning workload
+		dm.ContainerGroups[conGroupIdx].WorkloadKind = pod.Metadata["workloadKind"]
+		dm.ContainerGroups[conGroupIdx].WorkloadName = pod.Metadata["workloadName"]
 
-			if !kl.ContainsElement(dm.ContainerGroups[conGroupIdx].Identities, k+"="+v) {
-				dm.ContainerGroups[conGroupIdx].Identities = append(dm.ContainerGroups[conGroupIdx].Identities, k+"="+v)
-			}
+		// update the host namespaces shared with the pod
+		dm.ContainerGroups[conGroupIdx].HostPID = pod.HostPID
+		dm.ContainerGroups[conGroupIdx].HostNetwork = pod.HostNetwork
+
+		// update the workload of logs
+		if dm.EnableWorkloadEnrichment {
+			dm.LogFeeder.UpdateWorkload(action, dm.ContainerGroups[conGroupIdx])
 		}
 
 		// get security policies according to the updated identities
@@ -188,11 +219,38 @@ func (dm *KubeArmorDaemon) UpdateContainerGroupWithPod(action string, pod tp.K8s
 		// enforce security policies
 		dm.RuntimeEnforcer.UpdateSecurityPolicies(dm.ContainerGroups[conGroupIdx])
 	} else { // DELETED
+		// forget the label conflicts of the pod
+		delete(dm.LabelConflicts, pod.Metadata["namespaceName"]+"/"+pod.Metadata["podName"])
+
+		// update the workload of logs
+		if dm.EnableWorkloadEnrichment {
+			dm.LogFeeder.UpdateWorkload(action, tp.ContainerGroup{NamespaceName: pod.Metadata["namespaceName"], ContainerGroupName: pod.Metadata["podName"]})
+		}
+
 		// update security profiles
 		dm.RuntimeEnforcer.UpdateSecurityProfiles(action, pod)
 	}
 }
 
+// GetWorkload Function
+func GetWorkload(pod v1.Pod) (string, string) {
+	ownerRef := metav1.GetControllerOf(&pod)
+	if ownerRef == nil {
+		// bare pod
+		return "", ""
+	}
+
+	if ownerRef.Kind == "ReplicaSet" {
+		// a deployment names its replica sets as <deployment name>-<pod-template-hash>
+		if hash, ok := pod.ObjectMeta.Labels["pod-template-hash"]; ok && strings.HasSuffix(ownerRef.Name, "-"+hash) {
+			return "Deployment", strings.TrimSuffix(ownerRef.Name, "-"+hash)
+		}
+	}
+
+	// StatefulSet, DaemonSet, Job, ...
+	return ownerRef.Kind, ownerRef.Name
+}
+
 // WatchK8sPods Function
 func (dm *KubeArmorDaemon) WatchK8sPods() {
 	for {
@@ -220,6 +278,11 @@ func (dm *KubeArmorDaemon) WatchK8sPods() {
 				pod.Metadata["podName"] = event.Object.ObjectMeta.Name
 				pod.Metadata["generation"] = strconv.FormatInt(event.Object.Generation, 10)
 
+				pod.Metadata["workloadKind"], pod.Metadata["workloadName"] = GetWorkload(event.Object)
+
+				pod.HostPID = event.Object.Spec.HostPID
+				pod.HostNetwork = event.Object.Spec.HostNetwork
+
 				if event.Type == "ADDED" || event.Type == "MODIFIED" {
 					exist := false
 
@@ -463,7 +526,7 @@ func (dm *KubeArmorDaemon) UpdateSecurityPolicy(action string, secPolicy tp.Secu
 			} else if action == "DELETED" {
 				// remove the given policy from the security policy list of this container group
 				for idxP, policy := range conGroup.SecurityPolicies {
-					if reflect.DeepEqual(secPolicy, policy) {
+					if policy.Metadata["namespaceName"] == secPolicy.Metadata["namespaceName"] && policy.Metadata["policyName"] == secPolicy.Metadata["policyName"] {
 						dm.ContainerGroups[idx].SecurityPolicies = append(dm.ContainerGroups[idx].SecurityPolicies[:idxP], dm.ContainerGroups[idx].SecurityPolicies[idxP+1:]...)
 						break
 					}
@@ -490,6 +553,178 @@ func (dm *KubeArmorDaemon) UpdateSecurityPolicy(action string, secPolicy tp.Secu
 	}
 }
 
+// CanonicalizeMatchPaths Function
+func CanonicalizeMatchPaths(process *tp.ProcessType, file *tp.FileType) error {
+	for idx, matchPath := range process.MatchPaths {
+		path, err := kl.GetCanonicalPath(matchPath.Path)
+		if err != nil {
+			return fmt.Errorf("invalid process.matchPaths (%s)", err.Error())
+		}
+		process.MatchPaths[idx].Path = path
+
+		if matchPath.SHA256 != "" {
+			if hash, err := hex.DecodeString(matchPath.SHA256); err != nil || len(hash) != sha256.Size {
+				return fmt.Errorf("invalid process.matchPaths.sha256 (%s)", matchPath.SHA256)
+			}
+			process.MatchPaths[idx].SHA256 = strings.ToLower(matchPath.SHA256)
+		}
+
+		for idxS, src := range matchPath.FromSource {
+			if src.Path == "" {
+				continue
+			}
+
+			path, err := kl.GetCanonicalPath(src.Path)
+			if err != nil {
+				return fmt.Errorf("invalid process.matchPaths.fromSource (%s)", err.Error())
+			}
+			process.MatchPaths[idx].FromSource[idxS].Path = path
+		}
+	}
+
+	for idx, matchPath := range file.MatchPaths {
+		path, err := kl.GetCanonicalPath(matchPath.Path)
+		if err != nil {
+			return fmt.Errorf("invalid file.matchPaths (%s)", err.Error())
+		}
+		file.MatchPaths[idx].Path = path
+
+		for idxS, src := range matchPath.FromSource {
+			if src.Path == "" {
+				continue
+			}
+
+			path, err := kl.GetCanonicalPath(src.Path)
+			if err != nil {
+				return fmt.Errorf("invalid file.matchPaths.fromSource (%s)", err.Error())
+			}
+			file.MatchPaths[idx].FromSource[idxS].Path = path
+		}
+	}
+
+	return nil
+}
+
+// mitreTechniquePattern for the IDs of MITRE ATT&CK (sub-)techniques
+var mitreTechniquePattern = regexp.MustCompile(`^T[0-9]{4}(\.[0-9]{3})?$`)
+
+// ValidateMitreTechniques Function
+func ValidateMitreTechniques(techniques []string) error {
+	for idx, technique := range techniques {
+		// e.g., T1059 or T1059.004
+		technique = strings.ToUpper(technique)
+		if !mitreTechniquePattern.MatchString(technique) {
+			return fmt.Errorf("invalid mitreTechniques (%s)", techniques[idx])
+		}
+		techniques[idx] = technique
+	}
+
+	return nil
+}
+
+// UpdateSecurityPolicyList Function
+func (dm *KubeArmorDaemon) UpdateSecurityPolicyList(event tp.K8sKubeArmorPolicyEvent) (tp.SecurityPolicy, bool, error) {
+	dm.SecurityPoliciesLock.Lock()
+	defer dm.SecurityPoliciesLock.Unlock()
+
+	// create a security policy
+
+	secPolicy := tp.SecurityPolicy{}
+
+	secPolicy.Metadata = map[string]string{}
+	secPolicy.Metadata["namespaceName"] = event.Object.Metadata.Namespace
+	secPolicy.Metadata["policyName"] = event.Object.Metadata.Name
+	secPolicy.Metadata["generation"] = strconv.FormatInt(event.Object.Metadata.Generation, 10)
+
+	kl.Clone(event.Object.Spec, &secPolicy.Spec)
+
+	if event.Type != "DELETED" {
+		if err := CanonicalizeMatchPaths(&secPolicy.Spec.Process, &secPolicy.Spec.File); err != nil {
+			return secPolicy, false, err
+		}
+
+		if err := ValidateMitreTechniques(secPolicy.Spec.MitreTechniques); err != nil {
+			return secPolicy, false, err
+		}
+	}
+
+	kl.ObjCommaExpandFirstDupOthers(&secPolicy.Spec.Network.MatchProtocols)
+	kl.ObjCommaExpandFirstDupOthers(&secPolicy.Spec.Capabilities.MatchCapabilities)
+
+	switch secPolicy.Spec.Action {
+	case "allow":
+		secPolicy.Spec.Action = "Allow"
+	case "block":
+		secPolicy.Spec.Action = "Block"
+	case "audit":
+		secPolicy.Spec.Action = "Audit"
+	case "allowwithaudit":
+		secPolicy.Spec.Action = "AllowWithAudit"
+	case "blockwithaudit":
+		secPolicy.Spec.Action = "BlockWithAudit"
+	case "quarantine":
+		secPolicy.Spec.Action = "Quarantine"
+	}
+
+	// add identities
+
+	secPolicy.Spec.Selector.Identities = append(secPolicy.Spec.Selector.Identities, "namespaceName="+event.Object.Metadata.Namespace)
+
+	for k, v := range secPolicy.Spec.Selector.MatchNames {
+		if kl.ContainsElement([]string{"containerGroupName", "containerName", "hostName", "imageName"}, k) {
+			secPolicy.Spec.Selector.Identities = append(secPolicy.Spec.Selector.Identities, k+"="+v)
+		}
+	}
+
+	for k, v := range secPolicy.Spec.Selector.MatchLabels {
+		if !kl.ContainsElement(secPolicy.Spec.Selector.Identities, k+"="+v) {
+			secPolicy.Spec.Selector.Identities = append(secPolicy.Spec.Selector.Identities, k+"="+v)
+		}
+	}
+
+	// skip the policy if the same spec is already loaded (e.g., resync)
+
+	secPolicy.Metadata["specHash"] = kl.GetSpecHash(event.Object.Spec)
+
+	if event.Type == "ADDED" || event.Type == "MODIFIED" {
+		for _, policy := range dm.SecurityPolicies {
+			if policy.Metadata["policyName"] == secPolicy.Metadata["policyName"] &&
+				policy.Metadata["namespaceName"] == secPolicy.Metadata["namespaceName"] &&
+				policy.Metadata["specHash"] == secPolicy.Metadata["specHash"] {
+				return secPolicy, false, nil
+			}
+		}
+	}
+
+	// update a security policy into the policy list
+
+	if event.Type == "ADDED" {
+		if !kl.ContainsElement(dm.SecurityPolicies, secPolicy) {
+			dm.SecurityPolicies = append(dm.SecurityPolicies, secPolicy)
+		}
+	} else if event.Type == "DELETED" {
+		for idx, policy := range dm.SecurityPolicies {
+			if policy.Metadata["namespaceName"] == secPolicy.Metadata["namespaceName"] && policy.Metadata["policyName"] == secPolicy.Metadata["policyName"] {
+				dm.SecurityPolicies = append(dm.SecurityPolicies[:idx], dm.SecurityPolicies[idx+1:]...)
+				break
+			}
+		}
+	} else { // MODIFIED
+		targetIdx := -1
+		for idx, policy := range dm.SecurityPolicies {
+			if policy.Metadata["namespaceName"] == secPolicy.Metadata["namespaceName"] && policy.Metadata["policyName"] == secPolicy.Metadata["policyName"] {
+				targetIdx = idx
+				break
+			}
+		}
+		if targetIdx != -1 {
+			dm.SecurityPolicies[targetIdx] = secPolicy
+		}
+	}
+
+	return secPolicy, true, nil
+}
+
 // WatchSecurityPolicies Function
 func (dm *KubeArmorDaemon) WatchSecurityPolicies() {
 	for {
@@ -512,96 +747,15 @@ func (dm *KubeArmorDaemon) WatchSecurityPolicies() {
 					break
 				}
 
-				dm.SecurityPoliciesLock.Lock()
-
-				// create a security policy
-
-				secPolicy := tp.SecurityPolicy{}
-
-				secPolicy.Metadata = map[string]string{}
-				secPolicy.Metadata["namespaceName"] = event.Object.Metadata.Namespace
-				secPolicy.Metadata["policyName"] = event.Object.Metadata.Name
-				secPolicy.Metadata["generation"] = strconv.FormatInt(event.Object.Metadata.Generation, 10)
-
-				if event.Type == "ADDED" || event.Type == "MODIFIED" {
-					exist := false
-					for _, policy := range dm.SecurityPolicies {
-						if policy.Metadata["policyName"] == secPolicy.Metadata["policyName"] &&
-							policy.Metadata["namespaceName"] == secPolicy.Metadata["namespaceName"] &&
-							policy.Metadata["generation"] == secPolicy.Metadata["generation"] {
-							exist = true
-							break
-						}
-					}
-
-					if exist {
-						dm.SecurityPoliciesLock.Unlock()
-						continue
-					}
-				}
-
-				kl.Clone(event.Object.Spec, &secPolicy.Spec)
-
-				kl.ObjCommaExpandFirstDupOthers(&secPolicy.Spec.Network.MatchProtocols)
-				kl.ObjCommaExpandFirstDupOthers(&secPolicy.Spec.Capabilities.MatchCapabilities)
-
-				switch secPolicy.Spec.Action {
-				case "allow":
-					secPolicy.Spec.Action = "Allow"
-				case "block":
-					secPolicy.Spec.Action = "Block"
-				case "audit":
-					secPolicy.Spec.Action = "Audit"
-				case "allowwithaudit":
-					secPolicy.Spec.Action = "AllowWithAudit"
-				case "blockwithaudit":
-					secPolicy.Spec.Action = "BlockWithAudit"
-				}
-
-				// add identities
-
-				secPolicy.Spec.Selector.Identities = append(secPolicy.Spec.Selector.Identities, "namespaceName="+event.Object.Metadata.Namespace)
-
-				for k, v := range secPolicy.Spec.Selector.MatchNames {
-					if kl.ContainsElement([]string{"containerGroupName", "containerName", "hostName", "imageName"}, k) {
-						secPolicy.Spec.Selector.Identities = append(secPolicy.Spec.Selector.Identities, k+"="+v)
-					}
-				}
-
-				for k, v := range secPolicy.Spec.Selector.MatchLabels {
-					if !kl.ContainsElement(secPolicy.Spec.Selector.Identities, k+"="+v) {
-						secPolicy.Spec.Selector.Identities = append(secPolicy.Spec.Selector.Identities, k+"="+v)
-					}
-				}
-
-				// update a security policy into the policy list
-
-				if event.Type == "ADDED" {
-					if !kl.ContainsElement(dm.SecurityPolicies, secPolicy) {
-						dm.SecurityPolicies = append(dm.SecurityPolicies, secPolicy)
-					}
-				} else if event.Type == "DELETED" {
-					for idx, policy := range dm.SecurityPolicies {
-						if reflect.DeepEqual(secPolicy, policy) {
-							dm.SecurityPolicies = append(dm.SecurityPolicies[:idx], dm.SecurityPolicies[idx+1:]...)
-							break
-						}
-					}
-				} else { // MODIFIED
-					targetIdx := -1
-					for idx, policy := range dm.SecurityPolicies {
-						if policy.Metadata["namespaceName"] == secPolicy.Metadata["namespaceName"] && policy.Metadata["policyName"] == secPolicy.Metadata["policyName"] {
-							targetIdx = idx
-							break
-						}
-					}
-					if targetIdx != -1 {
-						dm.SecurityPolicies[targetIdx] = secPolicy
-					}
+				secPolicy, updated, err := dm.UpdateSecurityPolicyList(event)
+				if err != nil {
+					dm.LogFeeder.Errf("Rejected a Security Policy (%s/%s/%s, %s)", strings.ToLower(event.Type), secPolicy.Metadata["namespaceName"], secPolicy.Metadata["policyName"], err.Error())
+					continue
+				} else if !updated {
+					dm.LogFeeder.Debugf("Skipped an unchanged Security Policy (%s/%s/%s)", strings.ToLower(event.Type), secPolicy.Metadata["namespaceName"], secPolicy.Metadata["policyName"])
+					continue
 				}
 
-				dm.SecurityPoliciesLock.Unlock()
-
 				dm.LogFeeder.Printf("Detected a Security Policy (%s/%s/%s)", strings.ToLower(event.Type), secPolicy.Metadata["namespaceName"], secPolicy.Metadata["policyName"])
 
 				// apply security policies to containers
@@ -622,7 +776,8 @@ func (dm *KubeArmorDaemon) UpdateHostSecurityPolicy() {
 	secPolicies := []tp.HostSecurityPolicy{}
 
 	for _, policy := range dm.HostSecurityPolicies {
-		if kl.MatchIdentities(policy.Spec.NodeSelector.Identities, nodeIdentities) {
+		// match names may have patterns (e.g., hostName=node-[001-100])
+		if MatchNodeSelector(policy.Spec.NodeSelector.Identities, nodeIdentities) {
 			secPolicies = append(secPolicies, policy)
 		}
 	}
@@ -634,6 +789,109 @@ func (dm *KubeArmorDaemon) UpdateHostSecurityPolicy() {
 	dm.RuntimeEnforcer.UpdateHostSecurityPolicies(secPolicies)
 }
 
+// UpdateHostSecurityPolicyList Function
+func (dm *KubeArmorDaemon) UpdateHostSecurityPolicyList(event tp.K8sKubeArmorHostPolicyEvent) (tp.HostSecurityPolicy, bool, error) {
+	dm.HostSecurityPoliciesLock.Lock()
+	defer dm.HostSecurityPoliciesLock.Unlock()
+
+	// create a host security policy
+
+	secPolicy := tp.HostSecurityPolicy{}
+
+	secPolicy.Metadata = map[string]string{}
+	secPolicy.Metadata["policyName"] = event.Object.Metadata.Name
+	secPolicy.Metadata["generation"] = strconv.FormatInt(event.Object.Metadata.Generation, 10)
+
+	kl.Clone(event.Object.Spec, &secPolicy.Spec)
+
+	if event.Type != "DELETED" {
+		if err := CanonicalizeMatchPaths(&secPolicy.Spec.Process, &secPolicy.Spec.File); err != nil {
+			return secPolicy, false, err
+		}
+
+		if err := ValidateMitreTechniques(secPolicy.Spec.MitreTechniques); err != nil {
+			return secPolicy, false, err
+		}
+
+		if err := ValidateNodeSelector(secPolicy.Spec.NodeSelector); err != nil {
+			return secPolicy, false, err
+		}
+	}
+
+	kl.ObjCommaExpandFirstDupOthers(&secPolicy.Spec.Network.MatchProtocols)
+	kl.ObjCommaExpandFirstDupOthers(&secPolicy.Spec.Capabilities.MatchCapabilities)
+
+	switch secPolicy.Spec.Action {
+	case "allow":
+		secPolicy.Spec.Action = "Allow"
+	case "block":
+		secPolicy.Spec.Action = "Block"
+	case "audit":
+		secPolicy.Spec.Action = "Audit"
+	case "allowwithaudit":
+		secPolicy.Spec.Action = "AllowWithAudit"
+	case "blockwithaudit":
+		secPolicy.Spec.Action = "BlockWithAudit"
+	case "quarantine":
+		secPolicy.Spec.Action = "Quarantine"
+	}
+
+	// add identities (match names may have patterns resolved against node identities later)
+
+	for k, v := range secPolicy.Spec.NodeSelector.MatchNames {
+		if kl.ContainsElement([]string{"hostName", "architecture", "osType", "osName", "osVersion", "kernelVersion", "runtimePlatform"}, k) {
+			secPolicy.Spec.NodeSelector.Identities = append(secPolicy.Spec.NodeSelector.Identities, k+"="+v)
+		}
+	}
+
+	for k, v := range secPolicy.Spec.NodeSelector.MatchLabels {
+		if !kl.ContainsElement(secPolicy.Spec.NodeSelector.Identities, k+"="+v) {
+			secPolicy.Spec.NodeSelector.Identities = append(secPolicy.Spec.NodeSelector.Identities, k+"="+v)
+		}
+	}
+
+	// skip the policy if the same spec is already loaded (e.g., resync)
+
+	secPolicy.Metadata["specHash"] = kl.GetSpecHash(event.Object.Spec)
+
+	if event.Type == "ADDED" || event.Type == "MODIFIED" {
+		for _, policy := range dm.HostSecurityPolicies {
+			if policy.Metadata["policyName"] == secPolicy.Metadata["policyName"] &&
+				policy.Metadata["specHash"] == secPolicy.Metadata["specHash"] {
+				return secPolicy, false, nil
+			}
+		}
+	}
+
+	// update a security policy into the policy list
+
+	if event.Type == "ADDED" {
+		if !kl.ContainsElement(dm.HostSecurityPolicies, secPolicy) {
+			dm.HostSecurityPolicies = append(dm.HostSecurityPolicies, secPolicy)
+		}
+	} else if event.Type == "DELETED" {
+		for idx, policy := range dm.HostSecurityPolicies {
+			if policy.Metadata["policyName"] == secPolicy.Metadata["policyName"] {
+				dm.HostSecurityPolicies = append(dm.HostSecurityPolicies[:idx], dm.HostSecurityPolicies[idx+1:]...)
+				break
+			}
+		}
+	} else { // MODIFIED
+		targetIdx := -1
+		for idx, policy := range dm.HostSecurityPolicies {
+			if policy.Metadata["policyName"] == secPolicy.Metadata["policyName"] {
+				targetIdx = idx
+				break
+			}
+		}
+		if targetIdx != -1 {
+			dm.HostSecurityPolicies[targetIdx] = secPolicy
+		}
+	}
+
+	return secPolicy, true, nil
+}
+
 // WatchHostSecurityPolicies Function
 func (dm *KubeArmorDaemon) WatchHostSecurityPolicies() {
 	for {
@@ -660,92 +918,15 @@ func (dm *KubeArmorDaemon) WatchHostSecurityPolicies() {
 					continue
 				}
 
-				dm.HostSecurityPoliciesLock.Lock()
-
-				// create a host security policy
-
-				secPolicy := tp.HostSecurityPolicy{}
-
-				secPolicy.Metadata = map[string]string{}
-				secPolicy.Metadata["policyName"] = event.Object.Metadata.Name
-				secPolicy.Metadata["generation"] = strconv.FormatInt(event.Object.Metadata.Generation, 10)
-
-				if event.Type == "ADDED" || event.Type == "MODIFIED" {
-					exist := false
-					for _, policy := range dm.HostSecurityPolicies {
-						if policy.Metadata["policyName"] == secPolicy.Metadata["policyName"] &&
-							policy.Metadata["generation"] == secPolicy.Metadata["generation"] {
-							exist = true
-							break
-						}
-					}
-
-					if exist {
-						dm.HostSecurityPoliciesLock.Unlock()
-						continue
-					}
-				}
-
-				kl.Clone(event.Object.Spec, &secPolicy.Spec)
-
-				kl.ObjCommaExpandFirstDupOthers(&secPolicy.Spec.Network.MatchProtocols)
-				kl.ObjCommaExpandFirstDupOthers(&secPolicy.Spec.Capabilities.MatchCapabilities)
-
-				switch secPolicy.Spec.Action {
-				case "allow":
-					secPolicy.Spec.Action = "Allow"
-				case "block":
-					secPolicy.Spec.Action = "Block"
-				case "audit":
-					secPolicy.Spec.Action = "Audit"
-				case "allowwithaudit":
-					secPolicy.Spec.Action = "AllowWithAudit"
-				case "blockwithaudit":
-					secPolicy.Spec.Action = "BlockWithAudit"
-				}
-
-				// add identities
-
-				for k, v := range secPolicy.Spec.NodeSelector.MatchNames {
-					if kl.ContainsElement([]string{"hostName", "architecture", "osType", "osName", "osVersion", "kernelVersion", "runtimePlatform"}, k) {
-						secPolicy.Spec.NodeSelector.Identities = append(secPolicy.Spec.NodeSelector.Identities, k+"="+v)
-					}
-				}
-
-				for k, v := range secPolicy.Spec.NodeSelector.MatchLabels {
-					if !kl.ContainsElement(secPolicy.Spec.NodeSelector.Identities, k+"="+v) {
-						secPolicy.Spec.NodeSelector.Identities = append(secPolicy.Spec.NodeSelector.Identities, k+"="+v)
-					}
-				}
-
-				// update a security policy into the policy list
-
-				if event.Type == "ADDED" {
-					if !kl.ContainsElement(dm.HostSecurityPolicies, secPolicy) {
-						dm.HostSecurityPolicies = append(dm.HostSecurityPolicies, secPolicy)
-					}
-				} else if event.Type == "DELETED" {
-					for idx, policy := range dm.HostSecurityPolicies {
-						if reflect.DeepEqual(secPolicy, policy) {
-							dm.HostSecurityPolicies = append(dm.HostSecurityPolicies[:idx], dm.HostSecurityPolicies[idx+1:]...)
-							break
-						}
-					}
-				} else { // MODIFIED
-					targetIdx := -1
-					for idx, policy := range dm.HostSecurityPolicies {
-						if policy.Metadata["policyName"] == secPolicy.Metadata["policyName"] {
-							targetIdx = idx
-							break
-						}
-					}
-					if targetIdx != -1 {
-						dm.HostSecurityPolicies[targetIdx] = secPolicy
-					}
+				secPolicy, updated, err := dm.UpdateHostSecurityPolicyList(event)
+				if err != nil {
+					dm.LogFeeder.Errf("Rejected a Host Security Policy (%s/%s, %s)", strings.ToLower(event.Type), secPolicy.Metadata["policyName"], err.Error())
+					continue
+				} else if !updated {
+					dm.LogFeeder.Debugf("Skipped an unchanged Host Security Policy (%s/%s)", strings.ToLower(event.Type), secPolicy.Metadata["policyName"])
+					continue
 				}
 
-				dm.HostSecurityPoliciesLock.Unlock()
-
 				dm.LogFeeder.Printf("Detected a Host Security Policy (%s/%s)", strings.ToLower(event.Type), secPolicy.Metadata["policyName"])
 
 				// apply security policies to a host
@@ -0,0 +1,319 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	efc "github.com/accuknox/KubeArmor/KubeArmor/enforcer"
+	fd "github.com/accuknox/KubeArmor/KubeArmor/feeder"
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+func TestUpdateSecurityPolicyList(t *testing.T) {
+	dm := NewKubeArmorDaemon(false, false, false, false, false, false, false)
+
+	// create a policy event
+
+	event := tp.K8sKubeArmorPolicyEvent{Type: "ADDED"}
+	event.Object.Metadata.Namespace = "multiubuntu"
+	event.Object.Metadata.Name = "ksp-ubuntu-1-proc-path-block"
+	event.Object.Metadata.Generation = 1
+	event.Object.Spec.Selector.MatchLabels = map[string]string{"container": "ubuntu-1", "group": "group-1"}
+	event.Object.Spec.Process.MatchPaths = []tp.ProcessPathType{{Path: "/bin/sleep"}}
+	event.Object.Spec.Action = "Block"
+
+	// add the policy
+
+	if _, updated, _ := dm.UpdateSecurityPolicyList(event); !updated || len(dm.SecurityPolicies) != 1 {
+		t.Errorf("[FAIL] Failed to add a security policy")
+		return
+	}
+
+	t.Log("[PASS] Added a security policy")
+
+	// re-apply the same policy (resync)
+
+	for _, eventType := range []string{"ADDED", "MODIFIED"} {
+		event.Type = eventType
+
+		if _, updated, _ := dm.UpdateSecurityPolicyList(event); updated {
+			t.Errorf("[FAIL] Recomputed an unchanged security policy (%s)", eventType)
+			return
+		}
+	}
+
+	if len(dm.SecurityPolicies) != 1 {
+		t.Errorf("[FAIL] Duplicated an unchanged security policy (%d)", len(dm.SecurityPolicies))
+		return
+	}
+
+	t.Log("[PASS] Skipped an unchanged security policy")
+
+	// modify the policy
+
+	event.Type = "MODIFIED"
+	event.Object.Metadata.Generation = 2
+	event.Object.Spec.Process.MatchPaths = []tp.ProcessPathType{{Path: "/bin/sleep"}, {Path: "/usr/bin/wc"}}
+
+	if _, updated, _ := dm.UpdateSecurityPolicyList(event); !updated {
+		t.Errorf("[FAIL] Failed to update a modified security policy")
+		return
+	}
+
+	if len(dm.SecurityPolicies) != 1 || len(dm.SecurityPolicies[0].Spec.Process.MatchPaths) != 2 {
+		t.Errorf("[FAIL] Failed to replace the loaded security policy (%v)", dm.SecurityPolicies)
+		return
+	}
+
+	t.Log("[PASS] Updated a modified security policy")
+
+	// delete the policy
+
+	event.Type = "DELETED"
+
+	if _, updated, _ := dm.UpdateSecurityPolicyList(event); !updated || len(dm.SecurityPolicies) != 0 {
+		t.Errorf("[FAIL] Failed to delete a security policy")
+		return
+	}
+
+	t.Log("[PASS] Deleted a security policy")
+}
+
+func TestUpdateHostSecurityPolicyList(t *testing.T) {
+	dm := NewKubeArmorDaemon(false, true, false, false, false, false, false)
+
+	// create a host policy event
+
+	event := tp.K8sKubeArmorHostPolicyEvent{Type: "ADDED"}
+	event.Object.Metadata.Name = "hsp-kubearmor-dev-proc-path-block"
+	event.Object.Metadata.Generation = 1
+	event.Object.Spec.NodeSelector.MatchNames = map[string]string{"hostName": "kubearmor-dev", "osType": "linux"}
+	event.Object.Spec.Process.MatchPaths = []tp.ProcessPathType{{Path: "/usr/bin/diff"}}
+	event.Object.Spec.Action = "Block"
+
+	// add the policy
+
+	if _, updated, _ := dm.UpdateHostSecurityPolicyList(event); !updated || len(dm.HostSecurityPolicies) != 1 {
+		t.Errorf("[FAIL] Failed to add a host security policy")
+		return
+	}
+
+	t.Log("[PASS] Added a host security policy")
+
+	// re-apply the same policy (resync)
+
+	event.Type = "MODIFIED"
+
+	if _, updated, _ := dm.UpdateHostSecurityPolicyList(event); updated {
+		t.Errorf("[FAIL] Recomputed an unchanged host security policy")
+		return
+	}
+
+	t.Log("[PASS] Skipped an unchanged host security policy")
+
+	// modify the policy
+
+	event.Object.Spec.Action = "Audit"
+
+	if _, updated, _ := dm.UpdateHostSecurityPolicyList(event); !updated || dm.HostSecurityPolicies[0].Spec.Action != "Audit" {
+		t.Errorf("[FAIL] Failed to update a modified host security policy")
+		return
+	}
+
+	t.Log("[PASS] Updated a modified host security policy")
+}
+
+func TestCanonicalizeMatchPaths(t *testing.T) {
+	// dirty paths
+
+	process := tp.ProcessType{MatchPaths: []tp.ProcessPathType{{Path: "/usr//bin/./sh", FromSource: []tp.MatchSourceType{{Path: "/bin/../bin/bash"}}}}}
+	file := tp.FileType{MatchPaths: []tp.FilePathType{{Path: "/etc/ssh/../passwd"}}}
+
+	if err := CanonicalizeMatchPaths(&process, &file); err != nil {
+		t.Errorf("[FAIL] Failed to canonicalize paths (%s)", err.Error())
+		return
+	}
+
+	if process.MatchPaths[0].Path != "/usr/bin/sh" || process.MatchPaths[0].FromSource[0].Path != "/bin/bash" || file.MatchPaths[0].Path != "/etc/passwd" {
+		t.Errorf("[FAIL] Failed to normalize paths (%s, %s, %s)", process.MatchPaths[0].Path, process.MatchPaths[0].FromSource[0].Path, file.MatchPaths[0].Path)
+		return
+	}
+
+	t.Log("[PASS] Normalized dirty paths")
+
+	// relative paths
+
+	process = tp.ProcessType{MatchPaths: []tp.ProcessPathType{{Path: "bin/sh"}}}
+	file = tp.FileType{}
+
+	if err := CanonicalizeMatchPaths(&process, &file); err == nil || !strings.Contains(err.Error(), "not an absolute path") {
+		t.Errorf("[FAIL] Accepted a relative path in process.matchPaths")
+		return
+	}
+
+	process = tp.ProcessType{}
+	file = tp.FileType{MatchPaths: []tp.FilePathType{{Path: "/etc/passwd", FromSource: []tp.MatchSourceType{{Path: "./cat"}}}}}
+
+	if err := CanonicalizeMatchPaths(&process, &file); err == nil {
+		t.Errorf("[FAIL] Accepted a relative path in file.matchPaths.fromSource")
+		return
+	}
+
+	t.Log("[PASS] Rejected relative paths")
+
+	// pinned hashes
+
+	file = tp.FileType{}
+	process = tp.ProcessType{MatchPaths: []tp.ProcessPathType{{Path: "/bin/sleep", SHA256: "E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855"}}}
+
+	if err := CanonicalizeMatchPaths(&process, &file); err != nil || process.MatchPaths[0].SHA256 != "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+		t.Errorf("[FAIL] Failed to normalize a pinned hash (%s)", process.MatchPaths[0].SHA256)
+		return
+	}
+
+	process = tp.ProcessType{MatchPaths: []tp.ProcessPathType{{Path: "/bin/sleep", SHA256: "e3b0c442"}}}
+
+	if err := CanonicalizeMatchPaths(&process, &file); err == nil {
+		t.Errorf("[FAIL] Accepted an invalid pinned hash")
+		return
+	}
+
+	t.Log("[PASS] Checked pinned hashes")
+
+	// reject a policy with a relative path
+
+	dm := NewKubeArmorDaemon(false, false, false, false, false, false, false)
+
+	event := tp.K8sKubeArmorPolicyEvent{Type: "ADDED"}
+	event.Object.Metadata.Namespace = "multiubuntu"
+	event.Object.Metadata.Name = "ksp-ubuntu-1-file-path-block"
+	event.Object.Spec.File.MatchPaths = []tp.FilePathType{{Path: "etc/passwd"}}
+	event.Object.Spec.Action = "Block"
+
+	if _, _, err := dm.UpdateSecurityPolicyList(event); err == nil || len(dm.SecurityPolicies) != 0 {
+		t.Errorf("[FAIL] Loaded a security policy with a relative path")
+		return
+	}
+
+	t.Log("[PASS] Rejected a security policy with a relative path")
+}
+
+func TestGetWorkload(t *testing.T) {
+	isController := true
+
+	// a deployment-owned pod
+
+	pod := v1.Pod{}
+	pod.ObjectMeta.Name = "ubuntu-1-6d5c8f5b9-x2xzq"
+	pod.ObjectMeta.Labels = map[string]string{"container": "ubuntu-1", "pod-template-hash": "6d5c8f5b9"}
+	pod.ObjectMeta.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "ubuntu-1-6d5c8f5b9", Controller: &isController}}
+
+	if kind, name := GetWorkload(pod); kind != "Deployment" || name != "ubuntu-1" {
+		t.Errorf("[FAIL] Failed to get the deployment of a pod (%s/%s)", kind, name)
+		return
+	}
+
+	t.Log("[PASS] Got the deployment of a pod")
+
+	// a daemonset-owned pod
+
+	pod = v1.Pod{}
+	pod.ObjectMeta.Name = "kubearmor-7tzxl"
+	pod.ObjectMeta.Labels = map[string]string{"kubearmor-app": "kubearmor", "controller-revision-hash": "5c9f8d8b4"}
+	pod.ObjectMeta.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "kubearmor", Controller: &isController}}
+
+	if kind, name := GetWorkload(pod); kind != "DaemonSet" || name != "kubearmor" {
+		t.Errorf("[FAIL] Failed to get the daemonset of a pod (%s/%s)", kind, name)
+		return
+	}
+
+	t.Log("[PASS] Got the daemonset of a pod")
+
+	// a pod with an owner which is not its controller
+
+	isController = false
+
+	pod = v1.Pod{}
+	pod.ObjectMeta.Name = "ubuntu-owned"
+	pod.ObjectMeta.OwnerReferences = []metav1.OwnerReference{{Kind: "ConfigMap", Name: "ubuntu-config", Controller: &isController}, {Kind: "Job", Name: "ubuntu-job"}}
+
+	if kind, name := GetWorkload(pod); kind != "" || name != "" {
+		t.Errorf("[FAIL] Got a non-controller owner as the workload of a pod (%s/%s)", kind, name)
+		return
+	}
+
+	t.Log("[PASS] Skipped the owners which are not controllers")
+
+	// a bare pod
+
+	pod = v1.Pod{}
+	pod.ObjectMeta.Name = "ubuntu-bare"
+
+	if kind, name := GetWorkload(pod); kind != "" || name != "" {
+		t.Errorf("[FAIL] Got the workload of a bare pod (%s/%s)", kind, name)
+		return
+	}
+
+	t.Log("[PASS] Got no workload for a bare pod")
+}
+
+func TestUpdateContainerGroupWithPod(t *testing.T) {
+	dm := NewKubeArmorDaemon(false, false, false, true, false, false, false)
+
+	dm.LogFeeder = fd.NewFeeder("0", "none", false)
+	if dm.LogFeeder == nil {
+		t.Error("[FAIL] Failed to create Feeder")
+		return
+	}
+	defer dm.LogFeeder.DestroyFeeder()
+
+	// no enforcers
+	dm.RuntimeEnforcer = &efc.RuntimeEnforcer{}
+
+	pod := tp.K8sPod{
+		Metadata:    map[string]string{"namespaceName": "multiubuntu", "podName": "ubuntu-1-6d5c8f5b9-x2xzq", "workloadKind": "Deployment", "workloadName": "ubuntu-1"},
+		Annotations: map[string]string{},
+		Labels:      map[string]string{"container": "ubuntu-1"},
+	}
+
+	dm.UpdateContainerGroupWithPod("ADDED", pod)
+
+	if workload := dm.LogFeeder.GetWorkload("multiubuntu", "ubuntu-1-6d5c8f5b9-x2xzq"); workload != "Deployment/ubuntu-1" {
+		t.Errorf("[FAIL] Failed to set the workload of an added pod (%s)", workload)
+		return
+	}
+
+	t.Log("[PASS] Set the workload of an added pod")
+
+	dm.UpdateContainerGroupWithPod("DELETED", pod)
+
+	if workload := dm.LogFeeder.GetWorkload("multiubuntu", "ubuntu-1-6d5c8f5b9-x2xzq"); workload != "" {
+		t.Errorf("[FAIL] Kept the workload of a deleted pod (%s)", workload)
+		return
+	}
+
+	t.Log("[PASS] Removed the workload of a deleted pod")
+}
+
+func TestValidateMitreTechniques(t *testing.T) {
+	techniques := []string{"T1059", "t1059.004"}
+
+	if err := ValidateMitreTechniques(techniques); err != nil || techniques[1] != "T1059.004" {
+		t.Errorf("[FAIL] Failed to validate MITRE techniques (%v, %v)", techniques, err)
+		return
+	}
+
+	t.Log("[PASS] Validated MITRE techniques")
+
+	for _, technique := range []string{"1059", "T105", "T1059.4", "TA0002", "T1059.004.001"} {
+		if err := ValidateMitreTechniques([]string{technique}); err == nil {
+			t.Errorf("[FAIL] Accepted an invalid MITRE technique (%s)", technique)
+			return
+		}
+	}
+
+	t.Log("[PASS] Rejected invalid MITRE techniques")
+}
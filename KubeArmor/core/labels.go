@@ -0,0 +1,275 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	kl "github.com/accuknox/KubeArmor/KubeArmor/common"
+	kg "github.com/accuknox/KubeArmor/KubeArmor/log"
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+// ================= //
+// == Label Merge == //
+// ================= //
+
+const (
+	// LabelSourcePod for the labels of a pod
+	LabelSourcePod = "pod"
+
+	// LabelSourceAnnotation for the annotations of a pod
+	LabelSourceAnnotation = "annotation"
+
+	// LabelSourceRuntime for the labels given by a container runtime
+	LabelSourceRuntime = "runtime"
+)
+
+// ignoredAnnotations for the annotations not describing a workload
+var ignoredAnnotations = []string{"kubectl.kubernetes.io/last-applied-configuration"}
+
+// perContainerLabelPrefixes for the runtime labels set differently for each container of a pod
+var perContainerLabelPrefixes = []string{"io.kubernetes.", "annotation.io.kubernetes.", "io.cri-containerd."}
+
+// ignoredIdentityKeys for the labels changed on every rollout
+var ignoredIdentityKeys = []string{"controller-revision-hash", "pod-template-hash", "pod-template-generation"}
+
+// LabelSource Structure
+type LabelSource struct {
+	Source string
+	Labels map[string]string
+}
+
+// LabelConflict Structure
+type LabelConflict struct {
+	Key string
+
+	Source string
+	Value  string
+
+	IgnoredSource string
+	IgnoredValue  string
+
+	// neither value is kept (runtime labels differing across containers)
+	Dropped bool
+}
+
+// String Function
+func (conflict LabelConflict) String() string {
+	if conflict.Dropped {
+		return fmt.Sprintf("%s: dropped %s from %s and %s from %s", conflict.Key, conflict.Value, conflict.Source, conflict.IgnoredValue, conflict.IgnoredSource)
+	}
+
+	return fmt.Sprintf("%s: %s from %s, ignored %s from %s", conflict.Key, conflict.Value, conflict.Source, conflict.IgnoredValue, conflict.IgnoredSource)
+}
+
+// ParseLabels Function
+func ParseLabels(labels []string) map[string]string {
+	parsed := map[string]string{}
+
+	for _, label := range labels {
+		if kv := strings.SplitN(label, "=", 2); len(kv) == 2 {
+			parsed[kv[0]] = kv[1]
+		} else {
+			parsed[label] = ""
+		}
+	}
+
+	return parsed
+}
+
+// sortedKeys Function
+func sortedKeys(labels map[string]string) []string {
+	keys := []string{}
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// MergeLabels Function
+func MergeLabels(sources ...LabelSource) ([]string, []LabelConflict) {
+	// sources are given in the order of precedence (the first one wins)
+
+	merged := map[string]string{}
+	owners := map[string]string{}
+
+	conflicts := []LabelConflict{}
+
+	for _, source := range sources {
+		for _, k := range sortedKeys(source.Labels) {
+			v := source.Labels[k]
+
+			if val, ok := merged[k]; ok {
+				if val != v {
+					conflicts = append(conflicts, LabelConflict{Key: k, Source: owners[k], Value: val, IgnoredSource: source.Source, IgnoredValue: v})
+				}
+				continue
+			}
+
+			merged[k] = v
+			owners[k] = source.Source
+		}
+	}
+
+	labels := []string{}
+	for k, v := range merged {
+		labels = append(labels, k+"="+v)
+	}
+	sort.Strings(labels)
+
+	return labels, conflicts
+}
+
+// MergeRuntimeLabels Function
+func MergeRuntimeLabels(sources ...LabelSource) (map[string]string, []LabelConflict) {
+	// containers are equal, so only the labels that all containers agree on are kept
+
+	merged := map[string]string{}
+	owners := map[string]string{}
+	dropped := map[string]bool{}
+
+	conflicts := []LabelConflict{}
+
+	for _, source := range sources {
+		for _, k := range sortedKeys(source.Labels) {
+			if dropped[k] || hasPerContainerLabelPrefix(k) {
+				continue
+			}
+
+			v := source.Labels[k]
+
+			if val, ok := merged[k]; ok {
+				if val != v {
+					conflicts = append(conflicts, LabelConflict{Key: k, Source: owners[k], Value: val, IgnoredSource: source.Source, IgnoredValue: v, Dropped: true})
+					delete(merged, k)
+					dropped[k] = true
+				}
+				continue
+			}
+
+			merged[k] = v
+			owners[k] = source.Source
+		}
+	}
+
+	return merged, conflicts
+}
+
+// hasPerContainerLabelPrefix Function
+func hasPerContainerLabelPrefix(key string) bool {
+	for _, prefix := range perContainerLabelPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetContainerGroupLabels Function (ContainerGroupsLock should be held)
+//
+// merges the labels of a pod, the annotations of the pod, and the runtime labels of its containers in that order of precedence
+func (dm *KubeArmorDaemon) GetContainerGroupLabels(namespaceName, containerGroupName string, podLabels, annotations map[string]string, containerIDs []string) []string {
+	podAnnotations := map[string]string{}
+	for k, v := range annotations {
+		if !kl.ContainsElement(ignoredAnnotations, k) {
+			podAnnotations[k] = v
+		}
+	}
+
+	// runtime labels, ordered by container id
+	ids := append([]string{}, containerIDs...)
+	sort.Strings(ids)
+
+	runtimeSources := []LabelSource{}
+
+	dm.ContainersLock.RLock()
+	for _, containerID := range ids {
+		if container, ok := dm.Containers[containerID]; ok {
+			runtimeSources = append(runtimeSources, LabelSource{Source: LabelSourceRuntime + "/" + containerID, Labels: ParseLabels(container.Labels)})
+		}
+	}
+	dm.ContainersLock.RUnlock()
+
+	runtimeLabels, runtimeConflicts := MergeRuntimeLabels(runtimeSources...)
+
+	labels, conflicts := MergeLabels(
+		LabelSource{Source: LabelSourcePod, Labels: podLabels},
+		LabelSource{Source: LabelSourceAnnotation, Labels: podAnnotations},
+		LabelSource{Source: LabelSourceRuntime, Labels: runtimeLabels})
+
+	// report each conflict once
+	key := namespaceName + "/" + containerGroupName
+
+	reported := []string{}
+	for _, conflict := range append(runtimeConflicts, conflicts...) {
+		reported = append(reported, conflict.String())
+
+		if !kl.ContainsElement(dm.LabelConflicts[key], conflict.String()) {
+			kg.Printf("Detected a conflicting label (%s, %s)", key, conflict.String())
+		}
+	}
+
+	if len(reported) > 0 {
+		dm.LabelConflicts[key] = reported
+	} else {
+		delete(dm.LabelConflicts, key)
+	}
+
+	return labels
+}
+
+// GetPodLabels Function
+func (dm *KubeArmorDaemon) GetPodLabels(namespaceName, podName string) map[string]string {
+	dm.K8sPodsLock.RLock()
+	defer dm.K8sPodsLock.RUnlock()
+
+	for _, pod := range dm.K8sPods {
+		if pod.Metadata["namespaceName"] == namespaceName && pod.Metadata["podName"] == podName {
+			return pod.Labels
+		}
+	}
+
+	return map[string]string{}
+}
+
+// GetContainerGroupIdentities Function
+func GetContainerGroupIdentities(namespaceName, containerGroupName string, labels, identities []string) []string {
+	newIdentities := []string{"namespaceName=" + namespaceName, "containerGroupName=" + containerGroupName}
+
+	for _, label := range labels {
+		if kl.ContainsElement(ignoredIdentityKeys, strings.SplitN(label, "=", 2)[0]) {
+			continue
+		}
+
+		if !kl.ContainsElement(newIdentities, label) {
+			newIdentities = append(newIdentities, label)
+		}
+	}
+
+	// keep the containers of the container group
+	for _, identity := range identities {
+		if strings.HasPrefix(identity, "containerName=") && !kl.ContainsElement(newIdentities, identity) {
+			newIdentities = append(newIdentities, identity)
+		}
+	}
+
+	return newIdentities
+}
+
+// UpdateContainerGroupLabels Function (ContainerGroupsLock should be held)
+//
+// returns true if the identities of the container group are changed
+func (dm *KubeArmorDaemon) UpdateContainerGroupLabels(conGroup *tp.ContainerGroup, podLabels, annotations map[string]string) bool {
+	conGroup.Labels = dm.GetContainerGroupLabels(conGroup.NamespaceName, conGroup.ContainerGroupName, podLabels, annotations, conGroup.Containers)
+
+	identities := GetContainerGroupIdentities(conGroup.NamespaceName, conGroup.ContainerGroupName, conGroup.Labels, conGroup.Identities)
+	changed := !reflect.DeepEqual(identities, conGroup.Identities)
+	conGroup.Identities = identities
+
+	return changed
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	kl "github.com/accuknox/KubeArmor/KubeArmor/common"
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+func TestMergeLabels(t *testing.T) {
+	podLabels := LabelSource{Source: LabelSourcePod, Labels: map[string]string{"app": "nginx", "tier": "frontend", "version": "v2"}}
+	runtimeLabels := LabelSource{Source: LabelSourceRuntime, Labels: map[string]string{"version": "v1", "maintainer": "ops", "app": "nginx"}}
+
+	expected := []string{"app=nginx", "maintainer=ops", "tier=frontend", "version=v2"}
+
+	// merge the labels repeatedly (map iteration is random)
+
+	for i := 0; i < 10; i++ {
+		labels, conflicts := MergeLabels(podLabels, runtimeLabels)
+
+		if !reflect.DeepEqual(labels, expected) {
+			t.Errorf("[FAIL] Failed to merge labels (%v, expected %v)", labels, expected)
+			return
+		}
+
+		if len(conflicts) != 1 {
+			t.Errorf("[FAIL] Failed to detect a conflicting label (%v)", conflicts)
+			return
+		}
+
+		conflict := conflicts[0]
+		if conflict.Key != "version" || conflict.Source != LabelSourcePod || conflict.Value != "v2" || conflict.IgnoredSource != LabelSourceRuntime || conflict.IgnoredValue != "v1" {
+			t.Errorf("[FAIL] Failed to detect a conflicting label (%v)", conflict)
+			return
+		}
+	}
+
+	t.Log("[PASS] Merged labels from two sources")
+
+	// the precedence follows the order of sources
+
+	labels, _ := MergeLabels(runtimeLabels, podLabels)
+	if !kl.ContainsElement(labels, "version=v1") || kl.ContainsElement(labels, "version=v2") {
+		t.Errorf("[FAIL] Failed to keep the precedence of label sources (%v)", labels)
+		return
+	}
+
+	t.Log("[PASS] Kept the precedence of label sources")
+}
+
+func TestMergeRuntimeLabels(t *testing.T) {
+	first := LabelSource{Source: LabelSourceRuntime + "/a", Labels: map[string]string{"io.kubernetes.container.name": "app", "maintainer": "ops", "version": "v1"}}
+	second := LabelSource{Source: LabelSourceRuntime + "/b", Labels: map[string]string{"io.kubernetes.container.name": "sidecar", "maintainer": "ops", "version": "v2"}}
+
+	labels, conflicts := MergeRuntimeLabels(first, second)
+
+	if !reflect.DeepEqual(labels, map[string]string{"maintainer": "ops"}) {
+		t.Errorf("[FAIL] Failed to keep the runtime labels common to containers (%v)", labels)
+		return
+	}
+
+	if len(conflicts) != 1 || conflicts[0].Key != "version" || !conflicts[0].Dropped {
+		t.Errorf("[FAIL] Failed to detect a runtime label differing across containers (%v)", conflicts)
+		return
+	}
+
+	t.Log("[PASS] Merged the runtime labels of containers")
+
+	// the result does not depend on the order of containers
+
+	reversed, _ := MergeRuntimeLabels(second, first)
+	if !reflect.DeepEqual(labels, reversed) {
+		t.Errorf("[FAIL] Merged runtime labels depending on the order of containers (%v, %v)", labels, reversed)
+		return
+	}
+
+	t.Log("[PASS] Merged runtime labels regardless of the order of containers")
+}
+
+func TestGetContainerGroupLabels(t *testing.T) {
+	dm := NewKubeArmorDaemon(false, false, false, false, false, false, false)
+
+	dm.Containers["b"] = tp.Container{ContainerID: "b", Labels: []string{"io.kubernetes.container.name=sidecar", "io.kubernetes.docker.type=container", "tier=backend", "image=nginx"}}
+	dm.Containers["a"] = tp.Container{ContainerID: "a", Labels: []string{"io.kubernetes.container.name=app", "io.kubernetes.docker.type=container", "image=nginx", "team=web"}}
+
+	podLabels := map[string]string{"tier": "frontend", "app": "nginx", "pod-template-hash": "1234"}
+	annotations := map[string]string{"team": "payments", "owner": "alice", "kubectl.kubernetes.io/last-applied-configuration": "{}"}
+
+	labels := dm.GetContainerGroupLabels("default", "nginx", podLabels, annotations, []string{"b", "a"})
+
+	// pod labels > annotations > runtime labels (without per-container ones)
+	expected := []string{"app=nginx", "image=nginx", "owner=alice", "pod-template-hash=1234", "team=payments", "tier=frontend"}
+	if !reflect.DeepEqual(labels, expected) {
+		t.Errorf("[FAIL] Failed to merge the labels of a container group (%v, expected %v)", labels, expected)
+		return
+	}
+
+	if len(dm.LabelConflicts["default/nginx"]) != 2 {
+		t.Errorf("[FAIL] Failed to record the label conflicts of a container group (%v)", dm.LabelConflicts["default/nginx"])
+		return
+	}
+
+	t.Log("[PASS] Merged the labels of a container group")
+
+	// identities come from the merged labels
+
+	conGroup := tp.ContainerGroup{NamespaceName: "default", ContainerGroupName: "nginx", Containers: []string{"a", "b"}, Identities: []string{"containerName=app"}}
+
+	if !dm.UpdateContainerGroupLabels(&conGroup, podLabels, annotations) {
+		t.Error("[FAIL] Failed to update the identities of a container group")
+		return
+	}
+
+	expected = []string{"namespaceName=default", "containerGroupName=nginx", "app=nginx", "image=nginx", "owner=alice", "team=payments", "tier=frontend", "containerName=app"}
+	if !reflect.DeepEqual(conGroup.Identities, expected) {
+		t.Errorf("[FAIL] Failed to get the identities of a container group (%v, expected %v)", conGroup.Identities, expected)
+		return
+	}
+
+	if dm.UpdateContainerGroupLabels(&conGroup, podLabels, annotations) {
+		t.Error("[FAIL] Changed the identities of a container group without any update")
+		return
+	}
+
+	if !kl.MatchIdentities([]string{"namespaceName=default", "owner=alice"}, conGroup.Identities) {
+		t.Error("[FAIL] Failed to match a selector with an annotation")
+		return
+	}
+
+	t.Log("[PASS] Got the identities of a container group")
+}
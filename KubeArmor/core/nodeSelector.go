@@ -0,0 +1,159 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	kl "github.com/accuknox/KubeArmor/KubeArmor/common"
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+// =================== //
+// == Node Selector == //
+// =================== //
+
+// nameRangePattern for numeric ranges in names (e.g., [001-100])
+var nameRangePattern = regexp.MustCompile(`^([0-9]+)-([0-9]+)$`)
+
+// nameRange Structure
+type nameRange struct {
+	low  int
+	high int
+}
+
+// NamePattern Structure
+type NamePattern struct {
+	regex  *regexp.Regexp
+	ranges []nameRange
+}
+
+// IsNamePattern Function
+func IsNamePattern(name string) bool {
+	return strings.ContainsAny(name, "*?[]")
+}
+
+// CompileNamePattern Function
+func CompileNamePattern(pattern string) (*NamePattern, error) {
+	namePattern := &NamePattern{}
+
+	// e.g., node-* or node-[001-100]
+	expr := "^"
+
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '*':
+			expr = expr + ".*"
+		case '?':
+			expr = expr + "."
+		case '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed range (%s)", pattern)
+			}
+
+			bounds := nameRangePattern.FindStringSubmatch(pattern[i+1 : i+end])
+			if bounds == nil {
+				return nil, fmt.Errorf("invalid range (%s)", pattern[i:i+end+1])
+			}
+
+			low, errL := strconv.Atoi(bounds[1])
+			high, errH := strconv.Atoi(bounds[2])
+			if errL != nil || errH != nil || low > high {
+				return nil, fmt.Errorf("invalid range (%s)", pattern[i:i+end+1])
+			}
+
+			// zero-padded ranges (e.g., [001-100]) match numbers of the same width
+			if len(bounds[1]) == len(bounds[2]) {
+				expr = expr + fmt.Sprintf("([0-9]{%d})", len(bounds[1]))
+			} else {
+				expr = expr + "([0-9]+)"
+			}
+
+			namePattern.ranges = append(namePattern.ranges, nameRange{low: low, high: high})
+
+			i = i + end
+		case ']':
+			return nil, fmt.Errorf("unopened range (%s)", pattern)
+		default:
+			expr = expr + regexp.QuoteMeta(pattern[i:i+1])
+		}
+	}
+
+	regex, err := regexp.Compile(expr + "$")
+	if err != nil {
+		return nil, err
+	}
+	namePattern.regex = regex
+
+	return namePattern, nil
+}
+
+// Match Function
+func (np *NamePattern) Match(name string) bool {
+	matches := np.regex.FindStringSubmatch(name)
+	if matches == nil {
+		return false
+	}
+
+	for idx, r := range np.ranges {
+		val, err := strconv.Atoi(matches[idx+1])
+		if err != nil || val < r.low || val > r.high {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ValidateNodeSelector Function
+func ValidateNodeSelector(nodeSelector tp.NodeSelectorType) error {
+	for k, v := range nodeSelector.MatchNames {
+		if !IsNamePattern(v) {
+			continue
+		}
+
+		if _, err := CompileNamePattern(v); err != nil {
+			return fmt.Errorf("invalid nodeSelector.matchNames (%s=%s, %s)", k, v, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// ResolveNodeIdentities Function
+func ResolveNodeIdentities(identities, nodeIdentities []string) []string {
+	resolved := []string{}
+
+	for _, identity := range identities {
+		kv := strings.SplitN(identity, "=", 2)
+		if len(kv) != 2 || !IsNamePattern(kv[1]) {
+			resolved = append(resolved, identity)
+			continue
+		}
+
+		namePattern, err := CompileNamePattern(kv[1])
+		if err != nil {
+			resolved = append(resolved, identity)
+			continue
+		}
+
+		// replace the pattern with the matched node identity
+		for _, nodeIdentity := range nodeIdentities {
+			if strings.HasPrefix(nodeIdentity, kv[0]+"=") && namePattern.Match(strings.TrimPrefix(nodeIdentity, kv[0]+"=")) {
+				identity = nodeIdentity
+				break
+			}
+		}
+
+		resolved = append(resolved, identity)
+	}
+
+	return resolved
+}
+
+// MatchNodeSelector Function
+func MatchNodeSelector(identities, nodeIdentities []string) bool {
+	return kl.MatchIdentities(ResolveNodeIdentities(identities, nodeIdentities), nodeIdentities)
+}
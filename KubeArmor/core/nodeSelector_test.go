@@ -0,0 +1,98 @@
+package core
+
+import (
+	"testing"
+
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+func TestMatchNodeSelector(t *testing.T) {
+	nodeIdentities := []string{"hostName=node-042", "architecture=amd64", "osType=linux", "kernelVersion=5.4.0"}
+
+	// a glob pattern
+
+	if !MatchNodeSelector([]string{"hostName=node-*", "osType=linux"}, nodeIdentities) {
+		t.Errorf("[FAIL] Failed to match a node with a glob pattern")
+		return
+	}
+
+	if !MatchNodeSelector([]string{"kernelVersion=5.4.?"}, nodeIdentities) {
+		t.Errorf("[FAIL] Failed to match a node with a single character pattern")
+		return
+	}
+
+	t.Log("[PASS] Matched a node with a glob pattern")
+
+	// a numeric range
+
+	if !MatchNodeSelector([]string{"hostName=node-[001-100]"}, nodeIdentities) {
+		t.Errorf("[FAIL] Failed to match a node with a numeric range")
+		return
+	}
+
+	if MatchNodeSelector([]string{"hostName=node-[100-250]"}, nodeIdentities) {
+		t.Errorf("[FAIL] Matched a node out of a numeric range")
+		return
+	}
+
+	if MatchNodeSelector([]string{"hostName=node-[1-100]"}, []string{"hostName=node-1000"}) {
+		t.Errorf("[FAIL] Matched a node out of an unpadded numeric range")
+		return
+	}
+
+	t.Log("[PASS] Matched a node with a numeric range")
+
+	// a non-matching node name
+
+	if MatchNodeSelector([]string{"hostName=worker-*"}, nodeIdentities) {
+		t.Errorf("[FAIL] Matched a non-matching node name")
+		return
+	}
+
+	if MatchNodeSelector([]string{"hostName=node-*", "osType=windows"}, nodeIdentities) {
+		t.Errorf("[FAIL] Matched a node with a non-matching identity")
+		return
+	}
+
+	if !MatchNodeSelector([]string{"hostName=node-042"}, nodeIdentities) || MatchNodeSelector([]string{"hostName=node-04"}, nodeIdentities) {
+		t.Errorf("[FAIL] Failed to match an exact node name")
+		return
+	}
+
+	t.Log("[PASS] Skipped a non-matching node name")
+}
+
+func TestValidateNodeSelector(t *testing.T) {
+	for _, pattern := range []string{"node-*", "node-[001-100]", "node-??-[1-20]", "node-1"} {
+		if err := ValidateNodeSelector(tp.NodeSelectorType{MatchNames: map[string]string{"hostName": pattern}}); err != nil {
+			t.Errorf("[FAIL] Rejected a valid pattern (%s, %s)", pattern, err.Error())
+			return
+		}
+	}
+
+	t.Log("[PASS] Validated node name patterns")
+
+	for _, pattern := range []string{"node-[001-100", "node-]", "node-[a-z]", "node-[100-001]"} {
+		if err := ValidateNodeSelector(tp.NodeSelectorType{MatchNames: map[string]string{"hostName": pattern}}); err == nil {
+			t.Errorf("[FAIL] Accepted an invalid pattern (%s)", pattern)
+			return
+		}
+	}
+
+	// invalid patterns are rejected at load time
+
+	dm := NewKubeArmorDaemon(false, true, false, false, false, false, false)
+
+	event := tp.K8sKubeArmorHostPolicyEvent{Type: "ADDED"}
+	event.Object.Metadata.Name = "hsp-node-range-block"
+	event.Object.Spec.NodeSelector.MatchNames = map[string]string{"hostName": "node-[100-001]"}
+	event.Object.Spec.Process.MatchPaths = []tp.ProcessPathType{{Path: "/usr/bin/diff"}}
+	event.Object.Spec.Action = "Block"
+
+	if _, _, err := dm.UpdateHostSecurityPolicyList(event); err == nil || len(dm.HostSecurityPolicies) != 0 {
+		t.Errorf("[FAIL] Loaded a host security policy with an invalid pattern")
+		return
+	}
+
+	t.Log("[PASS] Rejected invalid node name patterns")
+}
@@ -0,0 +1,358 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	kl "github.com/accuknox/KubeArmor/KubeArmor/common"
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+// =================== //
+// == Policy Loader == //
+// =================== //
+
+// PolicyFileExtensions for the policy files in a policy directory
+var PolicyFileExtensions = []string{".yaml", ".yml", ".json"}
+
+// PolicyFile Structure
+type PolicyFile struct {
+	// the policies applied from the file
+	SecurityPolicies     []tp.K8sKubeArmorPolicy
+	HostSecurityPolicies []tp.K8sKubeArmorHostPolicy
+}
+
+// policyDocument Structure
+type policyDocument struct {
+	Kind string `json:"kind"`
+}
+
+// IsPolicyFile Function
+func IsPolicyFile(path string) bool {
+	// skip hidden files (e.g., ..data of ConfigMap volumes, swap files of editors)
+	if strings.HasPrefix(filepath.Base(path), ".") {
+		return false
+	}
+
+	return kl.ContainsElement(PolicyFileExtensions, strings.ToLower(filepath.Ext(path)))
+}
+
+// ParsePolicyFile Function
+func ParsePolicyFile(content []byte) (PolicyFile, error) {
+	policyFile := PolicyFile{}
+
+	// multiple YAML documents (separated by ---) or JSON objects
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(content), 4096)
+
+	for {
+		raw := json.RawMessage{}
+		if err := decoder.Decode(&raw); err == io.EOF {
+			break
+		} else if err != nil {
+			return PolicyFile{}, err
+		}
+
+		// empty document
+		if len(raw) == 0 || string(raw) == "null" {
+			continue
+		}
+
+		doc := policyDocument{}
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			return PolicyFile{}, err
+		}
+
+		switch doc.Kind {
+		case "KubeArmorPolicy":
+			policy := tp.K8sKubeArmorPolicy{}
+			if err := json.Unmarshal(raw, &policy); err != nil {
+				return PolicyFile{}, err
+			}
+
+			if policy.Metadata.Name == "" {
+				return PolicyFile{}, errors.New("no metadata.name")
+			}
+
+			if policy.Metadata.Namespace == "" {
+				policy.Metadata.Namespace = "default"
+			}
+
+			policyFile.SecurityPolicies = append(policyFile.SecurityPolicies, policy)
+
+		case "KubeArmorHostPolicy":
+			policy := tp.K8sKubeArmorHostPolicy{}
+			if err := json.Unmarshal(raw, &policy); err != nil {
+				return PolicyFile{}, err
+			}
+
+			if policy.Metadata.Name == "" {
+				return PolicyFile{}, errors.New("no metadata.name")
+			}
+
+			policyFile.HostSecurityPolicies = append(policyFile.HostSecurityPolicies, policy)
+
+		default:
+			return PolicyFile{}, fmt.Errorf("unknown kind (%s)", doc.Kind)
+		}
+	}
+
+	return policyFile, nil
+}
+
+// applySecurityPolicyEvent Function
+func (dm *KubeArmorDaemon) applySecurityPolicyEvent(event tp.K8sKubeArmorPolicyEvent) bool {
+	secPolicy, updated, err := dm.UpdateSecurityPolicyList(event)
+	if err != nil {
+		dm.LogFeeder.Errf("Rejected a Security Policy (%s/%s/%s, %s)", strings.ToLower(event.Type), secPolicy.Metadata["namespaceName"], secPolicy.Metadata["policyName"], err.Error())
+		return false
+	} else if !updated {
+		dm.LogFeeder.Debugf("Skipped an unchanged Security Policy (%s/%s/%s)", strings.ToLower(event.Type), secPolicy.Metadata["namespaceName"], secPolicy.Metadata["policyName"])
+		return true
+	}
+
+	dm.LogFeeder.Printf("Detected a Security Policy (%s/%s/%s)", strings.ToLower(event.Type), secPolicy.Metadata["namespaceName"], secPolicy.Metadata["policyName"])
+
+	// apply security policies to containers
+	dm.UpdateSecurityPolicy(event.Type, secPolicy)
+
+	return true
+}
+
+// applyHostSecurityPolicyEvent Function
+func (dm *KubeArmorDaemon) applyHostSecurityPolicyEvent(event tp.K8sKubeArmorHostPolicyEvent) (bool, bool) {
+	secPolicy, updated, err := dm.UpdateHostSecurityPolicyList(event)
+	if err != nil {
+		dm.LogFeeder.Errf("Rejected a Host Security Policy (%s/%s, %s)", strings.ToLower(event.Type), secPolicy.Metadata["policyName"], err.Error())
+		return false, false
+	} else if !updated {
+		dm.LogFeeder.Debugf("Skipped an unchanged Host Security Policy (%s/%s)", strings.ToLower(event.Type), secPolicy.Metadata["policyName"])
+		return true, false
+	}
+
+	dm.LogFeeder.Printf("Detected a Host Security Policy (%s/%s)", strings.ToLower(event.Type), secPolicy.Metadata["policyName"])
+
+	return true, true
+}
+
+// LoadPolicyFile Function
+func (dm *KubeArmorDaemon) LoadPolicyFile(path string) error {
+	newFile := PolicyFile{}
+
+	content, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	} else if err == nil {
+		// keep the policies previously applied from a malformed file
+		if newFile, err = ParsePolicyFile(content); err != nil {
+			return err
+		}
+	}
+
+	dm.PolicyFilesLock.Lock()
+	defer dm.PolicyFilesLock.Unlock()
+
+	oldFile := dm.PolicyFiles[path]
+	loadedFile := PolicyFile{}
+
+	// security policies
+
+	for _, policy := range newFile.SecurityPolicies {
+		event := tp.K8sKubeArmorPolicyEvent{Type: "ADDED", Object: policy}
+		lastPolicy := tp.K8sKubeArmorPolicy{}
+
+		for _, oldPolicy := range oldFile.SecurityPolicies {
+			if oldPolicy.Metadata.Namespace == policy.Metadata.Namespace && oldPolicy.Metadata.Name == policy.Metadata.Name {
+				event.Type = "MODIFIED"
+				lastPolicy = oldPolicy
+				break
+			}
+		}
+
+		if dm.applySecurityPolicyEvent(event) {
+			loadedFile.SecurityPolicies = append(loadedFile.SecurityPolicies, policy)
+		} else if event.Type == "MODIFIED" {
+			// keep the last valid policy instead of the invalid one (the same as for a malformed file)
+			loadedFile.SecurityPolicies = append(loadedFile.SecurityPolicies, lastPolicy)
+		}
+	}
+
+	for _, oldPolicy := range oldFile.SecurityPolicies {
+		removed := true
+
+		for _, policy := range newFile.SecurityPolicies {
+			if oldPolicy.Metadata.Namespace == policy.Metadata.Namespace && oldPolicy.Metadata.Name == policy.Metadata.Name {
+				removed = false
+				break
+			}
+		}
+
+		if removed {
+			dm.applySecurityPolicyEvent(tp.K8sKubeArmorPolicyEvent{Type: "DELETED", Object: oldPolicy})
+		}
+	}
+
+	// host security policies
+
+	hostUpdated := false
+
+	for _, policy := range newFile.HostSecurityPolicies {
+		if !dm.EnableHostPolicy {
+			dm.LogFeeder.Errf("Skipped a Host Security Policy without host policy enforcement (%s, %s)", path, policy.Metadata.Name)
+			continue
+		}
+
+		event := tp.K8sKubeArmorHostPolicyEvent{Type: "ADDED", Object: policy}
+		lastPolicy := tp.K8sKubeArmorHostPolicy{}
+
+		for _, oldPolicy := range oldFile.HostSecurityPolicies {
+			if oldPolicy.Metadata.Name == policy.Metadata.Name {
+				event.Type = "MODIFIED"
+				lastPolicy = oldPolicy
+				break
+			}
+		}
+
+		if loaded, updated := dm.applyHostSecurityPolicyEvent(event); loaded {
+			loadedFile.HostSecurityPolicies = append(loadedFile.HostSecurityPolicies, policy)
+			hostUpdated = hostUpdated || updated
+		} else if event.Type == "MODIFIED" {
+			// keep the last valid policy instead of the invalid one (the same as for a malformed file)
+			loadedFile.HostSecurityPolicies = append(loadedFile.HostSecurityPolicies, lastPolicy)
+		}
+	}
+
+	for _, oldPolicy := range oldFile.HostSecurityPolicies {
+		removed := true
+
+		for _, policy := range newFile.HostSecurityPolicies {
+			if oldPolicy.Metadata.Name == policy.Metadata.Name {
+				removed = false
+				break
+			}
+		}
+
+		if removed {
+			_, updated := dm.applyHostSecurityPolicyEvent(tp.K8sKubeArmorHostPolicyEvent{Type: "DELETED", Object: oldPolicy})
+			hostUpdated = hostUpdated || updated
+		}
+	}
+
+	// apply security policies to a host
+	if hostUpdated {
+		dm.UpdateHostSecurityPolicy()
+	}
+
+	if len(loadedFile.SecurityPolicies) > 0 || len(loadedFile.HostSecurityPolicies) > 0 {
+		dm.PolicyFiles[path] = loadedFile
+	} else {
+		delete(dm.PolicyFiles, path)
+	}
+
+	return nil
+}
+
+// LoadPolicyDir Function
+func (dm *KubeArmorDaemon) LoadPolicyDir(dir string) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	paths := []string{}
+
+	for _, file := range files {
+		path := filepath.Join(dir, file.Name())
+
+		// files or symbolic links to files (e.g., ConfigMap volumes)
+		if info, err := os.Stat(path); err != nil || info.IsDir() || !IsPolicyFile(path) {
+			continue
+		}
+
+		paths = append(paths, path)
+	}
+
+	// the files removed since the last load
+
+	dm.PolicyFilesLock.Lock()
+	for path := range dm.PolicyFiles {
+		if filepath.Dir(path) == filepath.Clean(dir) && !kl.ContainsElement(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	dm.PolicyFilesLock.Unlock()
+
+	// a malformed file doesn't abort the whole load
+
+	for _, path := range paths {
+		if err := dm.LoadPolicyFile(path); err != nil {
+			dm.LogFeeder.Errf("Failed to load policies from %s (%s)", path, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// WatchPolicyDir Function
+func (dm *KubeArmorDaemon) WatchPolicyDir(dir string) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	dm.PolicyDirWatcher = watcher
+
+	dm.WgDaemon.Add(1)
+	go dm.handlePolicyDirEvents(watcher, filepath.Clean(dir))
+
+	return nil
+}
+
+// handlePolicyDirEvents Function
+func (dm *KubeArmorDaemon) handlePolicyDirEvents(watcher *fsnotify.Watcher, dir string) {
+	defer dm.WgDaemon.Done()
+
+	for {
+		select {
+		case <-StopChan:
+			return
+
+		case event, ok := <-watcher.Events:
+			if !ok { // closed
+				return
+			}
+
+			if IsPolicyFile(event.Name) {
+				if err := dm.LoadPolicyFile(event.Name); err != nil {
+					dm.LogFeeder.Errf("Failed to load policies from %s (%s)", event.Name, err.Error())
+				}
+			} else if strings.HasPrefix(filepath.Base(event.Name), "..") {
+				// the symbolic links of a ConfigMap volume are swapped at once
+				if err := dm.LoadPolicyDir(dir); err != nil {
+					dm.LogFeeder.Errf("Failed to load policies from %s (%s)", dir, err.Error())
+				}
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok { // closed
+				return
+			}
+
+			dm.LogFeeder.Errf("Failed to watch %s (%s)", dir, err.Error())
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	kl "github.com/accuknox/KubeArmor/KubeArmor/common"
+	efc "github.com/accuknox/KubeArmor/KubeArmor/enforcer"
+	fd "github.com/accuknox/KubeArmor/KubeArmor/feeder"
+)
+
+const validPolicyFile = `apiVersion: security.accuknox.com/v1
+kind: KubeArmorPolicy
+metadata:
+  name: ksp-group-1-proc-path-block
+  namespace: multiubuntu
+spec:
+  severity: 5
+  selector:
+    matchLabels:
+      group: group-1
+  process:
+    matchPaths:
+    - path: /bin/sleep
+  action:
+    Block
+---
+apiVersion: security.accuknox.com/v1
+kind: KubeArmorHostPolicy
+metadata:
+  name: hsp-kubearmor-dev-proc-path-block
+spec:
+  nodeSelector:
+    matchNames:
+      hostName: HOSTNAME
+  severity: 5
+  process:
+    matchPaths:
+    - path: /usr/bin/diff
+  action:
+    Block
+`
+
+const invalidPolicyFile = `apiVersion: security.accuknox.com/v1
+kind: KubeArmorPolicy
+metadata:
+  name: ksp-group-2-file-path-audit
+  namespace: multiubuntu
+spec:
+  selector: [
+`
+
+const rejectedPolicyFile = `{"apiVersion": "security.accuknox.com/v1", "kind": "KubeArmorPolicy",
+ "metadata": {"name": "ksp-ubuntu-1-file-path-block", "namespace": "multiubuntu"},
+ "spec": {"selector": {"matchLabels": {"container": "ubuntu-1"}}, "file": {"matchPaths": [{"path": "etc/passwd"}]}, "action": "Block"}}
+`
+
+func TestParsePolicyFile(t *testing.T) {
+	policyFile, err := ParsePolicyFile([]byte(validPolicyFile))
+	if err != nil || len(policyFile.SecurityPolicies) != 1 || len(policyFile.HostSecurityPolicies) != 1 {
+		t.Errorf("[FAIL] Failed to parse a policy file (%v)", err)
+		return
+	}
+
+	if policy := policyFile.SecurityPolicies[0]; policy.Metadata.Namespace != "multiubuntu" || policy.Spec.Action != "Block" || policy.Spec.Process.MatchPaths[0].Path != "/bin/sleep" {
+		t.Errorf("[FAIL] Failed to parse a security policy (%v)", policy)
+		return
+	}
+
+	t.Log("[PASS] Parsed a policy file")
+
+	for _, invalid := range []string{invalidPolicyFile, "kind: Pod\nmetadata:\n  name: ubuntu-1\n", "kind: KubeArmorPolicy\nspec:\n  action: Block\n"} {
+		if _, err := ParsePolicyFile([]byte(invalid)); err == nil {
+			t.Errorf("[FAIL] Parsed an invalid policy file (%s)", invalid)
+			return
+		}
+	}
+
+	t.Log("[PASS] Rejected invalid policy files")
+}
+
+func TestLoadPolicyDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubearmor-policies")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a temporary directory (%s)", err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	validPolicy := strings.Replace(validPolicyFile, "HOSTNAME", kl.GetHostName(), 1)
+
+	validPath := filepath.Join(dir, "valid.yaml")
+	invalidPath := filepath.Join(dir, "invalid.yml")
+
+	for path, content := range map[string]string{
+		validPath:                           validPolicy,
+		invalidPath:                         invalidPolicyFile,
+		filepath.Join(dir, "rejected.json"): rejectedPolicyFile,
+		filepath.Join(dir, "README.md"):     "# policies",
+	} {
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Errorf("[FAIL] Failed to write a policy file (%s)", err.Error())
+			return
+		}
+	}
+
+	dm := NewKubeArmorDaemon(false, true, false, false, false, false, false)
+
+	dm.LogFeeder = fd.NewFeeder("32757", "none", false)
+	if dm.LogFeeder == nil {
+		t.Error("[FAIL] Failed to create Feeder")
+		return
+	}
+	defer dm.LogFeeder.DestroyFeeder()
+
+	// no enforcers
+	dm.RuntimeEnforcer = &efc.RuntimeEnforcer{}
+
+	// load the directory
+
+	if err := dm.LoadPolicyDir(dir); err != nil {
+		t.Errorf("[FAIL] Failed to load the policy directory (%s)", err.Error())
+		return
+	}
+
+	if len(dm.SecurityPolicies) != 1 || dm.SecurityPolicies[0].Metadata["policyName"] != "ksp-group-1-proc-path-block" || len(dm.HostSecurityPolicies) != 1 {
+		t.Errorf("[FAIL] Failed to load valid policies only (%d policies, %d host policies)", len(dm.SecurityPolicies), len(dm.HostSecurityPolicies))
+		return
+	}
+
+	if len(dm.PolicyFiles) != 1 {
+		t.Errorf("[FAIL] Failed to track the loaded policy files (%v)", dm.PolicyFiles)
+		return
+	}
+
+	t.Log("[PASS] Loaded valid policies despite invalid files")
+
+	// watch the directory
+
+	if err := dm.WatchPolicyDir(dir); err != nil {
+		t.Errorf("[FAIL] Failed to watch the policy directory (%s)", err.Error())
+		return
+	}
+	defer dm.PolicyDirWatcher.Close()
+
+	waitFor := func(check func() bool) bool {
+		for i := 0; i < 300; i++ {
+			dm.SecurityPoliciesLock.Lock()
+			dm.HostSecurityPoliciesLock.Lock()
+			ok := check()
+			dm.HostSecurityPoliciesLock.Unlock()
+			dm.SecurityPoliciesLock.Unlock()
+
+			if ok {
+				return true
+			}
+
+			time.Sleep(time.Millisecond * 10)
+		}
+
+		return false
+	}
+
+	// modify a policy
+
+	if err := ioutil.WriteFile(validPath, []byte(strings.Replace(validPolicy, "    Block\n---", "    Audit\n---", 1)), 0600); err != nil {
+		t.Errorf("[FAIL] Failed to modify a policy file (%s)", err.Error())
+		return
+	}
+
+	if !waitFor(func() bool {
+		return len(dm.SecurityPolicies) == 1 && dm.SecurityPolicies[0].Spec.Action == "Audit" && len(dm.HostSecurityPolicies) == 1
+	}) {
+		t.Errorf("[FAIL] Failed to reload a modified policy (%v)", dm.SecurityPolicies)
+		return
+	}
+
+	t.Log("[PASS] Reloaded a modified policy")
+
+	// modify the policies into invalid ones (relative paths)
+
+	modified := strings.Replace(validPolicy, "    Block\n---", "    Audit\n---", 1)
+	broken := strings.Replace(strings.Replace(modified, "- path: /bin/sleep", "- path: bin/sleep", 1), "- path: /usr/bin/diff", "- path: usr/bin/diff", 1)
+
+	if err := ioutil.WriteFile(validPath, []byte(broken), 0600); err != nil {
+		t.Errorf("[FAIL] Failed to modify a policy file (%s)", err.Error())
+		return
+	}
+
+	if err := dm.LoadPolicyFile(validPath); err != nil {
+		t.Errorf("[FAIL] Failed to load a policy file (%s)", err.Error())
+		return
+	}
+
+	if !waitFor(func() bool {
+		return len(dm.SecurityPolicies) == 1 && dm.SecurityPolicies[0].Spec.Action == "Audit" && dm.SecurityPolicies[0].Spec.Process.MatchPaths[0].Path == "/bin/sleep" &&
+			len(dm.HostSecurityPolicies) == 1 && dm.HostSecurityPolicies[0].Spec.Process.MatchPaths[0].Path == "/usr/bin/diff"
+	}) {
+		t.Errorf("[FAIL] Failed to keep the last valid policies (%v, %v)", dm.SecurityPolicies, dm.HostSecurityPolicies)
+		return
+	}
+
+	dm.PolicyFilesLock.Lock()
+	policyFile := dm.PolicyFiles[validPath]
+	dm.PolicyFilesLock.Unlock()
+
+	if len(policyFile.SecurityPolicies) != 1 || len(policyFile.HostSecurityPolicies) != 1 {
+		t.Errorf("[FAIL] Failed to track the last valid policies (%v)", policyFile)
+		return
+	}
+
+	t.Log("[PASS] Kept the last valid policies for invalid modifications")
+
+	// fix the invalid file
+
+	fixed := strings.Replace(invalidPolicyFile, "  selector: [\n", "  selector:\n    matchLabels:\n      group: group-2\n  file:\n    matchPaths:\n    - path: /etc/passwd\n  action:\n    Audit\n", 1)
+
+	if err := ioutil.WriteFile(invalidPath, []byte(fixed), 0600); err != nil {
+		t.Errorf("[FAIL] Failed to fix a policy file (%s)", err.Error())
+		return
+	}
+
+	if !waitFor(func() bool { return len(dm.SecurityPolicies) == 2 }) {
+		t.Errorf("[FAIL] Failed to load a fixed policy (%d policies)", len(dm.SecurityPolicies))
+		return
+	}
+
+	t.Log("[PASS] Loaded a fixed policy")
+
+	// remove a file
+
+	if err := os.Remove(validPath); err != nil {
+		t.Errorf("[FAIL] Failed to remove a policy file (%s)", err.Error())
+		return
+	}
+
+	if !waitFor(func() bool {
+		return len(dm.SecurityPolicies) == 1 && dm.SecurityPolicies[0].Metadata["policyName"] == "ksp-group-2-file-path-audit" && len(dm.HostSecurityPolicies) == 0
+	}) {
+		t.Errorf("[FAIL] Failed to unload the policies of a removed file (%d policies, %d host policies)", len(dm.SecurityPolicies), len(dm.HostSecurityPolicies))
+		return
+	}
+
+	t.Log("[PASS] Unloaded the policies of a removed file")
+}
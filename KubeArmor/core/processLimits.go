@@ -0,0 +1,49 @@
+package core
+
+import (
+	kg "github.com/accuknox/KubeArmor/KubeArmor/log"
+	mon "github.com/accuknox/KubeArmor/KubeArmor/monitor"
+)
+
+// ==================== //
+// == Process Limits == //
+// ==================== //
+
+// ProcessLimitsAnnotation for the process limits of the containers in a pod
+const ProcessLimitsAnnotation = "kubearmor-process-limits"
+
+// GetPodAnnotations Function
+func (dm *KubeArmorDaemon) GetPodAnnotations(namespaceName, podName string) map[string]string {
+	dm.K8sPodsLock.RLock()
+	defer dm.K8sPodsLock.RUnlock()
+
+	for _, pod := range dm.K8sPods {
+		if pod.Metadata["namespaceName"] == namespaceName && pod.Metadata["podName"] == podName {
+			return pod.Annotations
+		}
+	}
+
+	return map[string]string{}
+}
+
+// UpdateProcessLimits Function
+func (dm *KubeArmorDaemon) UpdateProcessLimits(namespaceName, podName string, annotations map[string]string, containerIDs []string) {
+	val, ok := annotations[ProcessLimitsAnnotation]
+	if !ok {
+		// use the default limits
+		for _, containerID := range containerIDs {
+			dm.SystemMonitor.DeleteProcessLimits(containerID)
+		}
+		return
+	}
+
+	limits, err := mon.ParseProcessLimits(val)
+	if err != nil {
+		kg.Errf("Failed to parse process limits (%s/%s, %s)", namespaceName, podName, err.Error())
+		return
+	}
+
+	for _, containerID := range containerIDs {
+		dm.SystemMonitor.SetProcessLimits(containerID, limits)
+	}
+}
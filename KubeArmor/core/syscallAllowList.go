@@ -0,0 +1,36 @@
+package core
+
+import (
+	kg "github.com/accuknox/KubeArmor/KubeArmor/log"
+	mon "github.com/accuknox/KubeArmor/KubeArmor/monitor"
+)
+
+// ========================= //
+// == Syscall Allow-lists == //
+// ========================= //
+
+// SyscallAllowListAnnotation for the syscall allow-list of the containers in a pod (e.g., @base,@network,ptrace)
+// (alert-only: the syscalls out of the allow-list are reported with the Audit action, not blocked)
+const SyscallAllowListAnnotation = "kubearmor-syscall-allowlist"
+
+// UpdateSyscallAllowList Function
+func (dm *KubeArmorDaemon) UpdateSyscallAllowList(namespaceName, podName string, annotations map[string]string, containerIDs []string) {
+	val, ok := annotations[SyscallAllowListAnnotation]
+	if !ok {
+		// allow all syscalls
+		for _, containerID := range containerIDs {
+			dm.SystemMonitor.DeleteSyscallAllowList(containerID)
+		}
+		return
+	}
+
+	allowList, err := mon.ParseSyscallAllowList(val)
+	if err != nil {
+		kg.Errf("Failed to parse the syscall allow-list (%s/%s, %s)", namespaceName, podName, err.Error())
+		return
+	}
+
+	for _, containerID := range containerIDs {
+		dm.SystemMonitor.SetSyscallAllowList(containerID, allowList)
+	}
+}
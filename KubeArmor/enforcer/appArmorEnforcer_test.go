@@ -1,9 +1,11 @@
 package enforcer
 
 import (
+	"strings"
 	"testing"
 
 	fd "github.com/accuknox/KubeArmor/KubeArmor/feeder"
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
 )
 
 func TestAppArmorEnforcer(t *testing.T) {
@@ -131,3 +133,22 @@ func TestHostAppArmorProfile(t *testing.T) {
 
 	t.Log("[PASS] Destroyed Feeder")
 }
+
+func TestQuarantineProfile(t *testing.T) {
+	blockPolicy := tp.SecurityPolicy{}
+	blockPolicy.Spec.Process.MatchPaths = []tp.ProcessPathType{{Path: "/bin/sleep"}}
+	blockPolicy.Spec.Action = "Block"
+
+	quarantinePolicy := blockPolicy
+	quarantinePolicy.Spec.Action = "Quarantine"
+
+	_, blockBody := GenerateProfileBody(false, []string{}, []string{}, []tp.SecurityPolicy{blockPolicy})
+	_, quarantineBody := GenerateProfileBody(false, []string{}, []string{}, []tp.SecurityPolicy{quarantinePolicy})
+
+	if !strings.Contains(quarantineBody, "deny /bin/sleep") || quarantineBody != blockBody {
+		t.Errorf("[FAIL] Failed to block the operations of a Quarantine policy (%s)", quarantineBody)
+		return
+	}
+
+	t.Log("[PASS] Blocked the operations of a Quarantine policy")
+}
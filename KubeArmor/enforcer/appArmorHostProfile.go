@@ -1532,7 +1532,7 @@ func GenerateHostProfileBody(enableAuditd bool, secPolicies []tp.HostSecurityPol
 	}
 
 	for _, secPolicy := range secPolicies {
-		if secPolicy.Spec.Action == "Block" || secPolicy.Spec.Action == "BlockWithAudit" {
+		if secPolicy.Spec.Action == "Block" || secPolicy.Spec.Action == "BlockWithAudit" || secPolicy.Spec.Action == "Quarantine" {
 			blackList := []string{}
 
 			// process
@@ -1574,7 +1574,7 @@ func GenerateHostProfileBody(enableAuditd bool, secPolicies []tp.HostSecurityPol
 	}
 
 	for _, secPolicy := range secPolicies {
-		if secPolicy.Spec.Action == "Block" || secPolicy.Spec.Action == "BlockWithAudit" {
+		if secPolicy.Spec.Action == "Block" || secPolicy.Spec.Action == "BlockWithAudit" || secPolicy.Spec.Action == "Quarantine" {
 			// process
 			blockedHostProcessesFromSource(enableAuditd, secPolicy, fromSources)
 
@@ -1914,7 +1914,7 @@ func GenerateProfileBody(enableAuditd bool, oldContentsPreMid, oldConetntsMidPos
 	}
 
 	for _, secPolicy := range securityPolicies {
-		if secPolicy.Spec.Action == "Block" || secPolicy.Spec.Action == "BlockWithAudit" {
+		if secPolicy.Spec.Action == "Block" || secPolicy.Spec.Action == "BlockWithAudit" || secPolicy.Spec.Action == "Quarantine" {
 			blackList := []string{}
 
 			// process
@@ -1974,7 +1974,7 @@ func GenerateProfileBody(enableAuditd bool, oldContentsPreMid, oldConetntsMidPos
 	}
 
 	for _, secPolicy := range securityPolicies {
-		if secPolicy.Spec.Action == "Block" || secPolicy.Spec.Action == "BlockWithAudit" {
+		if secPolicy.Spec.Action == "Block" || secPolicy.Spec.Action == "BlockWithAudit" || secPolicy.Spec.Action == "Quarantine" {
 			// process
 			blockedProcessesFromSource(enableAuditd, secPolicy, fromSources)
 
@@ -0,0 +1,180 @@
+package feeder
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	kl "github.com/accuknox/KubeArmor/KubeArmor/common"
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+// ==================== //
+// == Block Throttle == //
+// ==================== //
+
+// DefaultBlockSummaryInterval in seconds (for Block, BlockWithAudit, and Quarantine decisions, disabled by default)
+const DefaultBlockSummaryInterval = 0
+
+// blockEntry Structure
+type blockEntry struct {
+	// the beginning of the current window
+	start time.Time
+
+	// the last suppressed log and the number of suppressed logs
+	last  tp.Log
+	count int32
+}
+
+// BlockThrottle Structure
+type BlockThrottle struct {
+	interval time.Duration
+
+	// policy + container / host + resource -> entry
+	entries map[string]*blockEntry
+	lock    sync.Mutex
+
+	stopChan chan struct{}
+}
+
+// NewBlockThrottle Function
+func NewBlockThrottle(interval time.Duration) *BlockThrottle {
+	bt := &BlockThrottle{}
+
+	bt.interval = interval
+
+	bt.entries = map[string]*blockEntry{}
+	bt.lock = sync.Mutex{}
+
+	bt.stopChan = make(chan struct{})
+
+	return bt
+}
+
+// ThrottledActions for the decisions denying operations
+var ThrottledActions = []string{"Block", "BlockWithAudit", "Quarantine"}
+
+// getBlockKey Function
+func getBlockKey(log tp.Log) string {
+	target := log.HostName
+	if log.ContainerID != "" {
+		target = log.ContainerID
+	}
+
+	return log.PolicyName + "|" + target + "|" + log.Resource
+}
+
+// Allow Function
+func (bt *BlockThrottle) Allow(log tp.Log, now time.Time) bool {
+	if !kl.ContainsElement(ThrottledActions, log.Action) || (log.Type != "MatchedPolicy" && log.Type != "MatchedHostPolicy") {
+		return true
+	}
+
+	key := getBlockKey(log)
+
+	bt.lock.Lock()
+	defer bt.lock.Unlock()
+
+	if entry, ok := bt.entries[key]; ok {
+		// summarized at the end of the window
+		entry.last = log
+		entry.count++
+		return false
+	}
+
+	// the first occurrence is always emitted
+	bt.entries[key] = &blockEntry{start: now}
+
+	return true
+}
+
+// Flush Function
+func (bt *BlockThrottle) Flush(now time.Time) []tp.Log {
+	summaries := []tp.Log{}
+
+	bt.lock.Lock()
+	defer bt.lock.Unlock()
+
+	for key, entry := range bt.entries {
+		if now.Sub(entry.start) < bt.interval {
+			continue
+		}
+
+		if entry.count == 0 {
+			// no more blocks, so emit the next one immediately
+			delete(bt.entries, key)
+			continue
+		}
+
+		summary := entry.last
+		summary.UpdatedTime = now.UTC().Format(kl.TimeFormUTC)
+		summary.Count = entry.count
+		summaries = append(summaries, summary)
+
+		// start a new window
+		entry.start = now
+		entry.last = tp.Log{}
+		entry.count = 0
+	}
+
+	return summaries
+}
+
+// Stop Function
+func (bt *BlockThrottle) Stop() {
+	close(bt.stopChan)
+}
+
+// SetBlockSummaryInterval Function
+func (fd *Feeder) SetBlockSummaryInterval(interval int) error {
+	if interval < 0 {
+		return fmt.Errorf("invalid interval (%d)", interval)
+	}
+
+	fd.blockThrottleLock.Lock()
+	defer fd.blockThrottleLock.Unlock()
+
+	if fd.blockThrottle != nil {
+		fd.blockThrottle.Stop()
+		fd.blockThrottle = nil
+	}
+
+	// disabled
+	if interval == 0 {
+		return nil
+	}
+
+	fd.blockThrottle = NewBlockThrottle(time.Second * time.Duration(interval))
+
+	fd.WgServer.Add(1)
+	go fd.summarizeBlocks(fd.blockThrottle)
+
+	return nil
+}
+
+// getBlockThrottle Function
+func (fd *Feeder) getBlockThrottle() *BlockThrottle {
+	fd.blockThrottleLock.RLock()
+	defer fd.blockThrottleLock.RUnlock()
+
+	return fd.blockThrottle
+}
+
+// summarizeBlocks Function
+func (fd *Feeder) summarizeBlocks(bt *BlockThrottle) {
+	defer fd.WgServer.Done()
+
+	ticker := time.NewTicker(time.Second * 1)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-bt.stopChan:
+			return
+		case now := <-ticker.C:
+			for _, summary := range bt.Flush(now) {
+				fd.pushLog(summary)
+			}
+		}
+	}
+}
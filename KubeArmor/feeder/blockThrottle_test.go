@@ -0,0 +1,182 @@
+package feeder
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	kl "github.com/accuknox/KubeArmor/KubeArmor/common"
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+func TestBlockThrottle(t *testing.T) {
+	bt := NewBlockThrottle(time.Second * 10)
+	now := time.Now()
+
+	blockLog := tp.Log{Type: "MatchedPolicy", PolicyName: "ksp-ubuntu-1-proc-path-block", ContainerID: "ubuntu-1-container", Resource: "/bin/sleep", Action: "Block"}
+
+	// the first occurrence
+
+	if !bt.Allow(blockLog, now) {
+		t.Errorf("[FAIL] Suppressed the first Block decision")
+		return
+	}
+
+	t.Log("[PASS] Emitted the first Block decision")
+
+	// a burst of identical blocks
+
+	for i := 0; i < 99; i++ {
+		if bt.Allow(blockLog, now.Add(time.Millisecond*time.Duration(i))) {
+			t.Errorf("[FAIL] Emitted a repeated Block decision")
+			return
+		}
+	}
+
+	t.Log("[PASS] Suppressed repeated Block decisions")
+
+	// other logs
+
+	otherLogs := []tp.Log{
+		{Type: "MatchedPolicy", PolicyName: "ksp-ubuntu-1-proc-path-block", ContainerID: "ubuntu-1-container", Resource: "/usr/bin/wc", Action: "Block"},
+		{Type: "MatchedPolicy", PolicyName: "ksp-ubuntu-1-proc-path-block", ContainerID: "ubuntu-2-container", Resource: "/bin/sleep", Action: "Block"},
+		{Type: "MatchedPolicy", PolicyName: "ksp-ubuntu-1-proc-path-audit", ContainerID: "ubuntu-1-container", Resource: "/bin/sleep", Action: "Audit"},
+		{Type: "ContainerLog", ContainerID: "ubuntu-1-container", Resource: "/bin/sleep"},
+	}
+
+	for _, log := range otherLogs {
+		if !bt.Allow(log, now) {
+			t.Errorf("[FAIL] Suppressed a different log (%v)", log)
+			return
+		}
+	}
+
+	t.Log("[PASS] Emitted different logs")
+
+	// summaries
+
+	if summaries := bt.Flush(now.Add(time.Second * 5)); len(summaries) != 0 {
+		t.Errorf("[FAIL] Summarized Block decisions before the interval (%v)", summaries)
+		return
+	}
+
+	summaries := bt.Flush(now.Add(time.Second * 10))
+	if len(summaries) != 1 || summaries[0].Count != 99 || summaries[0].Resource != "/bin/sleep" {
+		t.Errorf("[FAIL] Failed to summarize repeated Block decisions (%v)", summaries)
+		return
+	}
+
+	t.Log("[PASS] Summarized repeated Block decisions")
+
+	// the next window
+
+	if bt.Allow(blockLog, now.Add(time.Second*11)) {
+		t.Errorf("[FAIL] Emitted a repeated Block decision in the next window")
+		return
+	}
+
+	if summaries := bt.Flush(now.Add(time.Second * 20)); len(summaries) != 1 || summaries[0].Count != 1 {
+		t.Errorf("[FAIL] Failed to summarize the next window (%v)", summaries)
+		return
+	}
+
+	if summaries := bt.Flush(now.Add(time.Second * 30)); len(summaries) != 0 {
+		t.Errorf("[FAIL] Summarized an empty window (%v)", summaries)
+		return
+	}
+
+	if !bt.Allow(blockLog, now.Add(time.Second*31)) {
+		t.Errorf("[FAIL] Suppressed a Block decision after a quiet window")
+		return
+	}
+
+	t.Log("[PASS] Emitted a Block decision after a quiet window")
+
+	// other denying actions
+
+	for _, action := range []string{"BlockWithAudit", "Quarantine"} {
+		deniedLog := blockLog
+		deniedLog.Action = action
+		deniedLog.PolicyName = "ksp-ubuntu-1-proc-path-" + strings.ToLower(action)
+
+		if !bt.Allow(deniedLog, now) || bt.Allow(deniedLog, now) {
+			t.Errorf("[FAIL] Failed to throttle repeated %s decisions", action)
+			return
+		}
+	}
+
+	t.Log("[PASS] Suppressed repeated BlockWithAudit and Quarantine decisions")
+}
+
+func TestPushLogWithBlockThrottle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubearmor-block")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a temporary directory (%s)", err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	logPath := filepath.Join(dir, "kubearmor.log")
+
+	// create Feeder
+	feeder := NewFeeder("32763", logPath, false)
+	if feeder == nil {
+		t.Error("[FAIL] Failed to create Feeder")
+		return
+	}
+	defer feeder.DestroyFeeder()
+
+	feeder.SecurityPolicies["multiubuntu_ubuntu-1"] = tp.MatchPolicies{Policies: []tp.MatchPolicy{
+		{PolicyName: "ksp-ubuntu-1-proc-path-block", Severity: "5", Operation: "Process", Resource: "/bin/sleep", Action: "Block"},
+	}}
+
+	// flush summaries manually
+	feeder.blockThrottle = NewBlockThrottle(time.Hour)
+
+	// a burst of identical blocks
+
+	for i := 0; i < 100; i++ {
+		feeder.PushLog(tp.Log{UpdatedTime: kl.GetDateTimeNow(), HostName: "kubearmor-dev", NamespaceName: "multiubuntu", PodName: "ubuntu-1", ContainerID: "ubuntu-1-container", Operation: "Process", Resource: "/bin/sleep", Result: "Permission denied"})
+	}
+
+	for _, summary := range feeder.blockThrottle.Flush(time.Now().Add(time.Hour)) {
+		feeder.pushLog(summary)
+	}
+
+	content, err := ioutil.ReadFile(logPath)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to read logs (%s)", err.Error())
+		return
+	}
+
+	logs := []tp.Log{}
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		log := tp.Log{}
+		if err := json.Unmarshal([]byte(line), &log); err != nil {
+			t.Errorf("[FAIL] Failed to parse a log (%s)", err.Error())
+			return
+		}
+		logs = append(logs, log)
+	}
+
+	if len(logs) != 2 {
+		t.Errorf("[FAIL] Failed to collapse a burst of Block decisions (%d logs)", len(logs))
+		return
+	}
+
+	if logs[0].Action != "Block" || logs[0].Count != 0 {
+		t.Errorf("[FAIL] Failed to emit the first Block decision (%v)", logs[0])
+		return
+	}
+
+	if logs[1].Action != "Block" || logs[1].PolicyName != "ksp-ubuntu-1-proc-path-block" || logs[1].Count != 99 {
+		t.Errorf("[FAIL] Failed to emit the summary of Block decisions (%v)", logs[1])
+		return
+	}
+
+	t.Log("[PASS] Emitted the first Block decision and its summary")
+}
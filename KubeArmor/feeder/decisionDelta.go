@@ -0,0 +1,155 @@
+package feeder
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+// ==================== //
+// == Decision Delta == //
+// ==================== //
+
+// DefaultMaxDecisionEntries for the number of tracked decisions (disabled by default)
+const DefaultMaxDecisionEntries = 0
+
+// DecisionChangeOperation for the logs emitted on decision changes
+const DecisionChangeOperation = "DecisionChange"
+
+// decisionEntry Structure
+type decisionEntry struct {
+	key      string
+	decision string
+}
+
+// DecisionTracker Structure
+type DecisionTracker struct {
+	maxEntries int
+
+	// container / host + operation + resource -> entry (least recently used at the back)
+	entries map[string]*list.Element
+	lruList *list.List
+	lock    sync.Mutex
+}
+
+// NewDecisionTracker Function
+func NewDecisionTracker(maxEntries int) *DecisionTracker {
+	dt := &DecisionTracker{}
+
+	dt.maxEntries = maxEntries
+
+	dt.entries = map[string]*list.Element{}
+	dt.lruList = list.New()
+	dt.lock = sync.Mutex{}
+
+	return dt
+}
+
+// getDecisionKey Function
+func getDecisionKey(log tp.Log) string {
+	target := log.HostName
+	if log.ContainerID != "" {
+		target = log.ContainerID
+	}
+
+	return target + "|" + log.Operation + "|" + log.Resource
+}
+
+// getDecision Function
+func getDecision(log tp.Log) string {
+	if log.Type == "MatchedPolicy" || log.Type == "MatchedHostPolicy" {
+		return log.Action
+	}
+
+	// no policy matched, or filtered out before logging
+	return "Allow"
+}
+
+// Track Function
+func (dt *DecisionTracker) Track(log tp.Log) (tp.Log, bool) {
+	if log.Operation == DecisionChangeOperation || log.Resource == "" {
+		return tp.Log{}, false
+	}
+
+	key := getDecisionKey(log)
+	decision := getDecision(log)
+
+	dt.lock.Lock()
+	defer dt.lock.Unlock()
+
+	if elem, ok := dt.entries[key]; ok {
+		dt.lruList.MoveToFront(elem)
+
+		entry := elem.Value.(*decisionEntry)
+		if entry.decision == decision {
+			return tp.Log{}, false
+		}
+
+		previous := entry.decision
+		entry.decision = decision
+
+		change := log
+		change.Operation = DecisionChangeOperation
+		change.Action = decision
+		change.Data = fmt.Sprintf("operation=%s previous=%s current=%s", log.Operation, previous, decision)
+		change.Count = 0
+
+		return change, true
+	}
+
+	// the first decision is only recorded
+	dt.entries[key] = dt.lruList.PushFront(&decisionEntry{key: key, decision: decision})
+
+	// evict the least recently used decision
+	if dt.lruList.Len() > dt.maxEntries {
+		oldest := dt.lruList.Back()
+		dt.lruList.Remove(oldest)
+		delete(dt.entries, oldest.Value.(*decisionEntry).key)
+	}
+
+	return tp.Log{}, false
+}
+
+// Len Function
+func (dt *DecisionTracker) Len() int {
+	dt.lock.Lock()
+	defer dt.lock.Unlock()
+
+	return dt.lruList.Len()
+}
+
+// SetMaxDecisionEntries Function
+func (fd *Feeder) SetMaxDecisionEntries(maxEntries int) error {
+	if maxEntries < 0 {
+		return fmt.Errorf("invalid number of decisions (%d)", maxEntries)
+	}
+
+	// disabled
+	if maxEntries == 0 {
+		fd.decisionTracker = nil
+		return nil
+	}
+
+	fd.decisionTracker = NewDecisionTracker(maxEntries)
+
+	return nil
+}
+
+// TrackDecision Function
+func (fd *Feeder) TrackDecision(log tp.Log) {
+	if fd.decisionTracker == nil {
+		return
+	}
+
+	if change, ok := fd.decisionTracker.Track(log); ok {
+		if change.NamespaceName != "" && change.PodName != "" {
+			change.Workload = fd.GetWorkload(change.NamespaceName, change.PodName)
+		}
+
+		// decision changes are only streamed to the clients watching them
+		fd.streamLog(change)
+		fd.queueLog(change)
+	}
+}
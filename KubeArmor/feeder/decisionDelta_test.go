@@ -0,0 +1,209 @@
+package feeder
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	kl "github.com/accuknox/KubeArmor/KubeArmor/common"
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+	pb "github.com/accuknox/KubeArmor/protobuf"
+)
+
+func TestDecisionTracker(t *testing.T) {
+	dt := NewDecisionTracker(2)
+
+	allowed := tp.Log{HostName: "kubearmor-dev", ContainerID: "ubuntu-1-container", Type: "ContainerLog", Operation: "File", Resource: "/etc/shadow", Result: "Passed"}
+
+	// repeated same decisions
+
+	for i := 0; i < 10; i++ {
+		if change, ok := dt.Track(allowed); ok {
+			t.Errorf("[FAIL] Emitted a decision change for the same decision (%v)", change)
+			return
+		}
+	}
+
+	t.Log("[PASS] Emitted no decision change for the same decision")
+
+	// a new policy blocks the resource
+
+	blocked := allowed
+	blocked.Type = "MatchedPolicy"
+	blocked.PolicyName = "ksp-ubuntu-1-file-path-block"
+	blocked.Action = "Block"
+	blocked.Result = "Permission denied"
+
+	change, ok := dt.Track(blocked)
+	if !ok {
+		t.Error("[FAIL] Failed to emit a decision change")
+		return
+	}
+
+	if change.Operation != DecisionChangeOperation || change.Action != "Block" || change.Data != "operation=File previous=Allow current=Block" {
+		t.Errorf("[FAIL] Failed to build a decision change (%v)", change)
+		return
+	}
+
+	if _, ok := dt.Track(blocked); ok {
+		t.Error("[FAIL] Emitted a decision change for the same decision")
+		return
+	}
+
+	t.Log("[PASS] Emitted a decision change")
+
+	// bound the number of tracked decisions
+
+	for i := 0; i < 10; i++ {
+		other := allowed
+		other.Resource = fmt.Sprintf("/tmp/file-%d", i)
+		dt.Track(other)
+	}
+
+	if dt.Len() != 2 {
+		t.Errorf("[FAIL] Failed to bound the number of decisions (%d)", dt.Len())
+		return
+	}
+
+	// the evicted decision is recorded again without a change
+	if _, ok := dt.Track(allowed); ok {
+		t.Error("[FAIL] Emitted a decision change for an evicted decision")
+		return
+	}
+
+	t.Log("[PASS] Bounded the number of decisions")
+}
+
+func TestPushLogWithDecisionDelta(t *testing.T) {
+	// unmatched logs are given to clients or only tracked
+	for _, enableSystemLog := range []bool{true, false} {
+		if !testPushLogWithDecisionDelta(t, enableSystemLog) {
+			return
+		}
+	}
+}
+
+// testPushLogWithDecisionDelta Function
+func testPushLogWithDecisionDelta(t *testing.T, enableSystemLog bool) bool {
+	dir, err := ioutil.TempDir("", "kubearmor-decision")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a temporary directory (%s)", err.Error())
+		return false
+	}
+	defer os.RemoveAll(dir)
+
+	logPath := filepath.Join(dir, "kubearmor.log")
+
+	// create Feeder
+	feeder := NewFeeder("0", logPath, enableSystemLog)
+	if feeder == nil {
+		t.Error("[FAIL] Failed to create Feeder")
+		return false
+	}
+	defer feeder.DestroyFeeder()
+
+	if err := feeder.SetMaxDecisionEntries(16); err != nil {
+		t.Errorf("[FAIL] Failed to set the maximum number of decisions (%s)", err.Error())
+		return false
+	}
+
+	LogLock.Lock()
+	LogQueue = []pb.Log{}
+	LogLock.Unlock()
+
+	log := tp.Log{HostName: "kubearmor-dev", NamespaceName: "multiubuntu", PodName: "ubuntu-1", ContainerID: "ubuntu-1-container", Operation: "File", Resource: "/etc/shadow"}
+
+	// allowed without any policy
+
+	for i := 0; i < 5; i++ {
+		log.UpdatedTime = kl.GetDateTimeNow()
+		log.Result = "Passed"
+		feeder.PushLog(log)
+	}
+
+	// update a policy to block the resource
+
+	feeder.SecurityPoliciesLock.Lock()
+	feeder.SecurityPolicies["multiubuntu_ubuntu-1"] = tp.MatchPolicies{Policies: []tp.MatchPolicy{
+		{PolicyName: "ksp-ubuntu-1-file-path-block", Severity: "5", Operation: "File", Resource: "/etc/shadow", Action: "Block"},
+	}}
+	feeder.SecurityPoliciesLock.Unlock()
+
+	for i := 0; i < 5; i++ {
+		log.UpdatedTime = kl.GetDateTimeNow()
+		log.Result = "Permission denied"
+		feeder.PushLog(log)
+	}
+
+	changes := []*pb.Log{}
+
+	LogLock.Lock()
+	for idx := range LogQueue {
+		pbLog := &LogQueue[idx]
+		if matchLogFilter("decision", pbLog) {
+			changes = append(changes, pbLog)
+		} else if matchLogFilter("", pbLog) && pbLog.Operation == DecisionChangeOperation {
+			t.Error("[FAIL] Gave a decision change to the clients watching all logs")
+		}
+	}
+	LogQueue = []pb.Log{}
+	LogLock.Unlock()
+
+	if len(changes) != 1 {
+		t.Errorf("[FAIL] Failed to emit a single decision change (%d changes)", len(changes))
+		return false
+	}
+
+	if changes[0].Action != "Block" || changes[0].PolicyName != "ksp-ubuntu-1-file-path-block" || changes[0].Data != "operation=File previous=Allow current=Block" {
+		t.Errorf("[FAIL] Failed to emit the decision change (%v)", changes[0].String())
+		return false
+	}
+
+	// decision changes are not written to the file sink
+
+	content, err := ioutil.ReadFile(logPath)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to read logs (%s)", err.Error())
+		return false
+	}
+
+	if strings.Contains(string(content), DecisionChangeOperation) {
+		t.Error("[FAIL] Wrote a decision change to the file sink")
+		return false
+	}
+
+	t.Logf("[PASS] Emitted a single decision change (enableSystemLog: %t)", enableSystemLog)
+
+	// remove the policy (allowed again)
+
+	feeder.SecurityPoliciesLock.Lock()
+	feeder.SecurityPolicies["multiubuntu_ubuntu-1"] = tp.MatchPolicies{}
+	feeder.SecurityPoliciesLock.Unlock()
+
+	log.UpdatedTime = kl.GetDateTimeNow()
+	log.Result = "Passed"
+	feeder.PushLog(log)
+
+	changes = []*pb.Log{}
+
+	LogLock.Lock()
+	for idx := range LogQueue {
+		if pbLog := &LogQueue[idx]; matchLogFilter("decision", pbLog) {
+			changes = append(changes, pbLog)
+		}
+	}
+	LogQueue = []pb.Log{}
+	LogLock.Unlock()
+
+	if len(changes) != 1 || changes[0].Data != "operation=File previous=Block current=Allow" {
+		t.Errorf("[FAIL] Failed to emit the decision change back to Allow (%v)", changes)
+		return false
+	}
+
+	t.Logf("[PASS] Emitted the decision change back to Allow (enableSystemLog: %t)", enableSystemLog)
+
+	return true
+}
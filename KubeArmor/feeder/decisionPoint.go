@@ -0,0 +1,54 @@
+package feeder
+
+import (
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+// ==================== //
+// == Decision Point == //
+// ==================== //
+
+const (
+	// DecisionPointKernel for the decisions made inline in the kernel (e.g., denied by LSMs, filtered by eBPF)
+	DecisionPointKernel = "Kernel"
+
+	// DecisionPointUserspace for the decisions made by the policy matcher of the feeder
+	DecisionPointUserspace = "Userspace"
+)
+
+// GetDecisionPoint Function
+func GetDecisionPoint(log tp.Log) string {
+	// already set by monitors (e.g., syscall allow-lists)
+	if log.DecisionPoint != "" {
+		return log.DecisionPoint
+	}
+
+	// the operation was denied before the log reached the matcher
+	if (log.Action == "Block" || log.Action == "Quarantine") && log.Result != "Passed" {
+		return DecisionPointKernel
+	}
+
+	return DecisionPointUserspace
+}
+
+// UpdateMatchedPolicy Function
+func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
+	log, latency := fd.matchPolicies(log)
+
+	// only the logs evaluated with policies
+	if latency > 0 {
+		fd.Metrics.ObserveMatchLatency(latency)
+	}
+
+	if !fd.EnableDecisionPoint {
+		log.DecisionPoint = ""
+		return log
+	}
+
+	if log.Type == "MatchedPolicy" || log.Type == "MatchedHostPolicy" {
+		log.DecisionPoint = GetDecisionPoint(log)
+		log.MatchLatency = latency.Nanoseconds()
+	}
+
+	return log
+}
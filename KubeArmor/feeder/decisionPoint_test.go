@@ -0,0 +1,102 @@
+package feeder
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+func TestDecisionPoint(t *testing.T) {
+	fd := &Feeder{}
+	fd.SecurityPoliciesLock = new(sync.RWMutex)
+	fd.SecurityPolicies = map[string]tp.MatchPolicies{
+		"multiubuntu_ubuntu-1": {Policies: []tp.MatchPolicy{
+			{PolicyName: "ksp-ubuntu-1-proc-path-block", Severity: "5", Operation: "Process", Resource: "/bin/sleep", Action: "Block"},
+			{PolicyName: "ksp-ubuntu-1-file-path-audit", Severity: "3", Operation: "File", Resource: "/etc/passwd", Action: "Audit"},
+		}},
+	}
+	fd.Metrics = NewMetrics()
+
+	blockLog := tp.Log{ContainerID: "ubuntu-1-container", NamespaceName: "multiubuntu", PodName: "ubuntu-1", Operation: "Process", Resource: "/bin/sleep 1", Result: "Permission denied"}
+	auditLog := tp.Log{ContainerID: "ubuntu-1-container", NamespaceName: "multiubuntu", PodName: "ubuntu-1", Operation: "File", Resource: "/etc/passwd", Result: "Passed"}
+
+	// disabled
+
+	if log := fd.UpdateMatchedPolicy(blockLog); log.DecisionPoint != "" || log.MatchLatency != 0 {
+		t.Errorf("[FAIL] Populated the decision point without the option (%v)", log)
+		return
+	}
+
+	t.Log("[PASS] Kept the decision point empty without the option")
+
+	// enabled
+
+	fd.EnableDecisionPoint = true
+
+	if log := fd.UpdateMatchedPolicy(blockLog); log.Type != "MatchedPolicy" || log.DecisionPoint != DecisionPointKernel || log.MatchLatency <= 0 {
+		t.Errorf("[FAIL] Failed to populate the decision point of a blocked operation (%v)", log)
+		return
+	}
+
+	if log := fd.UpdateMatchedPolicy(auditLog); log.Type != "MatchedPolicy" || log.DecisionPoint != DecisionPointUserspace || log.MatchLatency <= 0 {
+		t.Errorf("[FAIL] Failed to populate the decision point of an audited operation (%v)", log)
+		return
+	}
+
+	if count := fd.Metrics.MatchCount; count != 3 {
+		t.Errorf("[FAIL] Failed to count policy matches (%d)", count)
+		return
+	}
+
+	t.Log("[PASS] Populated the decision point")
+}
+
+func TestMatchLatency(t *testing.T) {
+	fd := &Feeder{}
+	fd.SecurityPoliciesLock = new(sync.RWMutex)
+	fd.SecurityPolicies = map[string]tp.MatchPolicies{}
+	fd.EnableDecisionPoint = true
+
+	policies := []tp.MatchPolicy{}
+	for i := 0; i < 20000; i++ {
+		policies = append(policies, tp.MatchPolicy{PolicyName: fmt.Sprintf("ksp-ubuntu-2-file-path-audit-%d", i), Severity: "3", Operation: "File", Resource: fmt.Sprintf("/tmp/file-%d", i), Action: "Audit"})
+	}
+	policies = append(policies, tp.MatchPolicy{PolicyName: "ksp-ubuntu-2-file-path-audit", Severity: "3", Operation: "File", Resource: "/etc/passwd", Action: "Audit"})
+
+	fd.SecurityPolicies["multiubuntu_ubuntu-1"] = tp.MatchPolicies{Policies: []tp.MatchPolicy{
+		{PolicyName: "ksp-ubuntu-1-file-path-audit", Severity: "3", Operation: "File", Resource: "/etc/passwd", Action: "Audit"},
+	}}
+
+	// a deliberately slow policy set (many policies to compare with)
+	fd.SecurityPolicies["multiubuntu_ubuntu-2"] = tp.MatchPolicies{Policies: policies}
+
+	matchLatency := func(podName string) int64 {
+		var total int64
+
+		for i := 0; i < 5; i++ {
+			log := tp.Log{ContainerID: podName + "-container", NamespaceName: "multiubuntu", PodName: podName, Operation: "File", Resource: "/etc/passwd", Result: "Passed"}
+			log = fd.UpdateMatchedPolicy(log)
+
+			if log.Type != "MatchedPolicy" {
+				t.Errorf("[FAIL] Failed to match a policy (%v)", log)
+				return 0
+			}
+
+			total += log.MatchLatency
+		}
+
+		return total
+	}
+
+	fast := matchLatency("ubuntu-1")
+	slow := matchLatency("ubuntu-2")
+
+	if fast == 0 || slow <= fast {
+		t.Errorf("[FAIL] Failed to show the higher latency of a slow policy (fast: %d, slow: %d)", fast, slow)
+		return
+	}
+
+	t.Log("[PASS] Showed the higher latency of a slow policy")
+}
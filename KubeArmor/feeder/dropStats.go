@@ -0,0 +1,186 @@
+package feeder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ================ //
+// == Drop Stats == //
+// ================ //
+
+// DropReason Type
+type DropReason int
+
+const (
+	// DropReasonNamespace for the events of containers in untracked namespaces
+	DropReasonNamespace DropReason = iota
+
+	// DropReasonThrottle for the repeated Block decisions collapsed into summaries
+	DropReasonThrottle
+
+	// DropReasonUnackedLog for the oldest unacked logs dropped due to the bound
+	DropReasonUnackedLog
+
+	// DropReasonSocketQueue for the logs dropped due to full unix socket client queues
+	DropReasonSocketQueue
+
+	// DropReasonLostEvent for the events lost in the perf buffers
+	DropReasonLostEvent
+
+	// DropReasonStreamQueue for the logs dropped due to full log stream client queues
+	DropReasonStreamQueue
+
+	// DropReasonBackfillQueue for the live logs dropped due to full queues of clients being backfilled
+	DropReasonBackfillQueue
+
+	// DropReasonLogQueue for the oldest logs dropped due to the bound of the log queue while no client takes them
+	DropReasonLogQueue
+
+	numDropReasons
+)
+
+// dropReasonNames for the names of drop reasons
+var dropReasonNames = [numDropReasons]string{"namespace", "throttle", "unackedLog", "socketQueue", "lostEvent", "streamQueue", "backfillQueue", "logQueue"}
+
+// String Function
+func (reason DropReason) String() string {
+	if reason < 0 || reason >= numDropReasons {
+		return "unknown"
+	}
+
+	return dropReasonNames[reason]
+}
+
+// DropStats Structure
+type DropStats struct {
+	// reason -> the number of dropped events
+	counts [numDropReasons]uint64
+
+	// the counts at the last flush
+	flushed   [numDropReasons]uint64
+	flushLock sync.Mutex
+}
+
+// NewDropStats Function
+func NewDropStats() *DropStats {
+	return &DropStats{}
+}
+
+// Add Function
+func (ds *DropStats) Add(reason DropReason, count uint64) {
+	if ds == nil || reason < 0 || reason >= numDropReasons {
+		return
+	}
+
+	atomic.AddUint64(&ds.counts[reason], count)
+}
+
+// Get Function
+func (ds *DropStats) Get(reason DropReason) uint64 {
+	if ds == nil || reason < 0 || reason >= numDropReasons {
+		return 0
+	}
+
+	return atomic.LoadUint64(&ds.counts[reason])
+}
+
+// GetAll Function
+func (ds *DropStats) GetAll() map[string]uint64 {
+	stats := map[string]uint64{}
+
+	for reason := DropReason(0); reason < numDropReasons; reason++ {
+		stats[reason.String()] = ds.Get(reason)
+	}
+
+	return stats
+}
+
+// Flush Function
+func (ds *DropStats) Flush() map[string]uint64 {
+	deltas := map[string]uint64{}
+
+	ds.flushLock.Lock()
+	defer ds.flushLock.Unlock()
+
+	for reason := DropReason(0); reason < numDropReasons; reason++ {
+		count := ds.Get(reason)
+
+		if delta := count - ds.flushed[reason]; delta > 0 {
+			deltas[reason.String()] = delta
+		}
+
+		ds.flushed[reason] = count
+	}
+
+	return deltas
+}
+
+// CountDrop Function
+func (fd *Feeder) CountDrop(reason DropReason) {
+	fd.DropStats.Add(reason, 1)
+}
+
+// GetStats Function
+func (fd *Feeder) GetStats() map[string]uint64 {
+	return fd.DropStats.GetAll()
+}
+
+// SetDropLogInterval Function
+func (fd *Feeder) SetDropLogInterval(interval int) error {
+	if interval < 0 {
+		return fmt.Errorf("invalid interval (%d)", interval)
+	}
+
+	if fd.dropLogStopChan != nil {
+		close(fd.dropLogStopChan)
+		fd.dropLogStopChan = nil
+	}
+
+	// disabled
+	if interval == 0 {
+		return nil
+	}
+
+	fd.dropLogStopChan = make(chan struct{})
+
+	fd.WgServer.Add(1)
+	go fd.logDrops(time.Second*time.Duration(interval), fd.dropLogStopChan)
+
+	return nil
+}
+
+// logDrops Function
+func (fd *Feeder) logDrops(interval time.Duration, stopChan chan struct{}) {
+	defer fd.WgServer.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// count only the events dropped from now on
+	fd.DropStats.Flush()
+
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			deltas := fd.DropStats.Flush()
+			if len(deltas) == 0 {
+				continue
+			}
+
+			reasons := []string{}
+			for reason, delta := range deltas {
+				reasons = append(reasons, fmt.Sprintf("%s=%d", reason, delta))
+			}
+			sort.Strings(reasons)
+
+			fd.Debugf("Dropped events in the last %s (%s)", interval, strings.Join(reasons, ", "))
+		}
+	}
+}
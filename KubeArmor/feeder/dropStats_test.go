@@ -0,0 +1,143 @@
+package feeder
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	kl "github.com/accuknox/KubeArmor/KubeArmor/common"
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+	pb "github.com/accuknox/KubeArmor/protobuf"
+)
+
+func TestDropStats(t *testing.T) {
+	ds := NewDropStats()
+
+	ds.Add(DropReasonNamespace, 1)
+	ds.Add(DropReasonLostEvent, 10)
+	ds.Add(DropReasonLostEvent, 5)
+
+	stats := ds.GetAll()
+	if stats["namespace"] != 1 || stats["lostEvent"] != 15 || stats["throttle"] != 0 || len(stats) != int(numDropReasons) {
+		t.Errorf("[FAIL] Failed to count dropped events (%v)", stats)
+		return
+	}
+
+	t.Log("[PASS] Counted dropped events")
+
+	// deltas per interval
+
+	deltas := ds.Flush()
+	if len(deltas) != 2 || deltas["namespace"] != 1 || deltas["lostEvent"] != 15 {
+		t.Errorf("[FAIL] Failed to flush dropped events (%v)", deltas)
+		return
+	}
+
+	ds.Add(DropReasonLostEvent, 3)
+
+	deltas = ds.Flush()
+	if len(deltas) != 1 || deltas["lostEvent"] != 3 {
+		t.Errorf("[FAIL] Failed to flush dropped events in an interval (%v)", deltas)
+		return
+	}
+
+	if deltas := ds.Flush(); len(deltas) != 0 {
+		t.Errorf("[FAIL] Flushed dropped events in an empty interval (%v)", deltas)
+		return
+	}
+
+	t.Log("[PASS] Flushed dropped events per interval")
+
+	// no stats
+
+	var nilStats *DropStats
+	nilStats.Add(DropReasonThrottle, 1)
+
+	if nilStats.Get(DropReasonThrottle) != 0 {
+		t.Error("[FAIL] Counted dropped events without stats")
+		return
+	}
+
+	t.Log("[PASS] Ignored dropped events without stats")
+}
+
+func TestCountDrops(t *testing.T) {
+	// create Feeder
+	feeder := NewFeeder("32759", "none", false)
+	if feeder == nil {
+		t.Error("[FAIL] Failed to create Feeder")
+		return
+	}
+	defer feeder.DestroyFeeder()
+
+	// filtered (system logs are disabled)
+
+	feeder.PushLog(tp.Log{UpdatedTime: kl.GetDateTimeNow(), HostName: "kubearmor-dev", NamespaceName: "multiubuntu", PodName: "ubuntu-1", ContainerID: "ubuntu-1-container", Operation: "File", Resource: "/etc/hostname", Result: "Passed"})
+
+	if count := atomic.LoadUint64(&feeder.Metrics.FilteredCount); count != 1 {
+		t.Errorf("[FAIL] Failed to count a filtered log (%d)", count)
+		return
+	}
+
+	for reason, count := range feeder.GetStats() {
+		if count != 0 {
+			t.Errorf("[FAIL] Counted a filtered log as a drop (%s: %d)", reason, count)
+			return
+		}
+	}
+
+	t.Log("[PASS] Counted a filtered log apart from drops")
+
+	// throttle
+
+	feeder.SecurityPolicies["multiubuntu_ubuntu-1"] = tp.MatchPolicies{Policies: []tp.MatchPolicy{
+		{PolicyName: "ksp-ubuntu-1-proc-path-block", Severity: "5", Operation: "Process", Resource: "/bin/sleep", Action: "Block"},
+	}}
+
+	feeder.blockThrottle = NewBlockThrottle(time.Hour)
+
+	for i := 0; i < 3; i++ {
+		feeder.PushLog(tp.Log{UpdatedTime: kl.GetDateTimeNow(), HostName: "kubearmor-dev", NamespaceName: "multiubuntu", PodName: "ubuntu-1", ContainerID: "ubuntu-1-container", Operation: "Process", Resource: "/bin/sleep", Result: "Permission denied"})
+	}
+
+	if count := feeder.GetStats()["throttle"]; count != 2 {
+		t.Errorf("[FAIL] Failed to count throttled logs (%d)", count)
+		return
+	}
+
+	t.Log("[PASS] Counted throttled logs")
+
+	// unacked logs
+
+	ls := &LogService{AckConsumers: map[string]*AckConsumer{}, AckLock: sync.Mutex{}, MaxUnackedLogs: 2, DropStats: feeder.DropStats}
+	ls.AckConsumers["consumer-1"] = &AckConsumer{pendingLogs: map[uint64]*list.Element{}, pendingList: list.New()}
+
+	for seq := uint64(1); seq <= 5; seq++ {
+		ls.sendAckedLog(&pb.Log{Seq: seq, Type: "MatchedPolicy"}, time.Now())
+	}
+
+	if count := feeder.GetStats()["unackedLog"]; count != 3 {
+		t.Errorf("[FAIL] Failed to count dropped unacked logs (%d)", count)
+		return
+	}
+
+	t.Log("[PASS] Counted dropped unacked logs")
+
+	// full socket queues
+
+	us := &UnixSocketSink{clients: map[*unixSocketClient]struct{}{}, dropStats: feeder.DropStats}
+	us.clients[&unixSocketClient{queue: make(chan string, 1)}] = struct{}{}
+
+	for i := 0; i < 4; i++ {
+		us.Write("{}")
+	}
+
+	if count := feeder.GetStats()["socketQueue"]; count != 3 {
+		t.Errorf("[FAIL] Failed to count logs dropped due to full socket queues (%d)", count)
+		return
+	}
+
+	t.Log("[PASS] Counted logs dropped due to full socket queues")
+}
@@ -0,0 +1,353 @@
+package feeder
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	kl "github.com/accuknox/KubeArmor/KubeArmor/common"
+	kg "github.com/accuknox/KubeArmor/KubeArmor/log"
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+// ======================== //
+// == Executable Hashing == //
+// ======================== //
+
+const (
+	// ExecHashSeverity for the logs of mismatched executables
+	ExecHashSeverity = "10"
+
+	// DefaultMaxExecHashes for the hashes cached at once
+	DefaultMaxExecHashes = 4096
+
+	// DefaultExecHashQueueSize for the executables waiting to be hashed
+	DefaultExecHashQueueSize = 256
+
+	// ExecHashFailureLogInterval to log the failures of hashing at most once
+	ExecHashFailureLogInterval = time.Minute
+)
+
+// execHashKey Structure
+type execHashKey struct {
+	path  string
+	dev   uint64
+	ino   uint64
+	size  int64
+	mtime int64
+}
+
+// execHashEntry Structure
+type execHashEntry struct {
+	key  execHashKey
+	hash string
+}
+
+// execHashJob Structure
+type execHashJob struct {
+	key  execHashKey
+	file *os.File
+	done func(hash string)
+}
+
+// ExecHashCache Structure
+type ExecHashCache struct {
+	// path + inode + mtime -> hash (least recently used at the back)
+	hashes     map[execHashKey]*list.Element
+	lruList    *list.List
+	maxEntries int
+	lock       sync.Mutex
+
+	// the failures of hashing since the last log of them
+	failures       uint64
+	failuresLogged time.Time
+
+	// executables to be hashed in the background
+	jobs     chan execHashJob
+	pending  sync.WaitGroup
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewExecHashCache Function
+func NewExecHashCache() *ExecHashCache {
+	hc := &ExecHashCache{}
+
+	hc.hashes = map[execHashKey]*list.Element{}
+	hc.lruList = list.New()
+	hc.maxEntries = DefaultMaxExecHashes
+	hc.lock = sync.Mutex{}
+
+	hc.jobs = make(chan execHashJob, DefaultExecHashQueueSize)
+	hc.stopChan = make(chan struct{})
+
+	hc.wg.Add(1)
+	go hc.hashExecutables()
+
+	return hc
+}
+
+// lookup Function
+func (hc *ExecHashCache) lookup(key execHashKey) (string, bool) {
+	hc.lock.Lock()
+	defer hc.lock.Unlock()
+
+	if elem, ok := hc.hashes[key]; ok {
+		hc.lruList.MoveToFront(elem)
+		return elem.Value.(execHashEntry).hash, true
+	}
+
+	return "", false
+}
+
+// store Function
+func (hc *ExecHashCache) store(key execHashKey, hash string) {
+	hc.lock.Lock()
+	defer hc.lock.Unlock()
+
+	if elem, ok := hc.hashes[key]; ok {
+		hc.lruList.MoveToFront(elem)
+		return
+	}
+
+	hc.hashes[key] = hc.lruList.PushFront(execHashEntry{key: key, hash: hash})
+
+	for hc.lruList.Len() > hc.maxEntries {
+		oldest := hc.lruList.Back()
+		delete(hc.hashes, oldest.Value.(execHashEntry).key)
+		hc.lruList.Remove(oldest)
+	}
+}
+
+// GetCachedHashCount Function
+func (hc *ExecHashCache) GetCachedHashCount() int {
+	hc.lock.Lock()
+	defer hc.lock.Unlock()
+
+	return hc.lruList.Len()
+}
+
+// GetExecHash Function
+//
+// returns the cached hash of the executable at execPath (opened through execPath, cached by path),
+// or queues the executable to be hashed in the background and calls done with its hash later
+func (hc *ExecHashCache) GetExecHash(path, execPath string, done func(hash string)) (string, bool, error) {
+	file, err := os.Open(filepath.Clean(execPath))
+	if err != nil {
+		return "", false, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return "", false, err
+	}
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		file.Close()
+		return "", false, errors.New("no inode information")
+	}
+
+	// a modification in place changes the key
+	key := execHashKey{path: path, dev: uint64(stat.Dev), ino: uint64(stat.Ino), size: info.Size(), mtime: info.ModTime().UnixNano()}
+
+	if hash, ok := hc.lookup(key); ok {
+		file.Close()
+		return hash, true, nil
+	}
+
+	// the open file keeps the executable even if the process exits in the meantime
+	hc.pending.Add(1)
+
+	select {
+	case hc.jobs <- execHashJob{key: key, file: file, done: done}:
+		return "", false, nil
+	default:
+		hc.pending.Done()
+		file.Close()
+		return "", false, errors.New("too many executables to hash")
+	}
+}
+
+// logFailure Function
+func (hc *ExecHashCache) logFailure(path string, err error) {
+	hc.lock.Lock()
+	hc.failures++
+
+	// the processes of short-lived executables are often gone already
+	if time.Since(hc.failuresLogged) < ExecHashFailureLogInterval {
+		hc.lock.Unlock()
+		return
+	}
+
+	failures := hc.failures
+	hc.failures = 0
+	hc.failuresLogged = time.Now()
+	hc.lock.Unlock()
+
+	kg.Debugf("Failed to compute the hash of %s (%s, %d failures in %s)", path, err.Error(), failures, ExecHashFailureLogInterval)
+}
+
+// hashExecutables Function
+func (hc *ExecHashCache) hashExecutables() {
+	defer hc.wg.Done()
+
+	for {
+		select {
+		case <-hc.stopChan:
+			return
+		case job := <-hc.jobs:
+			hash := sha256.New()
+			_, err := io.Copy(hash, job.file)
+			job.file.Close()
+
+			if err == nil {
+				execHash := hex.EncodeToString(hash.Sum(nil))
+				hc.store(job.key, execHash)
+
+				if job.done != nil {
+					job.done(execHash)
+				}
+			}
+
+			hc.pending.Done()
+		}
+	}
+}
+
+// Wait Function
+func (hc *ExecHashCache) Wait() {
+	hc.pending.Wait()
+}
+
+// Stop Function
+func (hc *ExecHashCache) Stop() {
+	close(hc.stopChan)
+	hc.wg.Wait()
+
+	// drop the executables not hashed yet
+	for {
+		select {
+		case job := <-hc.jobs:
+			job.file.Close()
+			hc.pending.Done()
+		default:
+			return
+		}
+	}
+}
+
+// getExecPath Function
+func getExecPath(log tp.Log, resource string) string {
+	// the executable of the running process
+	if log.HostPID > 0 {
+		return fmt.Sprintf("/proc/%d/exe", log.HostPID)
+	}
+
+	return resource
+}
+
+// buildExecHashAlert Function
+func buildExecHashAlert(log tp.Log, secPolicy tp.MatchPolicy) tp.Log {
+	log.PolicyName = secPolicy.PolicyName
+	log.Severity = ExecHashSeverity
+
+	if len(secPolicy.Tags) > 0 {
+		log.Tags = strings.Join(secPolicy.Tags[:], ",")
+	}
+
+	setLogTags(&log, secPolicy.ComplianceTags, secPolicy.MitreTechniques)
+
+	log.Message = "Mismatched executable hash (expected: " + secPolicy.ExecHash + ")"
+
+	if log.ContainerID != "" {
+		log.Type = "MatchedPolicy"
+	} else {
+		log.Type = "MatchedHostPolicy"
+	}
+
+	log.Action = "Audit"
+
+	return log
+}
+
+// matchPinnedHash Function
+//
+// returns the first pinned policy if the hash matches none of the pinned hashes
+// (several policies can pin different hashes for the same path, e.g., during an upgrade)
+func matchPinnedHash(pinned []tp.MatchPolicy, execHash string) (tp.MatchPolicy, bool) {
+	if len(pinned) == 0 {
+		return tp.MatchPolicy{}, false
+	}
+
+	for _, secPolicy := range pinned {
+		if strings.EqualFold(secPolicy.ExecHash, execHash) {
+			return tp.MatchPolicy{}, false
+		}
+	}
+
+	return pinned[0], true
+}
+
+// MatchExecHash Function
+//
+// returns an alert if the executable at a pinned path has an unexpected hash (e.g., a trojaned binary),
+// the alert of an executable not hashed yet is pushed from the background later
+func (fd *Feeder) MatchExecHash(log tp.Log) (tp.Log, bool) {
+	// alerts raised by monitors are not matched with policies
+	if log.Operation != "Process" || log.Result != "Passed" || log.Type == "MatchedPolicy" || log.Type == "MatchedHostPolicy" {
+		return tp.Log{}, false
+	}
+
+	key := log.HostName
+
+	if log.NamespaceName != "" && log.PodName != "" {
+		key = log.NamespaceName + "_" + log.PodName
+	}
+
+	// the canonical path without arguments
+	resource := strings.SplitN(kl.GetCanonicalResource(log.Resource), " ", 2)[0]
+
+	fd.SecurityPoliciesLock.RLock()
+	pinned := []tp.MatchPolicy{}
+	for _, secPolicy := range fd.SecurityPolicies[key].Policies {
+		if secPolicy.Operation == "Process" && secPolicy.ExecHash != "" && secPolicy.Resource == resource {
+			pinned = append(pinned, secPolicy)
+		}
+	}
+	fd.SecurityPoliciesLock.RUnlock()
+
+	if len(pinned) == 0 {
+		return tp.Log{}, false
+	}
+
+	// an executable not hashed yet is checked in the background, and alerted later if mismatched
+	execHash, cached, err := fd.ExecHashes.GetExecHash(resource, getExecPath(log, resource), func(execHash string) {
+		if secPolicy, mismatched := matchPinnedHash(pinned, execHash); mismatched {
+			fd.PushLog(buildExecHashAlert(log, secPolicy))
+		}
+	})
+	if err != nil {
+		// the process may be gone already, so just skip the check
+		fd.ExecHashes.logFailure(resource, err)
+		return tp.Log{}, false
+	} else if !cached {
+		return tp.Log{}, false
+	}
+
+	if secPolicy, mismatched := matchPinnedHash(pinned, execHash); mismatched {
+		return buildExecHashAlert(log, secPolicy), true
+	}
+
+	return tp.Log{}, false
+}
@@ -0,0 +1,207 @@
+package feeder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+func TestMatchExecHash(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubearmor-exec")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a temporary directory (%s)", err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	// create an executable
+
+	execPath := filepath.Join(dir, "sleep")
+	content := []byte("#!/bin/sh\nsleep $1\n")
+
+	if err := ioutil.WriteFile(execPath, content, 0755); err != nil {
+		t.Errorf("[FAIL] Failed to create an executable (%s)", err.Error())
+		return
+	}
+
+	hash := sha256.Sum256(content)
+	execHash := hex.EncodeToString(hash[:])
+
+	logPath := filepath.Join(dir, "kubearmor.log")
+
+	// create Feeder
+	fd := NewFeeder("0", logPath, true)
+	if fd == nil {
+		t.Error("[FAIL] Failed to create Feeder")
+		return
+	}
+	defer fd.DestroyFeeder()
+
+	setPolicy := func(pinnedHashes ...string) {
+		policies := []tp.MatchPolicy{}
+		for idx, pinnedHash := range pinnedHashes {
+			policies = append(policies, tp.MatchPolicy{PolicyName: fmt.Sprintf("ksp-ubuntu-1-proc-path-allow-%d", idx), Severity: "1", Operation: "Process", Resource: execPath, Action: "Allow", ExecHash: pinnedHash})
+		}
+
+		fd.SecurityPoliciesLock.Lock()
+		fd.SecurityPolicies = map[string]tp.MatchPolicies{
+			"multiubuntu_ubuntu-1": {Policies: policies},
+		}
+		fd.SecurityPoliciesLock.Unlock()
+	}
+
+	newLog := func() tp.Log {
+		return tp.Log{UpdatedTime: "now", ContainerID: "ubuntu-1-container", NamespaceName: "multiubuntu", PodName: "ubuntu-1", Operation: "Process", Resource: execPath + " 1", Result: "Passed"}
+	}
+
+	countAlerts := func() int {
+		content, err := ioutil.ReadFile(logPath)
+		if err != nil {
+			return -1
+		}
+		return strings.Count(string(content), "Mismatched executable hash")
+	}
+
+	// the hash matches (hashed in the background first)
+
+	setPolicy(execHash)
+
+	if _, mismatched := fd.MatchExecHash(newLog()); mismatched {
+		t.Error("[FAIL] Flagged an executable not hashed yet")
+		return
+	}
+
+	fd.ExecHashes.Wait()
+
+	if _, mismatched := fd.MatchExecHash(newLog()); mismatched {
+		t.Error("[FAIL] Flagged an executable with the pinned hash")
+		return
+	}
+
+	fd.PushLog(newLog())
+
+	if count := countAlerts(); count != 0 {
+		t.Errorf("[FAIL] Alerted an executable with the pinned hash in the background (%d)", count)
+		return
+	}
+
+	t.Log("[PASS] Allowed an executable with the pinned hash")
+
+	// two policies pin different hashes for the same path
+
+	otherHash := hex.EncodeToString(make([]byte, sha256.Size))
+
+	setPolicy(otherHash, execHash)
+
+	if _, mismatched := fd.MatchExecHash(newLog()); mismatched {
+		t.Error("[FAIL] Flagged an executable with one of the pinned hashes")
+		return
+	}
+
+	fd.PushLog(newLog())
+
+	if count := countAlerts(); count != 0 {
+		t.Errorf("[FAIL] Alerted an executable with one of the pinned hashes (%d)", count)
+		return
+	}
+
+	t.Log("[PASS] Allowed an executable with one of the pinned hashes")
+
+	// the hash differs (cached)
+
+	setPolicy(otherHash)
+
+	log := fd.UpdateMatchedPolicy(newLog())
+	if log.Severity == ExecHashSeverity || log.Action != "Allow" || log.PolicyName != "ksp-ubuntu-1-proc-path-allow-0" {
+		t.Errorf("[FAIL] Skipped the policy matching of an executable with an unexpected hash (%v)", log)
+		return
+	}
+
+	fd.PushLog(newLog())
+
+	if count := countAlerts(); count != 1 {
+		t.Errorf("[FAIL] Failed to alert an executable with an unexpected hash (%d)", count)
+		return
+	}
+
+	if alert, mismatched := fd.MatchExecHash(newLog()); !mismatched || alert.Severity != ExecHashSeverity || alert.Action != "Audit" {
+		t.Errorf("[FAIL] Failed to return the alert of an executable with an unexpected hash (%v)", alert)
+		return
+	}
+
+	t.Log("[PASS] Alerted an executable with an unexpected hash")
+
+	// the executable is replaced (hashed in the background again)
+
+	setPolicy(execHash)
+
+	if err := ioutil.WriteFile(execPath, []byte("#!/bin/sh\ncat /etc/shadow\n"), 0755); err != nil {
+		t.Errorf("[FAIL] Failed to replace an executable (%s)", err.Error())
+		return
+	}
+
+	if _, mismatched := fd.MatchExecHash(newLog()); mismatched {
+		t.Error("[FAIL] Flagged an executable not hashed yet")
+		return
+	}
+
+	fd.ExecHashes.Wait()
+
+	if count := countAlerts(); count != 2 {
+		t.Errorf("[FAIL] Failed to alert a replaced executable in the background (%d)", count)
+		return
+	}
+
+	if _, mismatched := fd.MatchExecHash(newLog()); !mismatched {
+		t.Error("[FAIL] Used a stale hash for a replaced executable")
+		return
+	}
+
+	t.Log("[PASS] Alerted a replaced executable")
+
+	// the hash cannot be computed
+
+	os.Remove(execPath)
+
+	if _, mismatched := fd.MatchExecHash(newLog()); mismatched {
+		t.Error("[FAIL] Flagged an executable whose hash cannot be computed")
+		return
+	}
+
+	t.Log("[PASS] Skipped an executable whose hash cannot be computed")
+}
+
+func TestExecHashCacheBound(t *testing.T) {
+	hc := NewExecHashCache()
+	defer hc.Stop()
+
+	hc.maxEntries = 2
+
+	for idx, path := range []string{"/bin/sleep", "/bin/cat", "/bin/ls"} {
+		hc.store(execHashKey{path: path, ino: uint64(idx)}, path)
+
+		if idx == 1 {
+			// recently used
+			hc.lookup(execHashKey{path: "/bin/sleep", ino: 0})
+		}
+	}
+
+	if _, ok := hc.lookup(execHashKey{path: "/bin/cat", ino: 1}); ok || hc.GetCachedHashCount() != 2 {
+		t.Errorf("[FAIL] Failed to evict the least recently used hash (%d hashes)", hc.GetCachedHashCount())
+		return
+	}
+
+	if _, ok := hc.lookup(execHashKey{path: "/bin/sleep", ino: 0}); !ok {
+		t.Error("[FAIL] Evicted a recently used hash")
+		return
+	}
+
+	t.Log("[PASS] Bounded cached hashes")
+}
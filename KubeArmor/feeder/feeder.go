@@ -2,11 +2,14 @@ package feeder
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,6 +19,7 @@ import (
 
 	pb "github.com/accuknox/KubeArmor/protobuf"
 	"github.com/google/uuid"
+	"github.com/soheilhy/cmux"
 	"google.golang.org/grpc"
 )
 
@@ -23,9 +27,6 @@ import (
 // == Global == //
 // ============ //
 
-// Running flag
-var Running bool
-
 // MsgQueue for Messages
 var MsgQueue []pb.Message
 
@@ -38,14 +39,16 @@ var LogQueue []pb.Log
 // LogLock for Logs
 var LogLock sync.Mutex
 
-func init() {
-	Running = true
+// LogSeq for the sequence number of the last log
+var LogSeq uint64
 
+func init() {
 	MsgQueue = []pb.Message{}
 	MsgLock = sync.Mutex{}
 
 	LogQueue = []pb.Log{}
 	LogLock = sync.Mutex{}
+	LogSeq = 0
 }
 
 // ========== //
@@ -62,6 +65,9 @@ type MsgStruct struct {
 type LogStruct struct {
 	Client pb.LogService_WatchLogsServer
 	Filter string
+
+	// live logs held while backfilling (nil if not backfilled)
+	Backlog *LogBacklog
 }
 
 // LogService Structure
@@ -71,6 +77,53 @@ type LogService struct {
 
 	LogStructs map[string]LogStruct
 	LogLock    sync.Mutex
+
+	// serialize the deliveries of logs to clients
+	SendLock sync.Mutex
+
+	// the maximum number of logs queued for clients (LogLock should be held)
+	MaxQueuedLogs int
+
+	AckConsumers    map[string]*AckConsumer
+	AckLock         sync.Mutex
+	MaxUnackedLogs  int
+	MaxAckConsumers int
+	AckConsumerTTL  time.Duration
+
+	// the number of dropped events by reason
+	DropStats *DropStats
+
+	// backfill from the file sink (nil if disabled)
+	Backfill *LogBackfill
+
+	// container id -> pid (nil until a process tree is set)
+	ActivePidMap     *map[string]tp.PidMap
+	ActivePidMapLock **sync.RWMutex
+
+	// closed when the service stops
+	stopChan chan struct{}
+	stopOnce sync.Once
+}
+
+// isRunning Function
+func (ls *LogService) isRunning() bool {
+	select {
+	case <-ls.stopChan:
+		return false
+	default:
+		return true
+	}
+}
+
+// Stop Function
+func (ls *LogService) Stop() {
+	if ls.stopChan == nil {
+		return
+	}
+
+	ls.stopOnce.Do(func() {
+		close(ls.stopChan)
+	})
 }
 
 // HealthCheck Function
@@ -120,7 +173,7 @@ func (ls *LogService) WatchMessages(req *pb.RequestMessage, svr pb.LogService_Wa
 	ls.addMsgStruct(uid, svr, req.Filter)
 	defer ls.removeMsgStruct(uid)
 
-	for Running {
+	for ls.isRunning() {
 		MsgLock.Lock()
 
 		msgStructs := ls.getMsgStructs()
@@ -143,13 +196,14 @@ func (ls *LogService) WatchMessages(req *pb.RequestMessage, svr pb.LogService_Wa
 }
 
 // addLogStruct Function
-func (ls *LogService) addLogStruct(uid string, srv pb.LogService_WatchLogsServer, filter string) {
+func (ls *LogService) addLogStruct(uid string, srv pb.LogService_WatchLogsServer, filter string, backlog *LogBacklog) {
 	ls.LogLock.Lock()
 	defer ls.LogLock.Unlock()
 
 	logStruct := LogStruct{}
 	logStruct.Client = srv
 	logStruct.Filter = filter
+	logStruct.Backlog = backlog
 
 	ls.LogStructs[uid] = logStruct
 }
@@ -176,34 +230,70 @@ func (ls *LogService) getLogStructs() []LogStruct {
 	return logStructs
 }
 
-// WatchLogs Function
-func (ls *LogService) WatchLogs(req *pb.RequestMessage, svr pb.LogService_WatchLogsServer) error {
-	uid := uuid.Must(uuid.NewRandom()).String()
+// sendLogs Function
+func (ls *LogService) sendLogs() {
+	ls.SendLock.Lock()
+	defer ls.SendLock.Unlock()
 
-	ls.addLogStruct(uid, svr, req.Filter)
-	defer ls.removeLogStruct(uid)
+	ls.flushLogs()
+}
+
+// flushLogs Function (SendLock should be held)
+func (ls *LogService) flushLogs() {
+	// take the queued logs at once so that producers never wait for clients
+	LogLock.Lock()
+	logs := LogQueue
+	LogQueue = []pb.Log{}
+	LogLock.Unlock()
 
-	for Running {
-		LogLock.Lock()
+	if len(logs) == 0 {
+		return
+	}
 
-		logStructs := ls.getLogStructs()
+	logStructs := ls.getLogStructs()
+	now := time.Now()
 
-		for len(LogQueue) != 0 {
-			log := LogQueue[0]
-			LogQueue = LogQueue[1:]
+	for idx := range logs {
+		log := &logs[idx]
 
-			for _, lgs := range logStructs {
-				if lgs.Filter == "" {
-					lgs.Client.Send(&log)
-				} else if lgs.Filter == "policy" && (log.Type == "MatchedPolicy" || log.Type == "MatchedHostPolicy") {
-					lgs.Client.Send(&log)
-				} else if lgs.Filter == "system" && (log.Type == "ContainerLog" || log.Type == "HostLog") {
-					lgs.Client.Send(&log)
+		for _, lgs := range logStructs {
+			if matchLogFilter(lgs.Filter, log) {
+				if lgs.Backlog.Hold(log, ls.DropStats) {
+					continue
 				}
+				lgs.Client.Send(log)
 			}
 		}
 
-		LogLock.Unlock()
+		ls.sendAckedLog(log, now)
+	}
+}
+
+// WatchLogs Function
+func (ls *LogService) WatchLogs(req *pb.RequestMessage, svr pb.LogService_WatchLogsServer) error {
+	uid := uuid.Must(uuid.NewRandom()).String()
+
+	if req.Backfill && ls.Backfill != nil {
+		backlog := NewLogBacklog(DefaultMaxBacklogLogs)
+
+		// take the position in the live logs, and hold the live logs for this client from there
+		// (a log being written at this moment can be given twice, but never missed)
+		ls.SendLock.Lock()
+		ls.flushLogs()
+		ls.addLogStruct(uid, svr, req.Filter, backlog)
+		ls.SendLock.Unlock()
+		defer ls.removeLogStruct(uid)
+
+		// replay the tail without blocking the deliveries to the others
+		ls.backfillLogs(svr, req.Filter)
+		backlog.Release(svr)
+	} else {
+		ls.addLogStruct(uid, svr, req.Filter, nil)
+		defer ls.removeLogStruct(uid)
+	}
+
+	for ls.isRunning() {
+		ls.sendLogs()
 
 		time.Sleep(time.Millisecond * 1)
 	}
@@ -223,12 +313,40 @@ type Feeder struct {
 	// output
 	output string
 
+	// namespace sink (only for the namespace output mode)
+	namespaceSink *NamespaceSink
+
+	// unix socket sink (only for the unix socket output mode)
+	unixSocketSink *UnixSocketSink
+
 	// gRPC listener
 	listener net.Listener
 
 	// log server
 	logServer *grpc.Server
 
+	// log service
+	logService *LogService
+
+	// metrics port (the same as the gRPC port if multiplexed)
+	metricsPort string
+
+	// metrics listener (only if a separate port is used)
+	metricsListener net.Listener
+
+	// metrics server
+	metricsServer *http.Server
+	metricsMux    *http.ServeMux
+
+	// clients of the NDJSON log stream (nil if disabled)
+	logStreams        map[*logStreamClient]struct{}
+	logStreamsLock    sync.Mutex
+	logStreamStopChan chan struct{}
+	maxLogStreams     int
+
+	// TLS configuration applied to both gRPC and metrics
+	tlsConfig *tls.Config
+
 	// wait group
 	WgServer sync.WaitGroup
 
@@ -243,8 +361,40 @@ type Feeder struct {
 	SecurityPolicies     map[string]tp.MatchPolicies
 	SecurityPoliciesLock *sync.RWMutex
 
+	// metrics
+	Metrics *Metrics
+
+	// the number of dropped events by reason
+	DropStats *DropStats
+
+	// stop channel for the logs of dropped events (nil if disabled)
+	dropLogStopChan chan struct{}
+
+	// cache for the hashes of executables
+	ExecHashes *ExecHashCache
+
+	// throttle for repeated Block decisions (nil if disabled)
+	blockThrottle     *BlockThrottle
+	blockThrottleLock sync.RWMutex
+
+	// tracker for the changes of decisions (nil if disabled)
+	decisionTracker *DecisionTracker
+
+	// notifier for the matches of Quarantine policies (nil if disabled)
+	webhookNotifier *WebhookNotifier
+
+	// namespace name + container group name -> severity delta
+	SeverityEscalations []SeverityEscalation
+	Escalations         map[string]int
+	EscalationsLock     *sync.RWMutex
+
+	// namespace name + container group name -> owning workload (kind/name)
+	Workloads     map[string]string
+	WorkloadsLock *sync.RWMutex
+
 	// options
-	EnableSystemLog bool
+	EnableSystemLog     bool
+	EnableDecisionPoint bool
 }
 
 // NewFeeder Function
@@ -254,8 +404,26 @@ func NewFeeder(port, output string, enableSystemLog bool) *Feeder {
 	fd.port = fmt.Sprintf(":%s", port)
 	fd.output = output
 
+	// initialize drop stats
+	fd.DropStats = NewDropStats()
+
 	// output mode
-	if fd.output != "stdout" && fd.output != "none" {
+	if strings.HasPrefix(fd.output, NamespaceSinkPrefix) {
+		namespaceSink, err := NewNamespaceSink(fd.output)
+		if err != nil {
+			kg.Errf("Failed to create a namespace sink (%s, %s)", fd.output, err.Error())
+			return nil
+		}
+		fd.namespaceSink = namespaceSink
+	} else if strings.HasPrefix(fd.output, UnixSocketSinkPrefix) {
+		unixSocketSink, err := NewUnixSocketSink(fd.output)
+		if err != nil {
+			kg.Errf("Failed to create a unix socket sink (%s, %s)", fd.output, err.Error())
+			return nil
+		}
+		unixSocketSink.dropStats = fd.DropStats
+		fd.unixSocketSink = unixSocketSink
+	} else if fd.output != "stdout" && fd.output != "none" {
 		// get the directory part from the path
 		dirLog := filepath.Dir(fd.output)
 
@@ -265,8 +433,8 @@ func NewFeeder(port, output string, enableSystemLog bool) *Feeder {
 			return nil
 		}
 
-		// create target file
-		targetFile, err := os.Create(fd.output)
+		// create target file (the logs written before a restart are truncated or kept for backfill by SetLogBackfill)
+		targetFile, err := os.OpenFile(fd.output, os.O_CREATE|os.O_WRONLY, 0644)
 		if err != nil {
 			kg.Errf("Failed to create a target file (%s, %s)", fd.output, err.Error())
 			return nil
@@ -291,8 +459,26 @@ func NewFeeder(port, output string, enableSystemLog bool) *Feeder {
 		MsgLock:    sync.Mutex{},
 		LogStructs: make(map[string]LogStruct),
 		LogLock:    sync.Mutex{},
+
+		SendLock: sync.Mutex{},
+
+		MaxQueuedLogs: DefaultMaxUnackedLogs,
+
+		AckConsumers:    make(map[string]*AckConsumer),
+		AckLock:         sync.Mutex{},
+		MaxUnackedLogs:  DefaultMaxUnackedLogs,
+		MaxAckConsumers: DefaultMaxAckConsumers,
+		AckConsumerTTL:  DefaultAckConsumerTTL,
+
+		DropStats: fd.DropStats,
+
+		stopChan: make(chan struct{}),
 	}
 	pb.RegisterLogServiceServer(fd.logServer, logService)
+	fd.logService = logService
+
+	// metrics are disabled by default
+	fd.metricsPort = "none"
 
 	// set wait group
 	fd.WgServer = sync.WaitGroup{}
@@ -308,6 +494,21 @@ func NewFeeder(port, output string, enableSystemLog bool) *Feeder {
 	fd.SecurityPolicies = map[string]tp.MatchPolicies{}
 	fd.SecurityPoliciesLock = new(sync.RWMutex)
 
+	// initialize metrics
+	fd.Metrics = NewMetrics()
+
+	// initialize the hash cache
+	fd.ExecHashes = NewExecHashCache()
+
+	// initialize severity escalations
+	fd.SeverityEscalations = []SeverityEscalation{}
+	fd.Escalations = map[string]int{}
+	fd.EscalationsLock = new(sync.RWMutex)
+
+	// initialize workloads
+	fd.Workloads = map[string]string{}
+	fd.WorkloadsLock = new(sync.RWMutex)
+
 	// options
 	fd.EnableSystemLog = enableSystemLog
 
@@ -317,20 +518,75 @@ func NewFeeder(port, output string, enableSystemLog bool) *Feeder {
 // DestroyFeeder Function
 func (fd *Feeder) DestroyFeeder() error {
 	// stop gRPC service
-	Running = false
+	fd.logService.Stop()
 
 	// wait for a while
 	time.Sleep(time.Second * 1)
 
+	// disconnect the clients of the log stream
+	fd.logStreamsLock.Lock()
+	if fd.logStreamStopChan != nil {
+		close(fd.logStreamStopChan)
+		fd.logStreamStopChan = nil
+	}
+	fd.logStreamsLock.Unlock()
+
+	// close metrics server
+	if fd.metricsServer != nil {
+		fd.metricsServer.Close()
+		fd.metricsServer = nil
+	}
+
+	// close metrics listener
+	if fd.metricsListener != nil {
+		fd.metricsListener.Close()
+		fd.metricsListener = nil
+	}
+
 	// close listener
 	if fd.listener != nil {
 		fd.listener.Close()
 		fd.listener = nil
 	}
 
+	// stop summarizing Block decisions
+	fd.blockThrottleLock.Lock()
+	if fd.blockThrottle != nil {
+		fd.blockThrottle.Stop()
+		fd.blockThrottle = nil
+	}
+	fd.blockThrottleLock.Unlock()
+
+	// stop notifying the webhook
+	if fd.webhookNotifier != nil {
+		fd.webhookNotifier.Stop()
+		fd.webhookNotifier = nil
+	}
+
+	// stop hashing executables
+	if fd.ExecHashes != nil {
+		fd.ExecHashes.Stop()
+	}
+
+	// stop logging dropped events
+	if fd.dropLogStopChan != nil {
+		close(fd.dropLogStopChan)
+		fd.dropLogStopChan = nil
+	}
+
 	// wait for other routines
 	fd.WgServer.Wait()
 
+	// close namespace sink
+	if fd.namespaceSink != nil {
+		fd.namespaceSink.Close()
+	}
+
+	// close unix socket sink
+	if fd.unixSocketSink != nil {
+		fd.unixSocketSink.Close()
+	}
+
 	return nil
 }
 
@@ -381,13 +637,125 @@ func (fd *Feeder) Errf(message string, args ...interface{}) {
 // == Log Feeds == //
 // =============== //
 
+// SetTLSConfig Function
+func (fd *Feeder) SetTLSConfig(certPath, keyPath string) error {
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return err
+	}
+
+	fd.tlsConfig = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+
+	return nil
+}
+
+// EnableMetrics Function
+func (fd *Feeder) EnableMetrics(port string) error {
+	fd.metricsPort = fmt.Sprintf(":%s", port)
+
+	// create a metrics server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", fd.MetricsHandler)
+	fd.metricsServer = &http.Server{Handler: mux}
+	fd.metricsMux = mux
+
+	// the metrics will be multiplexed with gRPC
+	if fd.metricsPort == fd.port {
+		return nil
+	}
+
+	// listen to metrics port
+	listener, err := net.Listen("tcp", fd.metricsPort)
+	if err != nil {
+		return err
+	}
+	fd.metricsListener = listener
+
+	return nil
+}
+
+// SetMaxUnackedLogs Function
+func (fd *Feeder) SetMaxUnackedLogs(maxUnackedLogs int) error {
+	if maxUnackedLogs < 1 {
+		return fmt.Errorf("invalid number (%d)", maxUnackedLogs)
+	}
+
+	fd.logService.AckLock.Lock()
+	fd.logService.MaxUnackedLogs = maxUnackedLogs
+	fd.logService.AckLock.Unlock()
+
+	// no more logs than unacked ones are kept while no client takes them
+	LogLock.Lock()
+	fd.logService.MaxQueuedLogs = maxUnackedLogs
+	LogLock.Unlock()
+
+	return nil
+}
+
 // ServeLogFeeds Function
 func (fd *Feeder) ServeLogFeeds() {
 	fd.WgServer.Add(1)
 	defer fd.WgServer.Done()
 
+	if fd.metricsPort == fd.port && fd.tlsConfig != nil {
+		// ALPN is negotiated before connections could be split, so both gRPC and HTTP are served over one TLS server
+		server := fd.metricsServer
+		server.Handler = fd.grpcOrHTTPHandler(fd.metricsMux)
+		server.TLSConfig = fd.tlsConfig
+
+		server.ServeTLS(fd.listener, "", "")
+		return
+	}
+
+	if fd.metricsPort == fd.port {
+		// split connections into gRPC and HTTP ones
+		mux := cmux.New(fd.listener)
+
+		grpcListener := mux.MatchWithWriters(cmux.HTTP2MatchHeaderFieldSendSettings("content-type", "application/grpc"))
+		httpListener := mux.Match(cmux.Any())
+
+		// feed logs
+		go fd.logServer.Serve(grpcListener)
+
+		// serve metrics
+		go fd.metricsServer.Serve(httpListener)
+
+		mux.Serve()
+		return
+	}
+
+	if fd.metricsListener != nil {
+		// serve metrics
+		if fd.tlsConfig != nil {
+			fd.metricsServer.TLSConfig = fd.tlsConfig
+			go fd.metricsServer.ServeTLS(fd.metricsListener, "", "")
+		} else {
+			go fd.metricsServer.Serve(fd.metricsListener)
+		}
+	}
+
+	listener := fd.listener
+	if fd.tlsConfig != nil {
+		listener = tls.NewListener(listener, fd.tlsConfig)
+	}
+
 	// feed logs
-	fd.logServer.Serve(fd.listener)
+	fd.logServer.Serve(listener)
+}
+
+// grpcOrHTTPHandler Function
+func (fd *Feeder) grpcOrHTTPHandler(httpHandler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			fd.logServer.ServeHTTP(w, r)
+			return
+		}
+
+		httpHandler.ServeHTTP(w, r)
+	})
 }
 
 // PushMessage Function
@@ -404,6 +772,8 @@ func (fd *Feeder) PushMessage(level, message string) error {
 	pbMsg.Level = level
 	pbMsg.Message = message
 
+	fd.Metrics.CountMessage()
+
 	MsgLock.Lock()
 	MsgQueue = append(MsgQueue, pbMsg)
 	MsgLock.Unlock()
@@ -413,35 +783,103 @@ func (fd *Feeder) PushMessage(level, message string) error {
 
 // PushLog Function
 func (fd *Feeder) PushLog(log tp.Log) error {
-	log = fd.UpdateMatchedPolicy(log)
+	// check the executable at a pinned path, and emit the alert as an extra log after the log
+	alert, mismatched := fd.MatchExecHash(log)
+
+	err := fd.pushMatchedLog(log)
+
+	if mismatched {
+		fd.pushMatchedLog(alert)
+	}
+
+	return err
+}
+
+// pushMatchedLog Function
+func (fd *Feeder) pushMatchedLog(log tp.Log) error {
+	matched := fd.UpdateMatchedPolicy(log)
+
+	if matched.UpdatedTime == "" {
+		// record the implicit Allow of a filtered log (e.g., system logs when disabled)
+		fd.TrackDecision(log)
 
-	if log.UpdatedTime == "" {
+		// not a drop, but the normal path for the events matching no policy
+		fd.Metrics.CountFiltered()
 		return nil
 	}
 
+	log = matched
+
+	// adjust the severity of matched policies for sensitive workloads
+	log = fd.EscalateSeverity(log)
+
+	// notify the webhook of a Quarantine decision
+	if fd.webhookNotifier != nil {
+		log.Notified = fd.webhookNotifier.Notify(log, time.Now())
+	}
+
+	// emit a log if the decision for the resource has changed
+	fd.TrackDecision(log)
+
+	// collapse repeated identical Block decisions
+	if blockThrottle := fd.getBlockThrottle(); blockThrottle != nil && !blockThrottle.Allow(log, time.Now()) {
+		fd.CountDrop(DropReasonThrottle)
+		return nil
+	}
+
+	return fd.pushLog(log)
+}
+
+// pushLog Function
+func (fd *Feeder) pushLog(log tp.Log) error {
+	fd.Metrics.CountLog(log.Type)
+
+	if log.NamespaceName != "" && log.PodName != "" {
+		log.Workload = fd.GetWorkload(log.NamespaceName, log.PodName)
+	}
+
 	// standard output / file output
 
 	if fd.output == "stdout" {
 		arr, _ := json.Marshal(log)
 		fmt.Println(string(arr))
+	} else if fd.namespaceSink != nil {
+		arr, _ := json.Marshal(log)
+		if err := fd.namespaceSink.Write(log.NamespaceName, string(arr)); err != nil {
+			kg.Errf("Failed to write a log (%s, %s)", log.NamespaceName, err.Error())
+		}
+	} else if fd.unixSocketSink != nil {
+		arr, _ := json.Marshal(log)
+		fd.unixSocketSink.Write(string(arr))
 	} else if fd.output != "none" {
 		arr, _ := json.Marshal(log)
 		kl.StrToFile(string(arr), fd.output)
 	}
 
+	// NDJSON log stream output
+
+	fd.streamLog(log)
+
 	// gRPC output
 
-	pbLog := pb.Log{}
+	fd.queueLog(log)
+
+	return nil
+}
 
+// buildPbLog Function
+func buildPbLog(pbLog *pb.Log, clusterName string, log tp.Log) {
 	pbLog.UpdatedTime = log.UpdatedTime
 
-	pbLog.ClusterName = fd.clusterName
+	pbLog.ClusterName = clusterName
 	pbLog.HostName = log.HostName
 
 	pbLog.NamespaceName = log.NamespaceName
 	pbLog.PodName = log.PodName
+	pbLog.Workload = log.Workload
 	pbLog.ContainerID = log.ContainerID
 	pbLog.ContainerName = log.ContainerName
+	pbLog.Ambiguous = log.Ambiguous
 
 	pbLog.HostPID = log.HostPID
 	pbLog.PPID = log.PPID
@@ -452,10 +890,18 @@ func (fd *Feeder) PushLog(log tp.Log) error {
 		pbLog.PolicyName = log.PolicyName
 	}
 
+	if len(log.PolicySet) > 0 {
+		pbLog.PolicySet = log.PolicySet
+	}
+
 	if len(log.Severity) > 0 {
 		pbLog.Severity = log.Severity
 	}
 
+	if len(log.OriginalSeverity) > 0 {
+		pbLog.OriginalSeverity = log.OriginalSeverity
+	}
+
 	if len(log.Tags) > 0 {
 		pbLog.Tags = log.Tags
 	}
@@ -464,6 +910,14 @@ func (fd *Feeder) PushLog(log tp.Log) error {
 		pbLog.Message = log.Message
 	}
 
+	if len(log.ComplianceTags) > 0 {
+		pbLog.ComplianceTags = log.ComplianceTags
+	}
+
+	if len(log.MitreTechniques) > 0 {
+		pbLog.MitreTechniques = log.MitreTechniques
+	}
+
 	pbLog.Type = log.Type
 	pbLog.Source = log.Source
 	pbLog.Operation = log.Operation
@@ -479,9 +933,45 @@ func (fd *Feeder) PushLog(log tp.Log) error {
 
 	pbLog.Result = log.Result
 
+	if len(log.DecisionPoint) > 0 {
+		pbLog.DecisionPoint = log.DecisionPoint
+		pbLog.MatchLatency = log.MatchLatency
+	}
+
+	if log.Notified {
+		pbLog.Notified = log.Notified
+	}
+
+	if len(log.InterpretedCommand) > 0 {
+		pbLog.InterpretedCommand = log.InterpretedCommand
+	}
+
+	if log.Count > 0 {
+		pbLog.Count = log.Count
+	}
+}
+
+// queueLog Function
+func (fd *Feeder) queueLog(log tp.Log) {
+	pbLog := pb.Log{}
+	buildPbLog(&pbLog, fd.clusterName, log)
+
+	dropped := 0
+
 	LogLock.Lock()
+	LogSeq++
+	pbLog.Seq = LogSeq
+
+	// drop the oldest queued logs if no client takes them (e.g., all ack consumers are disconnected)
+	if fd.logService != nil && fd.logService.MaxQueuedLogs > 0 && len(LogQueue) >= fd.logService.MaxQueuedLogs {
+		dropped = len(LogQueue) - fd.logService.MaxQueuedLogs + 1
+		LogQueue = LogQueue[dropped:]
+	}
+
 	LogQueue = append(LogQueue, pbLog)
 	LogLock.Unlock()
 
-	return nil
+	if dropped > 0 {
+		fd.DropStats.Add(DropReasonLogQueue, uint64(dropped))
+	}
 }
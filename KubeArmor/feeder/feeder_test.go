@@ -1,7 +1,27 @@
 package feeder
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	pb "github.com/accuknox/KubeArmor/protobuf"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 func TestFeeder(t *testing.T) {
@@ -22,3 +42,219 @@ func TestFeeder(t *testing.T) {
 
 	t.Log("[PASS] Destroyed Feeder")
 }
+
+func TestUnifiedServerMux(t *testing.T) {
+	// create Feeder
+	feeder := NewFeeder("0", "none", false)
+	if feeder == nil {
+		t.Error("[FAIL] Failed to create Feeder")
+		return
+	}
+	defer feeder.DestroyFeeder()
+
+	// share the gRPC port with metrics
+	if err := feeder.EnableMetrics("0"); err != nil {
+		t.Errorf("[FAIL] Failed to enable metrics (%s)", err.Error())
+		return
+	}
+
+	addr := feeder.listener.Addr().String()
+
+	go feeder.ServeLogFeeds()
+
+	t.Log("[PASS] Started to serve gRPC and metrics on the same port")
+
+	// gRPC
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, addr, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		t.Errorf("[FAIL] Failed to connect to the gRPC server (%s)", err.Error())
+		return
+	}
+	defer conn.Close()
+
+	client := pb.NewLogServiceClient(conn)
+
+	res, err := client.HealthCheck(ctx, &pb.NonceMessage{Nonce: 1234})
+	if err != nil || res.Retval != 1234 {
+		t.Error("[FAIL] Failed to check the liveness of the gRPC server")
+		return
+	}
+
+	t.Log("[PASS] Checked the liveness of the gRPC server")
+
+	// metrics
+
+	feeder.PushMessage("INFO", "test message")
+
+	resp, err := http.Get("http://" + addr + "/metrics")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to get metrics (%s)", err.Error())
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to read metrics (%s)", err.Error())
+		return
+	}
+
+	if !strings.Contains(string(body), "kubearmor_messages_total 1") {
+		t.Errorf("[FAIL] Failed to get the message count from metrics (%s)", string(body))
+		return
+	}
+
+	t.Log("[PASS] Got metrics")
+}
+
+// writeTestCertificate Function
+func writeTestCertificate(dir string) (string, string, *x509.CertPool, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "kubearmor"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1"), net.IPv6loopback},
+		DNSNames:     []string{"localhost"},
+
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	certPath := filepath.Join(dir, "tls.crt")
+	keyPath := filepath.Join(dir, "tls.key")
+
+	if err := ioutil.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		return "", "", nil, err
+	}
+
+	if err := ioutil.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		return "", "", nil, err
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return certPath, keyPath, pool, nil
+}
+
+func TestUnifiedServerMuxWithTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubearmor-tls")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a temporary directory (%s)", err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	certPath, keyPath, pool, err := writeTestCertificate(dir)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a certificate (%s)", err.Error())
+		return
+	}
+
+	// create Feeder
+	feeder := NewFeeder("0", "none", false)
+	if feeder == nil {
+		t.Error("[FAIL] Failed to create Feeder")
+		return
+	}
+	defer feeder.DestroyFeeder()
+
+	if err := feeder.SetTLSConfig(certPath, keyPath); err != nil {
+		t.Errorf("[FAIL] Failed to set the TLS configuration (%s)", err.Error())
+		return
+	}
+
+	// share the gRPC port with metrics
+	if err := feeder.EnableMetrics("0"); err != nil {
+		t.Errorf("[FAIL] Failed to enable metrics (%s)", err.Error())
+		return
+	}
+
+	addr := feeder.listener.Addr().String()
+
+	go feeder.ServeLogFeeds()
+
+	t.Log("[PASS] Started to serve gRPC and metrics over TLS on the same port")
+
+	// gRPC
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	creds := credentials.NewTLS(&tls.Config{RootCAs: pool, ServerName: "localhost"})
+
+	conn, err := grpc.DialContext(ctx, addr, grpc.WithTransportCredentials(creds), grpc.WithBlock())
+	if err != nil {
+		t.Errorf("[FAIL] Failed to connect to the gRPC server (%s)", err.Error())
+		return
+	}
+	defer conn.Close()
+
+	client := pb.NewLogServiceClient(conn)
+
+	res, err := client.HealthCheck(ctx, &pb.NonceMessage{Nonce: 1234})
+	if err != nil || res.Retval != 1234 {
+		t.Errorf("[FAIL] Failed to check the liveness of the gRPC server (%v)", err)
+		return
+	}
+
+	t.Log("[PASS] Checked the liveness of the gRPC server over TLS")
+
+	// metrics over HTTP/2 and HTTP/1.1
+
+	feeder.PushMessage("INFO", "test message")
+
+	for _, forceHTTP2 := range []bool{true, false} {
+		httpClient := &http.Client{Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{RootCAs: pool, ServerName: "localhost"},
+			ForceAttemptHTTP2: forceHTTP2,
+		}}
+
+		resp, err := httpClient.Get("https://" + addr + "/metrics")
+		if err != nil {
+			t.Errorf("[FAIL] Failed to get metrics (%s)", err.Error())
+			return
+		}
+
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Errorf("[FAIL] Failed to read metrics (%s)", err.Error())
+			return
+		}
+
+		if (resp.ProtoMajor == 2) != forceHTTP2 || !strings.Contains(string(body), "kubearmor_messages_total 1") {
+			t.Errorf("[FAIL] Failed to get metrics over %s (%s)", resp.Proto, string(body))
+			return
+		}
+
+		t.Logf("[PASS] Got metrics over %s", resp.Proto)
+	}
+}
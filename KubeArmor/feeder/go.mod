@@ -18,5 +18,6 @@ require (
 	github.com/accuknox/KubeArmor/KubeArmor/types v0.0.0-00010101000000-000000000000
 	github.com/accuknox/KubeArmor/protobuf v0.0.0-00010101000000-000000000000
 	github.com/google/uuid v1.1.2
+	github.com/soheilhy/cmux v0.1.5
 	google.golang.org/grpc v1.34.0
 )
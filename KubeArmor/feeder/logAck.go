@@ -0,0 +1,261 @@
+package feeder
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "github.com/accuknox/KubeArmor/protobuf"
+)
+
+// ============== //
+// == Log Acks == //
+// ============== //
+
+const (
+	// DefaultMaxUnackedLogs for each consumer
+	DefaultMaxUnackedLogs = 10000
+
+	// DefaultMaxAckConsumers for the consumers kept at once
+	DefaultMaxAckConsumers = 64
+
+	// DefaultAckConsumerTTL to keep the unacked logs of a disconnected consumer
+	DefaultAckConsumerTTL = time.Minute * 10
+)
+
+// AckConsumer Structure
+type AckConsumer struct {
+	// connected client (nil if disconnected)
+	Client pb.LogService_WatchLogsWithAckServer
+	Filter string
+
+	// seq -> unacked log (the oldest at the front)
+	pendingLogs map[uint64]*list.Element
+	pendingList *list.List
+
+	// the number of unacked logs dropped due to the bound
+	DropCount uint64
+
+	// the last time when the consumer connected, acked, or disconnected
+	lastSeen time.Time
+}
+
+// matchLogFilter Function
+func matchLogFilter(filter string, log *pb.Log) bool {
+	// decision changes are only given to the clients watching them
+	if log.Operation == DecisionChangeOperation {
+		return filter == "decision"
+	}
+
+	if filter == "" {
+		return true
+	} else if filter == "policy" && (log.Type == "MatchedPolicy" || log.Type == "MatchedHostPolicy") {
+		return true
+	} else if filter == "system" && (log.Type == "ContainerLog" || log.Type == "HostLog") {
+		return true
+	}
+
+	return false
+}
+
+// evictAckConsumer Function (AckLock should be held)
+func (ls *LogService) evictAckConsumer() bool {
+	evictedID := ""
+	evictedTime := time.Time{}
+
+	// the least recently seen one among disconnected consumers
+	for consumerID, consumer := range ls.AckConsumers {
+		if consumer.Client != nil {
+			continue
+		}
+
+		if evictedID == "" || consumer.lastSeen.Before(evictedTime) {
+			evictedID = consumerID
+			evictedTime = consumer.lastSeen
+		}
+	}
+
+	if evictedID == "" {
+		return false
+	}
+
+	ls.DropStats.Add(DropReasonUnackedLog, uint64(ls.AckConsumers[evictedID].pendingList.Len()))
+	delete(ls.AckConsumers, evictedID)
+
+	return true
+}
+
+// connectAckConsumer Function
+func (ls *LogService) connectAckConsumer(consumerID, filter string, svr pb.LogService_WatchLogsWithAckServer) error {
+	// no live log comes before the retransmitted ones
+	ls.SendLock.Lock()
+	defer ls.SendLock.Unlock()
+
+	ls.AckLock.Lock()
+
+	consumer, ok := ls.AckConsumers[consumerID]
+	if !ok {
+		if len(ls.AckConsumers) >= ls.MaxAckConsumers && !ls.evictAckConsumer() {
+			ls.AckLock.Unlock()
+			return fmt.Errorf("too many consumers (%d)", ls.MaxAckConsumers)
+		}
+
+		consumer = &AckConsumer{pendingLogs: map[uint64]*list.Element{}, pendingList: list.New()}
+		ls.AckConsumers[consumerID] = consumer
+	}
+
+	consumer.Client = svr
+	consumer.Filter = filter
+	consumer.lastSeen = time.Now()
+
+	pendingLogs := make([]*pb.Log, 0, consumer.pendingList.Len())
+	for elem := consumer.pendingList.Front(); elem != nil; elem = elem.Next() {
+		pendingLogs = append(pendingLogs, elem.Value.(*pb.Log))
+	}
+
+	ls.AckLock.Unlock()
+
+	// retransmit unacked logs
+	for _, log := range pendingLogs {
+		if err := svr.Send(log); err != nil {
+			break
+		}
+	}
+
+	return nil
+}
+
+// disconnectAckConsumer Function
+func (ls *LogService) disconnectAckConsumer(consumerID string, svr pb.LogService_WatchLogsWithAckServer) {
+	ls.AckLock.Lock()
+	defer ls.AckLock.Unlock()
+
+	// keep unacked logs for the next connection
+	if consumer, ok := ls.AckConsumers[consumerID]; ok && consumer.Client == svr {
+		consumer.Client = nil
+		consumer.lastSeen = time.Now()
+	}
+}
+
+// ackLogs Function
+func (ls *LogService) ackLogs(consumerID string, seqs []uint64) {
+	ls.AckLock.Lock()
+	defer ls.AckLock.Unlock()
+
+	consumer, ok := ls.AckConsumers[consumerID]
+	if !ok {
+		return
+	}
+
+	for _, seq := range seqs {
+		if elem, ok := consumer.pendingLogs[seq]; ok {
+			consumer.pendingList.Remove(elem)
+			delete(consumer.pendingLogs, seq)
+		}
+	}
+
+	consumer.lastSeen = time.Now()
+}
+
+// sendAckedLog Function (SendLock should be held)
+func (ls *LogService) sendAckedLog(log *pb.Log, now time.Time) {
+	clients := []pb.LogService_WatchLogsWithAckServer{}
+
+	ls.AckLock.Lock()
+
+	for consumerID, consumer := range ls.AckConsumers {
+		// forget the consumers disconnected for long
+		if consumer.Client == nil && ls.AckConsumerTTL > 0 && now.Sub(consumer.lastSeen) > ls.AckConsumerTTL {
+			ls.DropStats.Add(DropReasonUnackedLog, uint64(consumer.pendingList.Len()))
+			delete(ls.AckConsumers, consumerID)
+			continue
+		}
+
+		if !matchLogFilter(consumer.Filter, log) {
+			continue
+		}
+
+		// drop the oldest unacked log if the bound is reached
+		for consumer.pendingList.Len() >= ls.MaxUnackedLogs {
+			oldest := consumer.pendingList.Front()
+			delete(consumer.pendingLogs, oldest.Value.(*pb.Log).Seq)
+			consumer.pendingList.Remove(oldest)
+			consumer.DropCount++
+			ls.DropStats.Add(DropReasonUnackedLog, 1)
+		}
+
+		consumer.pendingLogs[log.Seq] = consumer.pendingList.PushBack(log)
+
+		if consumer.Client != nil {
+			clients = append(clients, consumer.Client)
+		}
+	}
+
+	ls.AckLock.Unlock()
+
+	for _, client := range clients {
+		client.Send(log)
+	}
+}
+
+// GetUnackedLogCount Function
+func (ls *LogService) GetUnackedLogCount(consumerID string) int {
+	ls.AckLock.Lock()
+	defer ls.AckLock.Unlock()
+
+	if consumer, ok := ls.AckConsumers[consumerID]; ok {
+		return consumer.pendingList.Len()
+	}
+
+	return 0
+}
+
+// WatchLogsWithAck Function
+func (ls *LogService) WatchLogsWithAck(svr pb.LogService_WatchLogsWithAckServer) error {
+	// the first message subscribes a consumer
+	req, err := svr.Recv()
+	if err != nil {
+		return err
+	}
+
+	if req.ConsumerID == "" {
+		return errors.New("no consumer id")
+	}
+
+	// acks for the logs received before reconnecting
+	ls.ackLogs(req.ConsumerID, req.Seqs)
+
+	if err := ls.connectAckConsumer(req.ConsumerID, req.Filter, svr); err != nil {
+		return err
+	}
+	defer ls.disconnectAckConsumer(req.ConsumerID, svr)
+
+	// receive acks
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		for {
+			ack, err := svr.Recv()
+			if err != nil {
+				return
+			}
+			ls.ackLogs(req.ConsumerID, ack.Seqs)
+		}
+	}()
+
+	for ls.isRunning() {
+		select {
+		case <-done:
+			return nil
+		default:
+		}
+
+		ls.sendLogs()
+
+		time.Sleep(time.Millisecond * 1)
+	}
+
+	return nil
+}
@@ -0,0 +1,309 @@
+package feeder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kl "github.com/accuknox/KubeArmor/KubeArmor/common"
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+	pb "github.com/accuknox/KubeArmor/protobuf"
+	"google.golang.org/grpc"
+)
+
+// waitForAckConsumer Function
+func waitForAckConsumer(ls *LogService, consumerID string, connected bool) bool {
+	for i := 0; i < 500; i++ {
+		ls.AckLock.Lock()
+		consumer, ok := ls.AckConsumers[consumerID]
+		matched := ok && (consumer.Client != nil) == connected
+		ls.AckLock.Unlock()
+
+		if matched {
+			return true
+		}
+
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	return false
+}
+
+// receiveLogs Function
+func receiveLogs(stream pb.LogService_WatchLogsWithAckClient, count int) ([]uint64, error) {
+	seqs := []uint64{}
+
+	for len(seqs) < count {
+		log, err := stream.Recv()
+		if err != nil {
+			return seqs, err
+		}
+		seqs = append(seqs, log.Seq)
+	}
+
+	return seqs, nil
+}
+
+// fakeAckServer Structure
+type fakeAckServer struct {
+	pb.LogService_WatchLogsWithAckServer
+
+	sent []uint64
+}
+
+// Send Function
+func (fs *fakeAckServer) Send(log *pb.Log) error {
+	fs.sent = append(fs.sent, log.Seq)
+	return nil
+}
+
+func TestAckConsumerEviction(t *testing.T) {
+	ls := &LogService{AckConsumers: map[string]*AckConsumer{}, MaxUnackedLogs: 10, MaxAckConsumers: 2, AckConsumerTTL: time.Minute, DropStats: NewDropStats()}
+
+	// bounded number of consumers
+
+	first := &fakeAckServer{}
+	second := &fakeAckServer{}
+
+	if ls.connectAckConsumer("consumer-1", "", first) != nil || ls.connectAckConsumer("consumer-2", "", second) != nil {
+		t.Error("[FAIL] Failed to connect consumers")
+		return
+	}
+
+	if err := ls.connectAckConsumer("consumer-3", "", &fakeAckServer{}); err == nil {
+		t.Error("[FAIL] Connected more consumers than the limit")
+		return
+	}
+
+	t.Log("[PASS] Rejected a consumer over the limit")
+
+	ls.sendAckedLog(&pb.Log{Seq: 1, Type: "MatchedPolicy"}, time.Now())
+
+	if len(first.sent) != 1 || len(second.sent) != 1 {
+		t.Errorf("[FAIL] Failed to send a log to consumers (%v, %v)", first.sent, second.sent)
+		return
+	}
+
+	// the least recently seen disconnected consumer is evicted
+
+	ls.disconnectAckConsumer("consumer-1", first)
+
+	if err := ls.connectAckConsumer("consumer-3", "", &fakeAckServer{}); err != nil {
+		t.Errorf("[FAIL] Failed to connect a consumer in place of a disconnected one (%s)", err.Error())
+		return
+	}
+
+	if _, ok := ls.AckConsumers["consumer-1"]; ok || len(ls.AckConsumers) != 2 {
+		t.Errorf("[FAIL] Failed to evict a disconnected consumer (%d consumers)", len(ls.AckConsumers))
+		return
+	}
+
+	t.Log("[PASS] Evicted a disconnected consumer for a new one")
+
+	// idle consumers are forgotten after the TTL
+
+	ls.disconnectAckConsumer("consumer-2", second)
+
+	ls.sendAckedLog(&pb.Log{Seq: 2, Type: "MatchedPolicy"}, time.Now())
+
+	if ls.GetUnackedLogCount("consumer-2") != 2 {
+		t.Errorf("[FAIL] Failed to keep unacked logs within the TTL (%d)", ls.GetUnackedLogCount("consumer-2"))
+		return
+	}
+
+	ls.sendAckedLog(&pb.Log{Seq: 3, Type: "MatchedPolicy"}, time.Now().Add(time.Minute*2))
+
+	if _, ok := ls.AckConsumers["consumer-2"]; ok {
+		t.Error("[FAIL] Failed to forget an idle consumer")
+		return
+	}
+
+	if _, ok := ls.AckConsumers["consumer-3"]; !ok {
+		t.Error("[FAIL] Forgot a connected consumer")
+		return
+	}
+
+	t.Log("[PASS] Forgot an idle consumer after the TTL")
+}
+
+func TestWatchLogsWithAck(t *testing.T) {
+	// create Feeder
+	feeder := NewFeeder("32765", "none", true)
+	if feeder == nil {
+		t.Error("[FAIL] Failed to create Feeder")
+		return
+	}
+	defer feeder.DestroyFeeder()
+
+	go feeder.ServeLogFeeds()
+
+	pushLogs := func(count int) {
+		for i := 0; i < count; i++ {
+			feeder.PushLog(tp.Log{UpdatedTime: kl.GetDateTimeNow(), HostName: "kubearmor-dev", ContainerID: "ubuntu-1-container", Operation: "Process", Resource: "/bin/sleep", Result: "Passed"})
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	// connect a consumer
+
+	conn, err := grpc.DialContext(ctx, "localhost:32765", grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		t.Errorf("[FAIL] Failed to connect to the gRPC server (%s)", err.Error())
+		return
+	}
+
+	streamCtx, streamCancel := context.WithCancel(ctx)
+
+	stream, err := pb.NewLogServiceClient(conn).WatchLogsWithAck(streamCtx)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to watch logs (%s)", err.Error())
+		streamCancel()
+		conn.Close()
+		return
+	}
+
+	if err := stream.Send(&pb.LogAckMessage{ConsumerID: "compliance-sink", Filter: "system"}); err != nil || !waitForAckConsumer(feeder.logService, "compliance-sink", true) {
+		t.Error("[FAIL] Failed to subscribe a consumer")
+		streamCancel()
+		conn.Close()
+		return
+	}
+
+	t.Log("[PASS] Subscribed a consumer")
+
+	// receive logs and ack some of them
+
+	pushLogs(5)
+
+	seqs, err := receiveLogs(stream, 5)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to receive logs (%s)", err.Error())
+		streamCancel()
+		conn.Close()
+		return
+	}
+
+	if err := stream.Send(&pb.LogAckMessage{Seqs: seqs[:2]}); err != nil {
+		t.Errorf("[FAIL] Failed to ack logs (%s)", err.Error())
+		streamCancel()
+		conn.Close()
+		return
+	}
+
+	for i := 0; i < 500 && feeder.logService.GetUnackedLogCount("compliance-sink") != 3; i++ {
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	if count := feeder.logService.GetUnackedLogCount("compliance-sink"); count != 3 {
+		t.Errorf("[FAIL] Unexpected number of unacked logs (%d)", count)
+		streamCancel()
+		conn.Close()
+		return
+	}
+
+	t.Log("[PASS] Acked some logs")
+
+	// disconnect the consumer
+
+	streamCancel()
+	conn.Close()
+
+	if !waitForAckConsumer(feeder.logService, "compliance-sink", false) {
+		t.Error("[FAIL] Failed to disconnect a consumer")
+		return
+	}
+
+	pushLogs(2)
+
+	t.Log("[PASS] Disconnected the consumer")
+
+	// reconnect the consumer
+
+	conn, err = grpc.DialContext(ctx, "localhost:32765", grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		t.Errorf("[FAIL] Failed to connect to the gRPC server (%s)", err.Error())
+		return
+	}
+	defer conn.Close()
+
+	stream, err = pb.NewLogServiceClient(conn).WatchLogsWithAck(ctx)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to watch logs (%s)", err.Error())
+		return
+	}
+
+	if err := stream.Send(&pb.LogAckMessage{ConsumerID: "compliance-sink", Filter: "system"}); err != nil {
+		t.Errorf("[FAIL] Failed to resubscribe the consumer (%s)", err.Error())
+		return
+	}
+
+	resent, err := receiveLogs(stream, 5)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to receive logs (%s)", err.Error())
+		return
+	}
+
+	for idx, seq := range seqs[2:] {
+		if resent[idx] != seq {
+			t.Errorf("[FAIL] Failed to retransmit unacked logs (%v, %v)", seqs, resent)
+			return
+		}
+	}
+
+	for _, seq := range resent[3:] {
+		if seq <= seqs[4] {
+			t.Errorf("[FAIL] Received an unexpected log (%v, %v)", seqs, resent)
+			return
+		}
+	}
+
+	t.Log("[PASS] Received the unacked logs after reconnecting")
+}
+
+func TestLogQueueBound(t *testing.T) {
+	// create Feeder
+	feeder := NewFeeder("0", "none", true)
+	if feeder == nil {
+		t.Error("[FAIL] Failed to create Feeder")
+		return
+	}
+	defer feeder.DestroyFeeder()
+
+	if err := feeder.SetMaxUnackedLogs(3); err != nil {
+		t.Errorf("[FAIL] Failed to set the maximum number of unacked logs (%s)", err.Error())
+		return
+	}
+
+	LogLock.Lock()
+	LogQueue = []pb.Log{}
+	LogLock.Unlock()
+
+	// no client takes the logs
+
+	for i := 0; i < 5; i++ {
+		feeder.queueLog(tp.Log{UpdatedTime: kl.GetDateTimeNow(), HostName: "kubearmor-dev", ContainerID: "ubuntu-1-container", Operation: "Process", Resource: "/bin/sleep", Result: "Passed"})
+	}
+
+	LogLock.Lock()
+	queued := []uint64{}
+	for _, log := range LogQueue {
+		queued = append(queued, log.Seq)
+	}
+	lastSeq := LogSeq
+	LogQueue = []pb.Log{}
+	LogLock.Unlock()
+
+	if len(queued) != 3 || queued[2] != lastSeq || queued[0] != lastSeq-2 {
+		t.Errorf("[FAIL] Failed to keep the latest logs in the bounded log queue (%v)", queued)
+		return
+	}
+
+	if count := feeder.DropStats.Get(DropReasonLogQueue); count != 2 {
+		t.Errorf("[FAIL] Failed to count the logs dropped from the log queue (%d)", count)
+		return
+	}
+
+	t.Log("[PASS] Bounded the log queue without clients")
+}
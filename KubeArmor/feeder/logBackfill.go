@@ -0,0 +1,281 @@
+package feeder
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	kl "github.com/accuknox/KubeArmor/KubeArmor/common"
+	kg "github.com/accuknox/KubeArmor/KubeArmor/log"
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+
+	pb "github.com/accuknox/KubeArmor/protobuf"
+)
+
+// ================== //
+// == Log Backfill == //
+// ================== //
+
+// DefaultBackfillSize in bytes
+const DefaultBackfillSize = 1048576
+
+// DefaultBackfillAge in seconds
+const DefaultBackfillAge = 3600
+
+// DefaultMaxBacklogLogs for the live logs held for a client while backfilling
+const DefaultMaxBacklogLogs = 10000
+
+// LogBacklog Structure
+type LogBacklog struct {
+	// live logs not given yet
+	logs    []*pb.Log
+	maxLogs int
+
+	// set once the backlog is empty after the backfill
+	released bool
+
+	lock sync.Mutex
+}
+
+// NewLogBacklog Function
+func NewLogBacklog(maxLogs int) *LogBacklog {
+	return &LogBacklog{logs: []*pb.Log{}, maxLogs: maxLogs}
+}
+
+// Hold Function
+//
+// keeps a live log until the backfill is done (returns false if the log can be sent directly)
+func (lb *LogBacklog) Hold(log *pb.Log, dropStats *DropStats) bool {
+	if lb == nil {
+		return false
+	}
+
+	lb.lock.Lock()
+	defer lb.lock.Unlock()
+
+	if lb.released {
+		return false
+	}
+
+	if len(lb.logs) >= lb.maxLogs {
+		dropStats.Add(DropReasonBackfillQueue, 1)
+		return true
+	}
+
+	lb.logs = append(lb.logs, log)
+
+	return true
+}
+
+// Release Function
+//
+// gives the held logs, and then lets the live logs go to the client directly
+func (lb *LogBacklog) Release(svr pb.LogService_WatchLogsServer) {
+	for {
+		lb.lock.Lock()
+		logs := lb.logs
+		lb.logs = []*pb.Log{}
+		if len(logs) == 0 {
+			lb.released = true
+		}
+		lb.lock.Unlock()
+
+		if len(logs) == 0 {
+			return
+		}
+
+		for _, log := range logs {
+			if err := svr.Send(log); err != nil {
+				lb.lock.Lock()
+				lb.logs = []*pb.Log{}
+				lb.released = true
+				lb.lock.Unlock()
+				return
+			}
+		}
+	}
+}
+
+// LogBackfill Structure
+type LogBackfill struct {
+	// file sink
+	Path string
+
+	// bounds of the tail to replay
+	MaxSize int64
+	MaxAge  time.Duration
+
+	ClusterName string
+}
+
+// SetLogBackfill Function
+func (fd *Feeder) SetLogBackfill(maxSize, maxAge int) error {
+	if maxSize < 0 {
+		return fmt.Errorf("invalid size (%d)", maxSize)
+	}
+
+	if maxAge < 0 {
+		return fmt.Errorf("invalid age (%d)", maxAge)
+	}
+
+	// only for the file sink
+	if fd.output == "stdout" || fd.output == "none" || fd.namespaceSink != nil || fd.unixSocketSink != nil {
+		fd.logService.Backfill = nil
+		return nil
+	}
+
+	// start with an empty file if disabled, or only keep the tail to replay
+	if err := trimLogFile(fd.output, int64(maxSize)); err != nil {
+		return err
+	}
+
+	if maxSize == 0 {
+		fd.logService.Backfill = nil
+		return nil
+	}
+
+	fd.logService.Backfill = &LogBackfill{
+		Path: fd.output,
+
+		MaxSize: int64(maxSize),
+		MaxAge:  time.Second * time.Duration(maxAge),
+
+		ClusterName: fd.clusterName,
+	}
+
+	return nil
+}
+
+// trimLogFile Function
+func trimLogFile(path string, maxSize int64) error {
+	file, err := os.OpenFile(filepath.Clean(path), os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	if info.Size() <= maxSize {
+		return nil
+	}
+
+	tail := []byte{}
+
+	if maxSize > 0 {
+		tail = make([]byte, maxSize)
+		if _, err := file.ReadAt(tail, info.Size()-maxSize); err != nil && err != io.EOF {
+			return err
+		}
+
+		// drop the partial line at the beginning
+		if idx := bytes.IndexByte(tail, '\n'); idx != -1 {
+			tail = tail[idx+1:]
+		} else {
+			tail = []byte{}
+		}
+	}
+
+	if err := file.Truncate(0); err != nil {
+		return err
+	}
+
+	_, err = file.WriteAt(tail, 0)
+	return err
+}
+
+// ReadBackfillLogs Function
+func (bf *LogBackfill) ReadBackfillLogs(now time.Time) ([]tp.Log, error) {
+	logs := []tp.Log{}
+
+	file, err := os.Open(bf.Path)
+	if err != nil {
+		return logs, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return logs, err
+	}
+
+	// read the tail only
+	offset := int64(0)
+	if info.Size() > bf.MaxSize {
+		offset = info.Size() - bf.MaxSize
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return logs, err
+	}
+
+	reader := bufio.NewReader(file)
+
+	// skip the partial line at the beginning of the tail
+	if offset > 0 {
+		if _, err := reader.ReadString('\n'); err != nil {
+			return logs, nil
+		}
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			// a partial line being written
+			break
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		log := tp.Log{}
+		if err := json.Unmarshal([]byte(line), &log); err != nil {
+			continue
+		}
+
+		if bf.MaxAge > 0 {
+			updatedTime, err := time.Parse(kl.TimeFormUTC, log.UpdatedTime)
+			if err != nil || now.Sub(updatedTime) > bf.MaxAge {
+				continue
+			}
+		}
+
+		logs = append(logs, log)
+	}
+
+	return logs, nil
+}
+
+// backfillLogs Function
+func (ls *LogService) backfillLogs(svr pb.LogService_WatchLogsServer, filter string) {
+	logs, err := ls.Backfill.ReadBackfillLogs(time.Now().UTC())
+	if err != nil {
+		kg.Errf("Failed to read logs for backfill (%s, %s)", ls.Backfill.Path, err.Error())
+		return
+	}
+
+	for _, log := range logs {
+		pbLog := pb.Log{}
+		buildPbLog(&pbLog, ls.Backfill.ClusterName, log)
+
+		if !matchLogFilter(filter, &pbLog) {
+			continue
+		}
+
+		if err := svr.Send(&pbLog); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package feeder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	kl "github.com/accuknox/KubeArmor/KubeArmor/common"
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+
+	pb "github.com/accuknox/KubeArmor/protobuf"
+	"google.golang.org/grpc"
+)
+
+func TestReadBackfillLogs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubearmor-backfill")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a temporary directory (%s)", err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	logPath := filepath.Join(dir, "kubearmor.log")
+	now := time.Now().UTC()
+
+	// an old log and recent logs
+
+	content := ""
+	for idx, updatedTime := range []time.Time{now.Add(-time.Hour * 2), now.Add(-time.Minute * 2), now.Add(-time.Minute)} {
+		arr, _ := json.Marshal(tp.Log{UpdatedTime: updatedTime.Format(kl.TimeFormUTC), HostName: "kubearmor-dev", ContainerID: "ubuntu-1-container", Type: "ContainerLog", Operation: "Process", Resource: fmt.Sprintf("/bin/sleep %d", idx), Result: "Passed"})
+		content = content + string(arr) + "\n"
+	}
+
+	if err := ioutil.WriteFile(logPath, []byte(content), 0644); err != nil {
+		t.Errorf("[FAIL] Failed to write logs (%s)", err.Error())
+		return
+	}
+
+	// bounded by age
+
+	backfill := &LogBackfill{Path: logPath, MaxSize: DefaultBackfillSize, MaxAge: time.Hour}
+
+	logs, err := backfill.ReadBackfillLogs(now)
+	if err != nil || len(logs) != 2 || logs[0].Resource != "/bin/sleep 1" || logs[1].Resource != "/bin/sleep 2" {
+		t.Errorf("[FAIL] Failed to bound the backfill by age (%v, %v)", logs, err)
+		return
+	}
+
+	t.Log("[PASS] Bounded the backfill by age")
+
+	// bounded by size (the last line and a part of the previous one)
+
+	backfill.MaxSize = int64(len(content)/3 + 10)
+
+	logs, err = backfill.ReadBackfillLogs(now)
+	if err != nil || len(logs) != 1 || logs[0].Resource != "/bin/sleep 2" {
+		t.Errorf("[FAIL] Failed to bound the backfill by size (%v, %v)", logs, err)
+		return
+	}
+
+	t.Log("[PASS] Bounded the backfill by size")
+}
+
+func TestSetLogBackfill(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubearmor-backfill")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a temporary directory (%s)", err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	logPath := filepath.Join(dir, "kubearmor.log")
+	content := "{\"Resource\":\"/bin/sleep 0\"}\n{\"Resource\":\"/bin/sleep 1\"}\n{\"Resource\":\"/bin/sleep 2\"}\n"
+
+	// the logs written before a restart
+
+	if err := ioutil.WriteFile(logPath, []byte(content), 0644); err != nil {
+		t.Errorf("[FAIL] Failed to write logs (%s)", err.Error())
+		return
+	}
+
+	feeder := NewFeeder("0", logPath, false)
+	if feeder == nil {
+		t.Error("[FAIL] Failed to create Feeder")
+		return
+	}
+	defer feeder.DestroyFeeder()
+
+	// keep the tail only
+
+	if err := feeder.SetLogBackfill(len(content)/3+10, DefaultBackfillAge); err != nil {
+		t.Errorf("[FAIL] Failed to set log backfill (%s)", err.Error())
+		return
+	}
+
+	if data, err := ioutil.ReadFile(logPath); err != nil || string(data) != "{\"Resource\":\"/bin/sleep 2\"}\n" {
+		t.Errorf("[FAIL] Failed to bound the log file to the backfill size (%q)", string(data))
+		return
+	}
+
+	t.Log("[PASS] Kept the tail of the log file for backfill")
+
+	// disabled
+
+	if err := feeder.SetLogBackfill(0, DefaultBackfillAge); err != nil || feeder.logService.Backfill != nil {
+		t.Errorf("[FAIL] Failed to disable log backfill (%v)", err)
+		return
+	}
+
+	if data, err := ioutil.ReadFile(logPath); err != nil || len(data) != 0 {
+		t.Errorf("[FAIL] Failed to truncate the log file without backfill (%q)", string(data))
+		return
+	}
+
+	t.Log("[PASS] Truncated the log file without backfill")
+}
+
+func TestWatchLogsWithBackfill(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubearmor-backfill")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a temporary directory (%s)", err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	logPath := filepath.Join(dir, "kubearmor.log")
+
+	pushLog := func(feeder *Feeder, idx int) {
+		feeder.PushLog(tp.Log{UpdatedTime: kl.GetDateTimeNow(), HostName: "kubearmor-dev", ContainerID: "ubuntu-1-container", Operation: "Process", Resource: fmt.Sprintf("/bin/sleep %d", idx), Result: "Passed"})
+	}
+
+	// write logs before a restart
+
+	feeder := NewFeeder("32762", logPath, true)
+	if feeder == nil {
+		t.Error("[FAIL] Failed to create Feeder")
+		return
+	}
+
+	for idx := 0; idx < 3; idx++ {
+		pushLog(feeder, idx)
+	}
+
+	feeder.DestroyFeeder()
+
+	// a new process starts with an empty queue
+	LogLock.Lock()
+	LogQueue = []pb.Log{}
+	LogLock.Unlock()
+
+	// restart the feeder
+
+	feeder = NewFeeder("32761", logPath, true)
+	if feeder == nil {
+		t.Error("[FAIL] Failed to restart Feeder")
+		return
+	}
+	defer feeder.DestroyFeeder()
+
+	if err := feeder.SetLogBackfill(DefaultBackfillSize, DefaultBackfillAge); err != nil {
+		t.Errorf("[FAIL] Failed to enable backfill (%s)", err.Error())
+		return
+	}
+
+	go feeder.ServeLogFeeds()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, "localhost:32761", grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		t.Errorf("[FAIL] Failed to connect to the gRPC server (%s)", err.Error())
+		return
+	}
+	defer conn.Close()
+
+	stream, err := pb.NewLogServiceClient(conn).WatchLogs(ctx, &pb.RequestMessage{Filter: "", Backfill: true})
+	if err != nil {
+		t.Errorf("[FAIL] Failed to watch logs (%s)", err.Error())
+		return
+	}
+
+	// the backfilled tail
+
+	for idx := 0; idx < 3; idx++ {
+		log, err := stream.Recv()
+		if err != nil {
+			t.Errorf("[FAIL] Failed to receive a backfilled log (%s)", err.Error())
+			return
+		}
+
+		if log.Resource != fmt.Sprintf("/bin/sleep %d", idx) {
+			t.Errorf("[FAIL] Received an unexpected backfilled log (%v)", log)
+			return
+		}
+	}
+
+	t.Log("[PASS] Received the backfilled logs")
+
+	// live logs
+
+	pushLog(feeder, 3)
+
+	log, err := stream.Recv()
+	if err != nil {
+		t.Errorf("[FAIL] Failed to receive a live log (%s)", err.Error())
+		return
+	}
+
+	if log.Resource != "/bin/sleep 3" || log.Seq == 0 {
+		t.Errorf("[FAIL] Received an unexpected live log (%v)", log)
+		return
+	}
+
+	t.Log("[PASS] Received a live log after the backfilled logs")
+}
+
+// fakeLogServer Structure
+type fakeLogServer struct {
+	pb.LogService_WatchLogsServer
+
+	sent []uint64
+}
+
+// Send Function
+func (fs *fakeLogServer) Send(log *pb.Log) error {
+	fs.sent = append(fs.sent, log.Seq)
+	return nil
+}
+
+func TestLogBacklog(t *testing.T) {
+	ls := &LogService{LogStructs: map[string]LogStruct{}, AckConsumers: map[string]*AckConsumer{}, DropStats: NewDropStats()}
+
+	live := &fakeLogServer{}
+	backfilled := &fakeLogServer{}
+	backlog := NewLogBacklog(2)
+
+	ls.addLogStruct("live", live, "", nil)
+	ls.addLogStruct("backfilled", backfilled, "", backlog)
+
+	pushLogs := func(seqs ...uint64) {
+		LogLock.Lock()
+		for _, seq := range seqs {
+			LogQueue = append(LogQueue, pb.Log{Seq: seq, Type: "ContainerLog"})
+		}
+		LogLock.Unlock()
+
+		ls.sendLogs()
+	}
+
+	// live logs while backfilling
+
+	pushLogs(1, 2, 3)
+
+	if fmt.Sprint(live.sent) != "[1 2 3]" {
+		t.Errorf("[FAIL] Blocked the live logs of the others while backfilling (%v)", live.sent)
+		return
+	}
+
+	if len(backfilled.sent) != 0 || ls.DropStats.Get(DropReasonBackfillQueue) != 1 {
+		t.Errorf("[FAIL] Failed to hold the live logs of a client being backfilled (%v, %d)", backfilled.sent, ls.DropStats.Get(DropReasonBackfillQueue))
+		return
+	}
+
+	t.Log("[PASS] Held the live logs of a client being backfilled")
+
+	// live logs after the backfill
+
+	backlog.Release(backfilled)
+	pushLogs(4)
+
+	if fmt.Sprint(backfilled.sent) != "[1 2 4]" {
+		t.Errorf("[FAIL] Failed to give the held logs in order (%v)", backfilled.sent)
+		return
+	}
+
+	t.Log("[PASS] Gave the held logs after the backfill")
+}
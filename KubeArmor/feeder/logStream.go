@@ -0,0 +1,245 @@
+package feeder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+	pb "github.com/accuknox/KubeArmor/protobuf"
+)
+
+// ================ //
+// == Log Stream == //
+// ================ //
+
+// LogStreamPath for the NDJSON log stream (e.g., curl -N http://localhost:port/logs?type=policy)
+const LogStreamPath = "/logs"
+
+// DefaultLogStreamQueueSize for each client of the log stream
+const DefaultLogStreamQueueSize = 1024
+
+// DefaultMaxLogStreams for the clients of the log stream connected at once
+const DefaultMaxLogStreams = 64
+
+// LogStreamFilter Structure
+type LogStreamFilter struct {
+	// policy, system, or decision (the same as the filters of WatchLogs)
+	Type string
+
+	NamespaceName string
+	PodName       string
+	Operation     string
+
+	// the minimum severity of matched policies (0 to disable)
+	MinSeverity int
+}
+
+// ParseLogStreamFilter Function
+//
+// query: type=policy&namespace=multiubuntu&pod=ubuntu-1&operation=Process&severity>=5
+func ParseLogStreamFilter(query url.Values) (LogStreamFilter, error) {
+	filter := LogStreamFilter{}
+
+	for key, vals := range query {
+		val := vals[len(vals)-1]
+
+		switch key {
+		case "type":
+			if val != "" && val != "policy" && val != "system" && val != "decision" {
+				return LogStreamFilter{}, fmt.Errorf("invalid type (%s)", val)
+			}
+			filter.Type = val
+		case "namespace":
+			filter.NamespaceName = val
+		case "pod":
+			filter.PodName = val
+		case "operation":
+			filter.Operation = val
+		case "severity>": // severity>=N
+			severity, err := strconv.Atoi(val)
+			if err != nil || severity < 1 || severity > 10 {
+				return LogStreamFilter{}, fmt.Errorf("invalid severity (%s)", val)
+			}
+			filter.MinSeverity = severity
+		default:
+			return LogStreamFilter{}, fmt.Errorf("unknown filter (%s)", key)
+		}
+	}
+
+	return filter, nil
+}
+
+// getMaxSeverity Function
+func getMaxSeverity(severity string) int {
+	maxSeverity := 0
+
+	// the severities of multiple policies are joined (e.g., 3,5)
+	for _, sev := range strings.Split(severity, ",") {
+		if val, err := strconv.Atoi(sev); err == nil && val > maxSeverity {
+			maxSeverity = val
+		}
+	}
+
+	return maxSeverity
+}
+
+// Match Function
+func (filter LogStreamFilter) Match(log tp.Log) bool {
+	if !matchLogFilter(filter.Type, &pb.Log{Type: log.Type, Operation: log.Operation}) {
+		return false
+	}
+
+	if filter.NamespaceName != "" && log.NamespaceName != filter.NamespaceName {
+		return false
+	}
+
+	if filter.PodName != "" && log.PodName != filter.PodName {
+		return false
+	}
+
+	if filter.Operation != "" && log.Operation != filter.Operation {
+		return false
+	}
+
+	if filter.MinSeverity > 0 && getMaxSeverity(log.Severity) < filter.MinSeverity {
+		return false
+	}
+
+	return true
+}
+
+// logStreamClient Structure
+type logStreamClient struct {
+	filter LogStreamFilter
+	queue  chan []byte
+}
+
+// EnableLogStream Function
+func (fd *Feeder) EnableLogStream() error {
+	if fd.metricsMux == nil {
+		return errors.New("no metrics port")
+	}
+
+	fd.logStreamsLock.Lock()
+	fd.logStreams = map[*logStreamClient]struct{}{}
+	fd.maxLogStreams = DefaultMaxLogStreams
+	fd.logStreamStopChan = make(chan struct{})
+	fd.logStreamsLock.Unlock()
+
+	fd.metricsMux.HandleFunc(LogStreamPath, fd.LogStreamHandler)
+
+	return nil
+}
+
+// addLogStreamClient Function
+func (fd *Feeder) addLogStreamClient(filter LogStreamFilter) (*logStreamClient, chan struct{}, error) {
+	fd.logStreamsLock.Lock()
+	defer fd.logStreamsLock.Unlock()
+
+	// already stopped
+	if fd.logStreams == nil || fd.logStreamStopChan == nil {
+		return nil, nil, errors.New("log stream stopped")
+	}
+
+	if len(fd.logStreams) >= fd.maxLogStreams {
+		return nil, nil, fmt.Errorf("too many clients (%d)", fd.maxLogStreams)
+	}
+
+	client := &logStreamClient{filter: filter, queue: make(chan []byte, DefaultLogStreamQueueSize)}
+	fd.logStreams[client] = struct{}{}
+
+	return client, fd.logStreamStopChan, nil
+}
+
+// removeLogStreamClient Function
+func (fd *Feeder) removeLogStreamClient(client *logStreamClient) {
+	fd.logStreamsLock.Lock()
+	defer fd.logStreamsLock.Unlock()
+
+	delete(fd.logStreams, client)
+}
+
+// GetLogStreamCount Function
+func (fd *Feeder) GetLogStreamCount() int {
+	fd.logStreamsLock.Lock()
+	defer fd.logStreamsLock.Unlock()
+
+	return len(fd.logStreams)
+}
+
+// streamLog Function
+func (fd *Feeder) streamLog(log tp.Log) {
+	fd.logStreamsLock.Lock()
+	defer fd.logStreamsLock.Unlock()
+
+	var line []byte
+
+	for client := range fd.logStreams {
+		if !client.filter.Match(log) {
+			continue
+		}
+
+		if line == nil {
+			arr, err := json.Marshal(log)
+			if err != nil {
+				return
+			}
+			line = append(arr, '\n')
+		}
+
+		// drop the log if the client is too slow
+		select {
+		case client.queue <- line:
+		default:
+			fd.DropStats.Add(DropReasonStreamQueue, 1)
+		}
+	}
+}
+
+// LogStreamHandler Function
+func (fd *Feeder) LogStreamHandler(w http.ResponseWriter, r *http.Request) {
+	filter, err := ParseLogStreamFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	client, stopChan, err := fd.addLogStreamClient(filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	defer fd.removeLogStreamClient(client)
+
+	// no content length, so the response is sent with chunked transfer encoding
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done(): // disconnected
+			return
+		case <-stopChan: // shutdown
+			return
+		case line := <-client.queue:
+			if _, err := w.Write(line); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package feeder
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+func TestParseLogStreamFilter(t *testing.T) {
+	query, _ := url.ParseQuery("type=policy&namespace=multiubuntu&severity>=5")
+
+	filter, err := ParseLogStreamFilter(query)
+	if err != nil || filter.Type != "policy" || filter.NamespaceName != "multiubuntu" || filter.MinSeverity != 5 {
+		t.Errorf("[FAIL] Failed to parse a log stream filter (%v, %v)", filter, err)
+		return
+	}
+
+	for _, invalid := range []string{"type=all", "severity>=11", "severity>=high", "hostname=kubearmor-dev"} {
+		query, _ := url.ParseQuery(invalid)
+		if _, err := ParseLogStreamFilter(query); err == nil {
+			t.Errorf("[FAIL] Parsed an invalid log stream filter (%s)", invalid)
+			return
+		}
+	}
+
+	t.Log("[PASS] Parsed log stream filters")
+}
+
+func TestLogStream(t *testing.T) {
+	fd := &Feeder{DropStats: NewDropStats()}
+
+	// listen to a random port (unused, the test server is used instead)
+	if err := fd.EnableMetrics("0"); err != nil {
+		t.Errorf("[FAIL] Failed to enable metrics (%s)", err.Error())
+		return
+	}
+	defer fd.metricsListener.Close()
+
+	if err := fd.EnableLogStream(); err != nil {
+		t.Errorf("[FAIL] Failed to enable the log stream (%s)", err.Error())
+		return
+	}
+
+	server := httptest.NewServer(fd.metricsServer.Handler)
+	defer server.Close()
+
+	// an invalid filter
+
+	resp, err := http.Get(server.URL + LogStreamPath + "?type=all")
+	if err != nil || resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("[FAIL] Failed to reject an invalid filter (%v)", err)
+		return
+	}
+	resp.Body.Close()
+
+	t.Log("[PASS] Rejected an invalid filter")
+
+	// connect to the log stream
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+LogStreamPath+"?type=policy&namespace=multiubuntu&severity>=5", nil)
+
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to connect to the log stream (%s)", err.Error())
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.Header.Get("Content-Type") != "application/x-ndjson" || len(resp.TransferEncoding) == 0 || resp.TransferEncoding[0] != "chunked" {
+		t.Errorf("[FAIL] Failed to stream logs with chunked transfer encoding (%v, %v)", resp.Header, resp.TransferEncoding)
+		return
+	}
+
+	for i := 0; i < 100 && fd.GetLogStreamCount() != 1; i++ {
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	// push logs
+
+	fd.streamLog(tp.Log{NamespaceName: "multiubuntu", PodName: "ubuntu-1", Type: "ContainerLog", Operation: "Process", Resource: "/bin/ls"})
+	fd.streamLog(tp.Log{NamespaceName: "default", PodName: "nginx", Type: "MatchedPolicy", Severity: "7", Operation: "Process", Resource: "/bin/ls"})
+	fd.streamLog(tp.Log{NamespaceName: "multiubuntu", PodName: "ubuntu-1", Type: "MatchedPolicy", Severity: "3", Operation: "Process", Resource: "/bin/ls"})
+
+	for _, resource := range []string{"/bin/sleep", "/bin/cat", "/bin/bash"} {
+		fd.streamLog(tp.Log{NamespaceName: "multiubuntu", PodName: "ubuntu-1", Type: "MatchedPolicy", Severity: "5", Operation: "Process", Resource: resource})
+	}
+
+	// read NDJSON lines
+
+	scanner := bufio.NewScanner(resp.Body)
+
+	for _, resource := range []string{"/bin/sleep", "/bin/cat", "/bin/bash"} {
+		if !scanner.Scan() {
+			t.Errorf("[FAIL] Failed to read a log from the log stream (%v)", scanner.Err())
+			return
+		}
+
+		log := tp.Log{}
+		if err := json.Unmarshal(scanner.Bytes(), &log); err != nil || log.Resource != resource {
+			t.Errorf("[FAIL] Failed to read a filtered log (%s, %v)", scanner.Text(), err)
+			return
+		}
+	}
+
+	t.Log("[PASS] Read filtered logs from the log stream")
+
+	// decision changes
+
+	if err := fd.SetMaxDecisionEntries(16); err != nil {
+		t.Errorf("[FAIL] Failed to track decisions (%s)", err.Error())
+		return
+	}
+
+	decisionReq, _ := http.NewRequestWithContext(ctx, "GET", server.URL+LogStreamPath+"?type=decision", nil)
+
+	decisionResp, err := http.DefaultClient.Do(decisionReq)
+	if err != nil || decisionResp.StatusCode != http.StatusOK {
+		t.Errorf("[FAIL] Failed to connect to the log stream of decision changes (%v)", err)
+		return
+	}
+	defer decisionResp.Body.Close()
+
+	for i := 0; i < 100 && fd.GetLogStreamCount() != 2; i++ {
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	fd.TrackDecision(tp.Log{ContainerID: "ubuntu-1-container", Type: "ContainerLog", Operation: "File", Resource: "/etc/shadow"})
+	fd.TrackDecision(tp.Log{ContainerID: "ubuntu-1-container", Type: "MatchedPolicy", Action: "Block", Operation: "File", Resource: "/etc/shadow"})
+
+	decisionScanner := bufio.NewScanner(decisionResp.Body)
+	if !decisionScanner.Scan() {
+		t.Errorf("[FAIL] Failed to read a decision change from the log stream (%v)", decisionScanner.Err())
+		return
+	}
+
+	change := tp.Log{}
+	if err := json.Unmarshal(decisionScanner.Bytes(), &change); err != nil || change.Operation != DecisionChangeOperation || change.Action != "Block" {
+		t.Errorf("[FAIL] Failed to read a decision change (%s, %v)", decisionScanner.Text(), err)
+		return
+	}
+
+	t.Log("[PASS] Read decision changes from the log stream")
+
+	// too many clients
+
+	fd.logStreamsLock.Lock()
+	fd.maxLogStreams = 2
+	fd.logStreamsLock.Unlock()
+
+	resp, err = http.Get(server.URL + LogStreamPath)
+	if err != nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("[FAIL] Failed to reject a client over the limit (%v)", err)
+		return
+	}
+	resp.Body.Close()
+
+	t.Log("[PASS] Rejected a client over the limit")
+
+	// disconnect
+
+	cancel()
+
+	for i := 0; i < 100 && fd.GetLogStreamCount() != 0; i++ {
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	if count := fd.GetLogStreamCount(); count != 0 {
+		t.Errorf("[FAIL] Failed to remove a disconnected client (%d clients)", count)
+		return
+	}
+
+	t.Log("[PASS] Removed a disconnected client")
+}
@@ -0,0 +1,150 @@
+package feeder
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ============= //
+// == Metrics == //
+// ============= //
+
+// Metrics Structure
+type Metrics struct {
+	// the number of pushed messages
+	MessageCount uint64
+
+	// log type -> the number of pushed logs
+	LogCounts    map[string]uint64
+	LogCountLock sync.Mutex
+
+	// the number of logs filtered out by policy matching (e.g., system logs when disabled)
+	FilteredCount uint64
+
+	// the number of policy matches and their total latency (in nanoseconds)
+	MatchCount   uint64
+	MatchLatency uint64
+}
+
+// NewMetrics Function
+func NewMetrics() *Metrics {
+	mt := &Metrics{}
+
+	mt.MessageCount = 0
+
+	mt.LogCounts = map[string]uint64{}
+	mt.LogCountLock = sync.Mutex{}
+
+	return mt
+}
+
+// CountMessage Function
+func (mt *Metrics) CountMessage() {
+	atomic.AddUint64(&mt.MessageCount, 1)
+}
+
+// CountLog Function
+func (mt *Metrics) CountLog(logType string) {
+	mt.LogCountLock.Lock()
+	mt.LogCounts[logType]++
+	mt.LogCountLock.Unlock()
+}
+
+// CountFiltered Function
+func (mt *Metrics) CountFiltered() {
+	if mt == nil {
+		return
+	}
+
+	atomic.AddUint64(&mt.FilteredCount, 1)
+}
+
+// ObserveMatchLatency Function
+func (mt *Metrics) ObserveMatchLatency(latency time.Duration) {
+	if mt == nil || latency < 0 {
+		return
+	}
+
+	atomic.AddUint64(&mt.MatchCount, 1)
+	atomic.AddUint64(&mt.MatchLatency, uint64(latency.Nanoseconds()))
+}
+
+// GetLogCounts Function
+func (mt *Metrics) GetLogCounts() map[string]uint64 {
+	logCounts := map[string]uint64{}
+
+	mt.LogCountLock.Lock()
+	defer mt.LogCountLock.Unlock()
+
+	for logType, count := range mt.LogCounts {
+		logCounts[logType] = count
+	}
+
+	return logCounts
+}
+
+// MetricsHandler Function
+func (fd *Feeder) MetricsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	fmt.Fprintf(w, "# HELP kubearmor_messages_total The number of messages pushed by KubeArmor\n")
+	fmt.Fprintf(w, "# TYPE kubearmor_messages_total counter\n")
+	fmt.Fprintf(w, "kubearmor_messages_total %d\n", atomic.LoadUint64(&fd.Metrics.MessageCount))
+
+	logCounts := fd.Metrics.GetLogCounts()
+
+	logTypes := []string{}
+	for logType := range logCounts {
+		logTypes = append(logTypes, logType)
+	}
+	sort.Strings(logTypes)
+
+	fmt.Fprintf(w, "# HELP kubearmor_logs_total The number of logs pushed by KubeArmor\n")
+	fmt.Fprintf(w, "# TYPE kubearmor_logs_total counter\n")
+	for _, logType := range logTypes {
+		fmt.Fprintf(w, "kubearmor_logs_total{type=%q} %d\n", logType, logCounts[logType])
+	}
+
+	fmt.Fprintf(w, "# HELP kubearmor_filtered_logs_total The number of logs filtered out by policy matching (not dropped)\n")
+	fmt.Fprintf(w, "# TYPE kubearmor_filtered_logs_total counter\n")
+	fmt.Fprintf(w, "kubearmor_filtered_logs_total %d\n", atomic.LoadUint64(&fd.Metrics.FilteredCount))
+
+	LogLock.Lock()
+	queueLength := len(LogQueue)
+	LogLock.Unlock()
+
+	fmt.Fprintf(w, "# HELP kubearmor_log_queue_length The number of logs waiting to be sent\n")
+	fmt.Fprintf(w, "# TYPE kubearmor_log_queue_length gauge\n")
+	fmt.Fprintf(w, "kubearmor_log_queue_length %d\n", queueLength)
+
+	fd.logService.LogLock.Lock()
+	subscribers := len(fd.logService.LogStructs)
+	fd.logService.LogLock.Unlock()
+
+	dropStats := fd.GetStats()
+
+	reasons := []string{}
+	for reason := range dropStats {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+
+	fmt.Fprintf(w, "# HELP kubearmor_dropped_events_total The number of events dropped by KubeArmor\n")
+	fmt.Fprintf(w, "# TYPE kubearmor_dropped_events_total counter\n")
+	for _, reason := range reasons {
+		fmt.Fprintf(w, "kubearmor_dropped_events_total{reason=%q} %d\n", reason, dropStats[reason])
+	}
+
+	fmt.Fprintf(w, "# HELP kubearmor_policy_match_duration_seconds The latency of matching logs with security policies\n")
+	fmt.Fprintf(w, "# TYPE kubearmor_policy_match_duration_seconds summary\n")
+	fmt.Fprintf(w, "kubearmor_policy_match_duration_seconds_sum %f\n", time.Duration(atomic.LoadUint64(&fd.Metrics.MatchLatency)).Seconds())
+	fmt.Fprintf(w, "kubearmor_policy_match_duration_seconds_count %d\n", atomic.LoadUint64(&fd.Metrics.MatchCount))
+
+	fmt.Fprintf(w, "# HELP kubearmor_log_subscribers The number of clients watching logs\n")
+	fmt.Fprintf(w, "# TYPE kubearmor_log_subscribers gauge\n")
+	fmt.Fprintf(w, "kubearmor_log_subscribers %d\n", subscribers)
+}
@@ -0,0 +1,393 @@
+package feeder
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ==================== //
+// == Namespace Sink == //
+// ==================== //
+
+// NamespaceSinkPrefix for the output mode writing logs per namespace
+const NamespaceSinkPrefix = "namespace://"
+
+// HostSinkName for the logs generated by a host (not a valid namespace name, e.g., _host.maxBackups)
+const HostSinkName = "_host"
+
+// HostLogName for the log file of a host (host.log)
+const HostLogName = "host"
+
+// HostNamespaceLogName for the log file of a namespace named "host" (not to share host.log)
+const HostNamespaceLogName = "host_ns"
+
+// PruneInterval to remove the rotated files older than MaxAge
+const PruneInterval = time.Minute * 10
+
+// RetentionPolicy Structure
+type RetentionPolicy struct {
+	MaxSize    int64         // rotate a log file if it gets bigger than this (bytes)
+	MaxBackups int           // the number of rotated files to keep
+	MaxAge     time.Duration // remove rotated files older than this (0: keep)
+}
+
+// namespaceFile Structure
+type namespaceFile struct {
+	name string
+	file *os.File
+	size int64
+}
+
+// NamespaceSink Structure
+type NamespaceSink struct {
+	// target directory
+	dir string
+
+	// default retention policy
+	defaultPolicy RetentionPolicy
+
+	// namespace -> retention policy
+	policies map[string]RetentionPolicy
+
+	// the maximum number of open files
+	maxOpenFiles int
+
+	// namespace -> open file (least recently used at the back)
+	files   map[string]*list.Element
+	lruList *list.List
+
+	lock sync.Mutex
+
+	// stop channel for pruning
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewNamespaceSink Function
+//
+// output: namespace:///var/log/kubearmor?maxSize=10&maxBackups=3&maxAge=24&maxOpenFiles=32&prod.maxBackups=10
+// (maxSize in MB, maxAge in hours, "<namespace>.<option>" overrides the option for the namespace)
+func NewNamespaceSink(output string) (*NamespaceSink, error) {
+	u, err := url.Parse(output)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Path == "" {
+		return nil, errors.New("no target directory")
+	}
+
+	ns := &NamespaceSink{}
+
+	ns.dir = u.Path
+
+	ns.defaultPolicy = RetentionPolicy{MaxSize: 10 * 1024 * 1024, MaxBackups: 3, MaxAge: 0}
+	ns.policies = map[string]RetentionPolicy{}
+
+	ns.maxOpenFiles = 32
+
+	ns.files = map[string]*list.Element{}
+	ns.lruList = list.New()
+
+	query := u.Query()
+
+	// default options first
+	for key, values := range query {
+		if strings.Contains(key, ".") {
+			continue
+		}
+
+		if key == "maxOpenFiles" {
+			val, err := strconv.Atoi(values[0])
+			if err != nil || val < 1 {
+				return nil, fmt.Errorf("invalid maxOpenFiles (%s)", values[0])
+			}
+			ns.maxOpenFiles = val
+			continue
+		}
+
+		if err := updateRetentionPolicy(&ns.defaultPolicy, key, values[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	// namespace-specific options
+	for key, values := range query {
+		if !strings.Contains(key, ".") {
+			continue
+		}
+
+		parts := strings.SplitN(key, ".", 2)
+
+		policy, ok := ns.policies[parts[0]]
+		if !ok {
+			policy = ns.defaultPolicy
+		}
+
+		if err := updateRetentionPolicy(&policy, parts[1], values[0]); err != nil {
+			return nil, err
+		}
+
+		ns.policies[parts[0]] = policy
+	}
+
+	if err := os.MkdirAll(ns.dir, 0755); err != nil {
+		return nil, err
+	}
+
+	// remove old rotated files even if no more logs are written
+	ns.stopChan = make(chan struct{})
+	ns.wg.Add(1)
+	go ns.pruneBackups(PruneInterval)
+
+	return ns, nil
+}
+
+// updateRetentionPolicy Function
+func updateRetentionPolicy(policy *RetentionPolicy, key, value string) error {
+	val, err := strconv.Atoi(value)
+	if err != nil || val < 0 {
+		return fmt.Errorf("invalid %s (%s)", key, value)
+	}
+
+	switch key {
+	case "maxSize":
+		policy.MaxSize = int64(val) * 1024 * 1024
+	case "maxBackups":
+		policy.MaxBackups = val
+	case "maxAge":
+		policy.MaxAge = time.Duration(val) * time.Hour
+	default:
+		return fmt.Errorf("unknown option (%s)", key)
+	}
+
+	return nil
+}
+
+// GetRetentionPolicy Function
+func (ns *NamespaceSink) GetRetentionPolicy(name string) RetentionPolicy {
+	if policy, ok := ns.policies[name]; ok {
+		return policy
+	}
+
+	return ns.defaultPolicy
+}
+
+// getLogName Function
+func getLogName(name string) string {
+	switch name {
+	case HostSinkName:
+		return HostLogName
+	case HostLogName:
+		return HostNamespaceLogName
+	default:
+		return name
+	}
+}
+
+// getSinkName Function
+func getSinkName(logName string) string {
+	switch logName {
+	case HostLogName:
+		return HostSinkName
+	case HostNamespaceLogName:
+		return HostLogName
+	default:
+		return logName
+	}
+}
+
+// GetLogPath Function
+func (ns *NamespaceSink) GetLogPath(name string) string {
+	return filepath.Join(ns.dir, getLogName(name)+".log")
+}
+
+// GetOpenFileCount Function
+func (ns *NamespaceSink) GetOpenFileCount() int {
+	ns.lock.Lock()
+	defer ns.lock.Unlock()
+
+	return ns.lruList.Len()
+}
+
+// getFile Function
+func (ns *NamespaceSink) getFile(name string) (*namespaceFile, error) {
+	if elem, ok := ns.files[name]; ok {
+		ns.lruList.MoveToFront(elem)
+		return elem.Value.(*namespaceFile), nil
+	}
+
+	// recycle the least recently used file
+	for ns.lruList.Len() >= ns.maxOpenFiles {
+		ns.closeFile(ns.lruList.Back().Value.(*namespaceFile).name)
+	}
+
+	file, err := os.OpenFile(ns.GetLogPath(name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	nsFile := &namespaceFile{name: name, file: file, size: info.Size()}
+	ns.files[name] = ns.lruList.PushFront(nsFile)
+
+	return nsFile, nil
+}
+
+// closeFile Function
+func (ns *NamespaceSink) closeFile(name string) {
+	if elem, ok := ns.files[name]; ok {
+		elem.Value.(*namespaceFile).file.Close()
+		ns.lruList.Remove(elem)
+		delete(ns.files, name)
+	}
+}
+
+// rotate Function
+func (ns *NamespaceSink) rotate(name string) error {
+	policy := ns.GetRetentionPolicy(name)
+	logPath := ns.GetLogPath(name)
+
+	ns.closeFile(name)
+
+	if policy.MaxBackups == 0 {
+		return os.Remove(logPath)
+	}
+
+	// shift the rotated files (<name>.log.1 -> <name>.log.2, ...)
+	os.Remove(fmt.Sprintf("%s.%d", logPath, policy.MaxBackups))
+	for idx := policy.MaxBackups - 1; idx > 0; idx-- {
+		os.Rename(fmt.Sprintf("%s.%d", logPath, idx), fmt.Sprintf("%s.%d", logPath, idx+1))
+	}
+
+	if err := os.Rename(logPath, logPath+".1"); err != nil {
+		return err
+	}
+
+	// remove old rotated files
+	ns.prune(name, time.Now())
+
+	return nil
+}
+
+// prune Function
+func (ns *NamespaceSink) prune(name string, now time.Time) int {
+	policy := ns.GetRetentionPolicy(name)
+	if policy.MaxAge == 0 {
+		return 0
+	}
+
+	pruned := 0
+
+	backups, _ := filepath.Glob(ns.GetLogPath(name) + ".*")
+	for _, backup := range backups {
+		if info, err := os.Stat(backup); err == nil && now.Sub(info.ModTime()) > policy.MaxAge {
+			if os.Remove(backup) == nil {
+				pruned++
+			}
+		}
+	}
+
+	return pruned
+}
+
+// Prune Function
+func (ns *NamespaceSink) Prune(now time.Time) int {
+	ns.lock.Lock()
+	defer ns.lock.Unlock()
+
+	names := map[string]bool{}
+
+	backups, _ := filepath.Glob(filepath.Join(ns.dir, "*.log.*"))
+	for _, backup := range backups {
+		base := filepath.Base(backup)
+		names[getSinkName(base[:strings.LastIndex(base, ".log.")])] = true
+	}
+
+	pruned := 0
+
+	for name := range names {
+		pruned = pruned + ns.prune(name, now)
+	}
+
+	return pruned
+}
+
+// pruneBackups Function
+func (ns *NamespaceSink) pruneBackups(interval time.Duration) {
+	defer ns.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ns.stopChan:
+			return
+		case <-ticker.C:
+			ns.Prune(time.Now())
+		}
+	}
+}
+
+// Write Function
+func (ns *NamespaceSink) Write(namespaceName, str string) error {
+	name := namespaceName
+	if name == "" {
+		name = HostSinkName
+	}
+
+	ns.lock.Lock()
+	defer ns.lock.Unlock()
+
+	nsFile, err := ns.getFile(name)
+	if err != nil {
+		return err
+	}
+
+	policy := ns.GetRetentionPolicy(name)
+
+	if policy.MaxSize > 0 && nsFile.size > 0 && nsFile.size+int64(len(str))+1 > policy.MaxSize {
+		if err := ns.rotate(name); err != nil {
+			return err
+		}
+
+		if nsFile, err = ns.getFile(name); err != nil {
+			return err
+		}
+	}
+
+	n, err := nsFile.file.WriteString(str + "\n")
+	nsFile.size += int64(n)
+
+	return err
+}
+
+// Close Function
+func (ns *NamespaceSink) Close() {
+	if ns.stopChan != nil {
+		close(ns.stopChan)
+		ns.wg.Wait()
+		ns.stopChan = nil
+	}
+
+	ns.lock.Lock()
+	defer ns.lock.Unlock()
+
+	for name := range ns.files {
+		ns.closeFile(name)
+	}
+}
@@ -0,0 +1,190 @@
+package feeder
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNamespaceSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubearmor-sink")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a temporary directory (%s)", err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	// create a namespace sink
+
+	ns, err := NewNamespaceSink("namespace://" + dir + "?maxOpenFiles=2&maxSize=1&prod.maxBackups=1")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a namespace sink (%s)", err.Error())
+		return
+	}
+	defer ns.Close()
+
+	t.Log("[PASS] Created a namespace sink")
+
+	// check retention policies
+
+	if ns.GetRetentionPolicy("prod").MaxBackups != 1 || ns.GetRetentionPolicy("prod").MaxSize != 1024*1024 {
+		t.Errorf("[FAIL] Failed to override the retention policy for prod (%v)", ns.GetRetentionPolicy("prod"))
+		return
+	}
+
+	if ns.GetRetentionPolicy("dev").MaxBackups != 3 {
+		t.Errorf("[FAIL] Failed to apply the default retention policy to dev (%v)", ns.GetRetentionPolicy("dev"))
+		return
+	}
+
+	t.Log("[PASS] Checked retention policies")
+
+	// write logs
+
+	events := []struct {
+		namespace string
+		msg       string
+	}{
+		{"prod", "prod-1"},
+		{"dev", "dev-1"},
+		{"test", "test-1"},
+		{"", "host-1"},
+		{"host", "host-ns-1"},
+		{"prod", "prod-2"},
+		{"dev", "dev-2"},
+	}
+
+	for _, event := range events {
+		if err := ns.Write(event.namespace, event.msg); err != nil {
+			t.Errorf("[FAIL] Failed to write a log (%s)", err.Error())
+			return
+		}
+
+		if ns.GetOpenFileCount() > 2 {
+			t.Errorf("[FAIL] Too many open files (%d)", ns.GetOpenFileCount())
+			return
+		}
+	}
+
+	t.Log("[PASS] Wrote logs with bounded file handles")
+
+	// check log files
+
+	expected := map[string]string{
+		"prod":       "prod-1\nprod-2\n",
+		"dev":        "dev-1\ndev-2\n",
+		"test":       "test-1\n",
+		"host":       "host-ns-1\n",
+		HostSinkName: "host-1\n",
+	}
+
+	for name, content := range expected {
+		data, err := ioutil.ReadFile(ns.GetLogPath(name))
+		if err != nil {
+			t.Errorf("[FAIL] Failed to read %s (%s)", ns.GetLogPath(name), err.Error())
+			return
+		}
+
+		if string(data) != content {
+			t.Errorf("[FAIL] Unexpected logs in %s (%q)", ns.GetLogPath(name), string(data))
+			return
+		}
+	}
+
+	if data, err := ioutil.ReadFile(filepath.Join(dir, "host.log")); err != nil || string(data) != "host-1\n" {
+		t.Errorf("[FAIL] Failed to write host logs to host.log (%q)", string(data))
+		return
+	}
+
+	if data, err := ioutil.ReadFile(filepath.Join(dir, "host_ns.log")); err != nil || string(data) != "host-ns-1\n" {
+		t.Errorf("[FAIL] Failed to write the logs of the host namespace to host_ns.log (%q)", string(data))
+		return
+	}
+
+	t.Log("[PASS] Checked logs per namespace")
+
+	// rotate a log file
+
+	large := strings.Repeat("x", 600*1024)
+
+	for i := 0; i < 3; i++ {
+		if err := ns.Write("prod", large); err != nil {
+			t.Errorf("[FAIL] Failed to write a log (%s)", err.Error())
+			return
+		}
+	}
+
+	if _, err := os.Stat(ns.GetLogPath("prod") + ".1"); err != nil {
+		t.Errorf("[FAIL] Failed to rotate prod.log (%s)", err.Error())
+		return
+	}
+
+	if _, err := os.Stat(ns.GetLogPath("prod") + ".2"); err == nil {
+		t.Errorf("[FAIL] Kept more backups than maxBackups for prod")
+		return
+	}
+
+	t.Log("[PASS] Rotated a log file")
+}
+
+func TestNamespaceSinkPrune(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubearmor-sink")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a temporary directory (%s)", err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	ns, err := NewNamespaceSink("namespace://" + dir + "?maxAge=1&dev.maxAge=0&host.maxAge=0")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a namespace sink (%s)", err.Error())
+		return
+	}
+	defer ns.Close()
+
+	// rotated files left behind without new logs
+
+	old := time.Now().Add(-time.Hour * 2)
+
+	for _, backup := range []string{ns.GetLogPath("prod") + ".1", ns.GetLogPath("prod") + ".2", ns.GetLogPath("dev") + ".1", ns.GetLogPath(HostSinkName) + ".1", ns.GetLogPath("host") + ".1"} {
+		if err := ioutil.WriteFile(backup, []byte("old\n"), 0600); err != nil {
+			t.Errorf("[FAIL] Failed to write a rotated file (%s)", err.Error())
+			return
+		}
+
+		if err := os.Chtimes(backup, old, old); err != nil {
+			t.Errorf("[FAIL] Failed to change the time of a rotated file (%s)", err.Error())
+			return
+		}
+	}
+
+	if err := ioutil.WriteFile(ns.GetLogPath("prod")+".3", []byte("new\n"), 0600); err != nil {
+		t.Errorf("[FAIL] Failed to write a rotated file (%s)", err.Error())
+		return
+	}
+
+	if pruned := ns.Prune(time.Now()); pruned != 3 {
+		t.Errorf("[FAIL] Failed to prune old rotated files only (%d pruned)", pruned)
+		return
+	}
+
+	if _, err := os.Stat(ns.GetLogPath("dev") + ".1"); err != nil {
+		t.Errorf("[FAIL] Pruned a rotated file without maxAge (%s)", err.Error())
+		return
+	}
+
+	if _, err := os.Stat(ns.GetLogPath("prod") + ".3"); err != nil {
+		t.Errorf("[FAIL] Pruned a recent rotated file (%s)", err.Error())
+		return
+	}
+
+	if _, err := os.Stat(ns.GetLogPath("host") + ".1"); err != nil {
+		t.Errorf("[FAIL] Applied the retention policy of the host to the host namespace (%s)", err.Error())
+		return
+	}
+
+	t.Log("[PASS] Pruned old rotated files without rotation")
+}
@@ -3,6 +3,7 @@ package feeder
 import (
 	"strconv"
 	"strings"
+	"time"
 
 	kl "github.com/accuknox/KubeArmor/KubeArmor/common"
 	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
@@ -14,6 +15,9 @@ import (
 
 // UpdateSecurityPolicies Function
 func (fd *Feeder) UpdateSecurityPolicies(action string, conGroup tp.ContainerGroup) {
+	// update the severity escalation according to the labels
+	fd.UpdateSeverityEscalation(action, conGroup)
+
 	if action == "DELETED" {
 		delete(fd.SecurityPolicies, conGroup.NamespaceName+"_"+conGroup.ContainerGroupName)
 	} else { // ADDED | MODIFIED
@@ -29,12 +33,14 @@ func (fd *Feeder) UpdateSecurityPolicies(action string, conGroup tp.ContainerGro
 						match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 						match.Tags = secPolicy.Spec.Tags
+						setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 						match.Message = secPolicy.Spec.Message
 
 						match.Source = ""
 						match.Operation = "Process"
 						match.Resource = path.Path
 						match.Action = secPolicy.Spec.Action
+						match.ExecHash = path.SHA256
 
 						matches.Policies = append(matches.Policies, match)
 					} else {
@@ -46,12 +52,14 @@ func (fd *Feeder) UpdateSecurityPolicies(action string, conGroup tp.ContainerGro
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								match.Source = src.Path
 								match.Operation = "Process"
 								match.Resource = path.Path
 								match.Action = secPolicy.Spec.Action
+								match.ExecHash = path.SHA256
 
 								matches.Policies = append(matches.Policies, match)
 							} else if len(src.Directory) > 0 {
@@ -61,12 +69,14 @@ func (fd *Feeder) UpdateSecurityPolicies(action string, conGroup tp.ContainerGro
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								match.Source = src.Directory
 								match.Operation = "Process"
 								match.Resource = path.Path
 								match.Action = secPolicy.Spec.Action
+								match.ExecHash = path.SHA256
 
 								matches.Policies = append(matches.Policies, match)
 							}
@@ -84,6 +94,7 @@ func (fd *Feeder) UpdateSecurityPolicies(action string, conGroup tp.ContainerGro
 						match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 						match.Tags = secPolicy.Spec.Tags
+						setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 						match.Message = secPolicy.Spec.Message
 
 						match.Source = ""
@@ -101,6 +112,7 @@ func (fd *Feeder) UpdateSecurityPolicies(action string, conGroup tp.ContainerGro
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								match.Source = src.Path
@@ -116,6 +128,7 @@ func (fd *Feeder) UpdateSecurityPolicies(action string, conGroup tp.ContainerGro
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								match.Source = src.Directory
@@ -143,6 +156,7 @@ func (fd *Feeder) UpdateSecurityPolicies(action string, conGroup tp.ContainerGro
 						match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 						match.Tags = secPolicy.Spec.Tags
+						setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 						match.Message = secPolicy.Spec.Message
 
 						match.Source = ""
@@ -160,6 +174,7 @@ func (fd *Feeder) UpdateSecurityPolicies(action string, conGroup tp.ContainerGro
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								match.Source = src.Path
@@ -175,6 +190,7 @@ func (fd *Feeder) UpdateSecurityPolicies(action string, conGroup tp.ContainerGro
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								match.Source = src.Directory
@@ -202,6 +218,7 @@ func (fd *Feeder) UpdateSecurityPolicies(action string, conGroup tp.ContainerGro
 						match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 						match.Tags = secPolicy.Spec.Tags
+						setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 						match.Message = secPolicy.Spec.Message
 
 						match.Source = ""
@@ -219,6 +236,7 @@ func (fd *Feeder) UpdateSecurityPolicies(action string, conGroup tp.ContainerGro
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								match.Source = src.Path
@@ -234,6 +252,7 @@ func (fd *Feeder) UpdateSecurityPolicies(action string, conGroup tp.ContainerGro
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								match.Source = src.Directory
@@ -257,6 +276,7 @@ func (fd *Feeder) UpdateSecurityPolicies(action string, conGroup tp.ContainerGro
 						match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 						match.Tags = secPolicy.Spec.Tags
+						setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 						match.Message = secPolicy.Spec.Message
 
 						match.Source = ""
@@ -288,6 +308,7 @@ func (fd *Feeder) UpdateSecurityPolicies(action string, conGroup tp.ContainerGro
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								match.Source = src.Path
@@ -317,6 +338,7 @@ func (fd *Feeder) UpdateSecurityPolicies(action string, conGroup tp.ContainerGro
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								match.Source = src.Directory
@@ -354,6 +376,7 @@ func (fd *Feeder) UpdateSecurityPolicies(action string, conGroup tp.ContainerGro
 						match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 						match.Tags = secPolicy.Spec.Tags
+						setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 						match.Message = secPolicy.Spec.Message
 
 						switch cap.Capability {
@@ -374,6 +397,7 @@ func (fd *Feeder) UpdateSecurityPolicies(action string, conGroup tp.ContainerGro
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								switch cap.Capability {
@@ -392,6 +416,7 @@ func (fd *Feeder) UpdateSecurityPolicies(action string, conGroup tp.ContainerGro
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								switch cap.Capability {
@@ -414,6 +439,10 @@ func (fd *Feeder) UpdateSecurityPolicies(action string, conGroup tp.ContainerGro
 			}
 		}
 
+		// evaluate the host policies together for the pods sharing the host namespaces
+		matches.HostPID = conGroup.HostPID
+		matches.HostNetwork = conGroup.HostNetwork
+
 		name := conGroup.NamespaceName + "_" + conGroup.ContainerGroupName
 
 		fd.SecurityPoliciesLock.Lock()
@@ -443,12 +472,14 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 						match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 						match.Tags = secPolicy.Spec.Tags
+						setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 						match.Message = secPolicy.Spec.Message
 
 						match.Source = ""
 						match.Operation = "Process"
 						match.Resource = path.Path
 						match.Action = secPolicy.Spec.Action
+						match.ExecHash = path.SHA256
 
 						matches.Policies = append(matches.Policies, match)
 					} else {
@@ -460,12 +491,14 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								match.Source = src.Path
 								match.Operation = "Process"
 								match.Resource = path.Path
 								match.Action = secPolicy.Spec.Action
+								match.ExecHash = path.SHA256
 
 								matches.Policies = append(matches.Policies, match)
 							} else if len(src.Directory) > 0 {
@@ -475,12 +508,14 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								match.Source = src.Directory
 								match.Operation = "Process"
 								match.Resource = path.Path
 								match.Action = secPolicy.Spec.Action
+								match.ExecHash = path.SHA256
 
 								matches.Policies = append(matches.Policies, match)
 							}
@@ -498,6 +533,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 						match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 						match.Tags = secPolicy.Spec.Tags
+						setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 						match.Message = secPolicy.Spec.Message
 
 						match.Source = ""
@@ -515,6 +551,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								match.Source = src.Path
@@ -530,6 +567,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								match.Source = src.Directory
@@ -557,6 +595,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 						match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 						match.Tags = secPolicy.Spec.Tags
+						setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 						match.Message = secPolicy.Spec.Message
 
 						match.Source = ""
@@ -574,6 +613,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								match.Source = src.Path
@@ -589,6 +629,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								match.Source = src.Directory
@@ -616,6 +657,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 						match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 						match.Tags = secPolicy.Spec.Tags
+						setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 						match.Message = secPolicy.Spec.Message
 
 						match.Source = ""
@@ -633,6 +675,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								match.Source = src.Path
@@ -648,6 +691,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								match.Source = src.Directory
@@ -673,6 +717,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								match.Source = src.Path
@@ -702,6 +747,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								match.Source = src.Directory
@@ -741,6 +787,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								switch cap.Capability {
@@ -759,6 +806,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 								match.Severity = strconv.Itoa(secPolicy.Spec.Severity)
 
 								match.Tags = secPolicy.Spec.Tags
+								setMatchTags(&match, secPolicy.Spec.ComplianceTags, secPolicy.Spec.MitreTechniques)
 								match.Message = secPolicy.Spec.Message
 
 								switch cap.Capability {
@@ -787,33 +835,82 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 // == Policy Matches == //
 // ==================== //
 
-// UpdateMatchedPolicy Function
-func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
+// setMatchTags Function
+func setMatchTags(match *tp.MatchPolicy, complianceTags, mitreTechniques []string) {
+	match.ComplianceTags = complianceTags
+	match.MitreTechniques = mitreTechniques
+}
+
+// setLogTags Function
+func setLogTags(log *tp.Log, complianceTags, mitreTechniques []string) {
+	if len(complianceTags) > 0 {
+		log.ComplianceTags = complianceTags
+	}
+
+	if len(mitreTechniques) > 0 {
+		log.MitreTechniques = mitreTechniques
+	}
+}
+
+// mergeTags Function
+func mergeTags(tags, newTags []string) []string {
+	for _, tag := range newTags {
+		if !kl.ContainsElement(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+// matchPolicies Function
+//
+// returns the latency of the policy loop as well (0 if no policy is evaluated)
+func (fd *Feeder) matchPolicies(log tp.Log) (tp.Log, time.Duration) {
+	var latency time.Duration
+
 	allowProcPolicy := ""
 	allowProcPolicySeverity := ""
 	allowProcTags := []string{}
+	allowProcComplianceTags := []string{}
+	allowProcMitreTechniques := []string{}
 	allowProcMessage := ""
 
 	allowFilePolicy := ""
 	allowFilePolicySeverity := ""
 	allowFileTags := []string{}
+	allowFileComplianceTags := []string{}
+	allowFileMitreTechniques := []string{}
 	allowFileMessage := ""
 
 	allowNetworkPolicy := ""
 	allowNetworkPolicySeverity := ""
 	allowNetworkTags := []string{}
+	allowNetworkComplianceTags := []string{}
+	allowNetworkMitreTechniques := []string{}
 	allowNetworkMessage := ""
 
+	// alerts raised by monitors (e.g., fileless executions) are not matched with policies
+	if log.Type == "MatchedPolicy" || log.Type == "MatchedHostPolicy" {
+		return log, 0
+	}
+
 	if log.Result == "Passed" || log.Result == "Operation not permitted" || log.Result == "Permission denied" {
 		fd.SecurityPoliciesLock.RLock()
 
+		start := time.Now()
+
 		key := log.HostName
 
 		if log.NamespaceName != "" && log.PodName != "" {
 			key = log.NamespaceName + "_" + log.PodName
 		}
 
-		secPolicies := fd.SecurityPolicies[key].Policies
+		// compare the canonical path of a resource (e.g., /usr//bin/./sh -> /usr/bin/sh)
+		resource := kl.GetCanonicalResource(log.Resource)
+
+		// the host policies are merged for hostPID/hostNetwork containers
+		secPolicies := fd.getMatchPolicies(key, log)
 		for _, secPolicy := range secPolicies {
 			if secPolicy.Source == "" || strings.Contains(secPolicy.Source, log.Source) {
 				if secPolicy.Action == "Allow" || secPolicy.Action == "AllowWithAudit" {
@@ -828,6 +925,9 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 								}
 							}
 
+							allowProcComplianceTags = mergeTags(allowProcComplianceTags, secPolicy.ComplianceTags)
+							allowProcMitreTechniques = mergeTags(allowProcMitreTechniques, secPolicy.MitreTechniques)
+
 							allowProcMessage = secPolicy.Message
 						} else if !strings.Contains(allowProcPolicy, secPolicy.PolicyName) {
 							allowProcPolicy = allowProcPolicy + "," + secPolicy.PolicyName
@@ -839,6 +939,9 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 								}
 							}
 
+							allowProcComplianceTags = mergeTags(allowProcComplianceTags, secPolicy.ComplianceTags)
+							allowProcMitreTechniques = mergeTags(allowProcMitreTechniques, secPolicy.MitreTechniques)
+
 							allowProcMessage = allowProcMessage + "," + secPolicy.Message
 						}
 					} else if secPolicy.Operation == "File" {
@@ -852,6 +955,9 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 								}
 							}
 
+							allowFileComplianceTags = mergeTags(allowFileComplianceTags, secPolicy.ComplianceTags)
+							allowFileMitreTechniques = mergeTags(allowFileMitreTechniques, secPolicy.MitreTechniques)
+
 							allowFileMessage = secPolicy.Message
 						} else if !strings.Contains(allowFilePolicy, secPolicy.PolicyName) {
 							allowFilePolicy = allowFilePolicy + "," + secPolicy.PolicyName
@@ -863,6 +969,9 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 								}
 							}
 
+							allowFileComplianceTags = mergeTags(allowFileComplianceTags, secPolicy.ComplianceTags)
+							allowFileMitreTechniques = mergeTags(allowFileMitreTechniques, secPolicy.MitreTechniques)
+
 							allowFileMessage = allowFileMessage + "," + secPolicy.Message
 						}
 					} else if secPolicy.Operation == "Network" {
@@ -876,6 +985,9 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 								}
 							}
 
+							allowNetworkComplianceTags = mergeTags(allowNetworkComplianceTags, secPolicy.ComplianceTags)
+							allowNetworkMitreTechniques = mergeTags(allowNetworkMitreTechniques, secPolicy.MitreTechniques)
+
 							allowNetworkMessage = secPolicy.Message
 						} else if !strings.Contains(allowNetworkPolicy, secPolicy.PolicyName) {
 							allowNetworkPolicy = allowNetworkPolicy + "," + secPolicy.PolicyName
@@ -887,6 +999,9 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 								}
 							}
 
+							allowNetworkComplianceTags = mergeTags(allowNetworkComplianceTags, secPolicy.ComplianceTags)
+							allowNetworkMitreTechniques = mergeTags(allowNetworkMitreTechniques, secPolicy.MitreTechniques)
+
 							allowNetworkMessage = allowNetworkMessage + "," + secPolicy.Message
 						}
 					}
@@ -896,7 +1011,7 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 			switch log.Operation {
 			case "Process", "File":
 				if secPolicy.Operation == log.Operation {
-					if strings.HasPrefix(log.Resource, secPolicy.Resource) {
+					if strings.HasPrefix(resource, secPolicy.Resource) {
 						if secPolicy.Source != "" && strings.Contains(secPolicy.Source, log.Source) {
 							log.PolicyName = secPolicy.PolicyName
 							log.Severity = secPolicy.Severity
@@ -905,12 +1020,15 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 								log.Tags = strings.Join(secPolicy.Tags[:], ",")
 							}
 
+							setLogTags(&log, secPolicy.ComplianceTags, secPolicy.MitreTechniques)
+
 							if len(secPolicy.Message) > 0 {
 								log.Message = secPolicy.Message
 							}
 
 							log.Type = "MatchedPolicy"
 							log.Action = secPolicy.Action
+							log.PolicySet = secPolicy.PolicySet
 
 							break
 						} else if secPolicy.Source == "" {
@@ -921,12 +1039,15 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 								log.Tags = strings.Join(secPolicy.Tags[:], ",")
 							}
 
+							setLogTags(&log, secPolicy.ComplianceTags, secPolicy.MitreTechniques)
+
 							if len(secPolicy.Message) > 0 {
 								log.Message = secPolicy.Message
 							}
 
 							log.Type = "MatchedPolicy"
 							log.Action = secPolicy.Action
+							log.PolicySet = secPolicy.PolicySet
 
 							break
 						}
@@ -943,12 +1064,15 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 								log.Tags = strings.Join(secPolicy.Tags[:], ",")
 							}
 
+							setLogTags(&log, secPolicy.ComplianceTags, secPolicy.MitreTechniques)
+
 							if len(secPolicy.Message) > 0 {
 								log.Message = secPolicy.Message
 							}
 
 							log.Type = "MatchedPolicy"
 							log.Action = secPolicy.Action
+							log.PolicySet = secPolicy.PolicySet
 
 							break
 						} else if secPolicy.Source == "" {
@@ -959,12 +1083,15 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 								log.Tags = strings.Join(secPolicy.Tags[:], ",")
 							}
 
+							setLogTags(&log, secPolicy.ComplianceTags, secPolicy.MitreTechniques)
+
 							if len(secPolicy.Message) > 0 {
 								log.Message = secPolicy.Message
 							}
 
 							log.Type = "MatchedPolicy"
 							log.Action = secPolicy.Action
+							log.PolicySet = secPolicy.PolicySet
 
 							break
 						}
@@ -973,6 +1100,8 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 			}
 		}
 
+		latency = time.Since(start)
+
 		fd.SecurityPoliciesLock.RUnlock()
 	}
 
@@ -987,6 +1116,8 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 						log.Tags = strings.Join(allowProcTags[:], ",")
 					}
 
+					setLogTags(&log, allowProcComplianceTags, allowProcMitreTechniques)
+
 					if len(allowProcMessage) > 0 {
 						log.Message = allowProcMessage
 					}
@@ -994,7 +1125,7 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 					log.Type = "MatchedPolicy"
 					log.Action = "Allow"
 
-					return log
+					return log, latency
 
 				} else if log.Operation == "File" && allowFilePolicy != "" {
 					log.PolicyName = allowFilePolicy
@@ -1004,6 +1135,8 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 						log.Tags = strings.Join(allowFileTags[:], ",")
 					}
 
+					setLogTags(&log, allowFileComplianceTags, allowFileMitreTechniques)
+
 					if len(allowFileMessage) > 0 {
 						log.Message = allowFileMessage
 					}
@@ -1011,7 +1144,7 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 					log.Type = "MatchedPolicy"
 					log.Action = "Allow"
 
-					return log
+					return log, latency
 
 				} else if log.Operation == "Network" && allowNetworkPolicy != "" {
 					log.PolicyName = allowNetworkPolicy
@@ -1021,6 +1154,8 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 						log.Tags = strings.Join(allowNetworkTags[:], ",")
 					}
 
+					setLogTags(&log, allowNetworkComplianceTags, allowNetworkMitreTechniques)
+
 					if len(allowNetworkMessage) > 0 {
 						log.Message = allowNetworkMessage
 					}
@@ -1028,25 +1163,25 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 					log.Type = "MatchedPolicy"
 					log.Action = "Allow"
 
-					return log
+					return log, latency
 
 				}
 
 				if fd.EnableSystemLog {
 					// Failed operations
 					log.Type = "ContainerLog"
-					return log
+					return log, latency
 				}
 			} else {
 				if log.Action == "Allow" {
 					// use 'AllowWithAudit' to get the logs for allowed operations
-					return tp.Log{}
+					return tp.Log{}, latency
 				}
 
 				if fd.EnableSystemLog {
 					// Passed operations
 					log.Type = "ContainerLog"
-					return log
+					return log, latency
 				}
 			}
 		} else if log.Type == "MatchedPolicy" {
@@ -1055,7 +1190,7 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 			// 	return tp.Log{}
 			// }
 
-			return log
+			return log, latency
 		}
 	} else { // host
 		if log.Type == "" {
@@ -1068,6 +1203,8 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 						log.Tags = strings.Join(allowProcTags[:], ",")
 					}
 
+					setLogTags(&log, allowProcComplianceTags, allowProcMitreTechniques)
+
 					if len(allowProcMessage) > 0 {
 						log.Message = allowProcMessage
 					}
@@ -1075,7 +1212,7 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 					log.Type = "MatchedHostPolicy"
 					log.Action = "Allow"
 
-					return log
+					return log, latency
 
 				} else if log.Operation == "File" && allowFilePolicy != "" {
 					log.PolicyName = allowFilePolicy
@@ -1085,6 +1222,8 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 						log.Tags = strings.Join(allowFileTags[:], ",")
 					}
 
+					setLogTags(&log, allowFileComplianceTags, allowFileMitreTechniques)
+
 					if len(allowFileMessage) > 0 {
 						log.Message = allowFileMessage
 					}
@@ -1092,7 +1231,7 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 					log.Type = "MatchedHostPolicy"
 					log.Action = "Allow"
 
-					return log
+					return log, latency
 
 				} else if log.Operation == "Network" && allowNetworkPolicy != "" {
 					log.PolicyName = allowNetworkPolicy
@@ -1102,6 +1241,8 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 						log.Tags = strings.Join(allowNetworkTags[:], ",")
 					}
 
+					setLogTags(&log, allowNetworkComplianceTags, allowNetworkMitreTechniques)
+
 					if len(allowNetworkMessage) > 0 {
 						log.Message = allowNetworkMessage
 					}
@@ -1109,7 +1250,7 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 					log.Type = "MatchedHostPolicy"
 					log.Action = "Allow"
 
-					return log
+					return log, latency
 
 				}
 
@@ -1121,7 +1262,7 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 			} else {
 				if log.Action == "Allow" {
 					// use 'AllowWithAudit' to get the logs for allowed operations
-					return tp.Log{}
+					return tp.Log{}, latency
 				}
 
 				// if fd.EnableSystemLog {
@@ -1137,9 +1278,9 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 			// }
 
 			log.Type = "MatchedHostPolicy"
-			return log
+			return log, latency
 		}
 	}
 
-	return tp.Log{}
+	return tp.Log{}, latency
 }
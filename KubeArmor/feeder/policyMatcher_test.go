@@ -0,0 +1,119 @@
+package feeder
+
+import (
+	"sync"
+	"testing"
+
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+func TestUpdateMatchedPolicy(t *testing.T) {
+	fd := &Feeder{}
+	fd.SecurityPoliciesLock = new(sync.RWMutex)
+	fd.SecurityPolicies = map[string]tp.MatchPolicies{
+		"multiubuntu_ubuntu-1": {Policies: []tp.MatchPolicy{
+			{PolicyName: "ksp-ubuntu-1-proc-path-block", Severity: "5", Operation: "Process", Resource: "/bin/sleep", Action: "Block"},
+		}},
+	}
+
+	// a dirty resource
+
+	log := tp.Log{ContainerID: "ubuntu-1-container", NamespaceName: "multiubuntu", PodName: "ubuntu-1", Operation: "Process", Resource: "/bin//./sleep 1", Result: "Permission denied"}
+	log = fd.UpdateMatchedPolicy(log)
+
+	if log.PolicyName != "ksp-ubuntu-1-proc-path-block" || log.Type != "MatchedPolicy" {
+		t.Errorf("[FAIL] Failed to match a dirty resource with a policy (%v)", log)
+		return
+	}
+
+	if log.Resource != "/bin//./sleep 1" {
+		t.Errorf("[FAIL] Changed the resource of a log (%s)", log.Resource)
+		return
+	}
+
+	t.Log("[PASS] Matched a dirty resource with a policy")
+}
+
+func TestUpdateMatchedPolicyWithMitreTechniques(t *testing.T) {
+	fd := &Feeder{}
+	fd.SecurityPolicies = map[string]tp.MatchPolicies{}
+	fd.SecurityPoliciesLock = new(sync.RWMutex)
+	fd.Escalations = map[string]int{}
+	fd.EscalationsLock = new(sync.RWMutex)
+
+	// a container group with policies mapped to techniques
+
+	conGroup := tp.ContainerGroup{NamespaceName: "multiubuntu", ContainerGroupName: "ubuntu-1"}
+
+	blockPolicy := tp.SecurityPolicy{Metadata: map[string]string{"policyName": "ksp-ubuntu-1-proc-path-block"}}
+	blockPolicy.Spec.Severity = 5
+	blockPolicy.Spec.ComplianceTags = []string{"PCI-DSS:10.2.1"}
+	blockPolicy.Spec.MitreTechniques = []string{"T1059", "T1059.004"}
+	blockPolicy.Spec.Process.MatchPaths = []tp.ProcessPathType{{Path: "/bin/bash"}}
+	blockPolicy.Spec.Action = "Block"
+
+	allowPolicy := tp.SecurityPolicy{Metadata: map[string]string{"policyName": "ksp-ubuntu-1-file-dir-allow"}}
+	allowPolicy.Spec.Severity = 1
+	allowPolicy.Spec.MitreTechniques = []string{"T1005"}
+	allowPolicy.Spec.File.MatchDirectories = []tp.FileDirectoryType{{Directory: "/credentials/"}}
+	allowPolicy.Spec.Action = "Allow"
+
+	conGroup.SecurityPolicies = []tp.SecurityPolicy{blockPolicy, allowPolicy}
+
+	fd.UpdateSecurityPolicies("ADDED", conGroup)
+
+	// a blocked process
+
+	log := tp.Log{ContainerID: "ubuntu-1-container", NamespaceName: "multiubuntu", PodName: "ubuntu-1", Operation: "Process", Resource: "/bin/bash -c id", Result: "Permission denied"}
+	log = fd.UpdateMatchedPolicy(log)
+
+	if log.Type != "MatchedPolicy" || log.PolicyName != "ksp-ubuntu-1-proc-path-block" {
+		t.Errorf("[FAIL] Failed to match a policy (%v)", log)
+		return
+	}
+
+	if len(log.MitreTechniques) != 2 || log.MitreTechniques[0] != "T1059" || log.MitreTechniques[1] != "T1059.004" {
+		t.Errorf("[FAIL] Failed to add the MITRE techniques of a policy (%v)", log.MitreTechniques)
+		return
+	}
+
+	if len(log.ComplianceTags) != 1 || log.ComplianceTags[0] != "PCI-DSS:10.2.1" {
+		t.Errorf("[FAIL] Failed to add the compliance tags of a policy (%v)", log.ComplianceTags)
+		return
+	}
+
+	t.Log("[PASS] Added the MITRE techniques of a matched policy")
+
+	// a file access denied by an allow policy
+
+	log = tp.Log{ContainerID: "ubuntu-1-container", NamespaceName: "multiubuntu", PodName: "ubuntu-1", Operation: "File", Resource: "/etc/shadow", Result: "Permission denied"}
+	log = fd.UpdateMatchedPolicy(log)
+
+	if log.Action != "Allow" || len(log.MitreTechniques) != 1 || log.MitreTechniques[0] != "T1005" || len(log.ComplianceTags) != 0 {
+		t.Errorf("[FAIL] Failed to add the MITRE techniques of an allow policy (%v)", log)
+		return
+	}
+
+	t.Log("[PASS] Added the MITRE techniques of an allow policy")
+}
+
+func TestUpdateMatchedPolicyWithMonitorAlert(t *testing.T) {
+	fd := &Feeder{}
+	fd.SecurityPoliciesLock = new(sync.RWMutex)
+	fd.SecurityPolicies = map[string]tp.MatchPolicies{
+		"multiubuntu_ubuntu-1": {Policies: []tp.MatchPolicy{
+			{PolicyName: "ksp-ubuntu-1-proc-path-audit", Severity: "1", Operation: "Process", Resource: "/tmp/dropper", Action: "Audit"},
+		}},
+	}
+
+	// a fileless execution raised by the system monitor
+
+	log := tp.Log{ContainerID: "ubuntu-1-container", NamespaceName: "multiubuntu", PodName: "ubuntu-1", Type: "MatchedPolicy", Severity: "10", Tags: "fileless", Operation: "Process", Resource: "/tmp/dropper", Action: "Audit", Result: "Passed"}
+
+	if updated := fd.UpdateMatchedPolicy(log); updated.PolicyName != "" || updated.Severity != "10" || updated.Tags != "fileless" {
+		t.Errorf("[FAIL] Overwrote an alert raised by the system monitor (%v)", updated)
+		return
+	}
+
+	t.Log("[PASS] Kept an alert raised by the system monitor")
+}
@@ -0,0 +1,57 @@
+package feeder
+
+import (
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+// ================ //
+// == Policy Set == //
+// ================ //
+
+const (
+	// PolicySetContainer for the decisions made by container policies
+	PolicySetContainer = "container"
+
+	// PolicySetHost for the decisions made by host policies
+	PolicySetHost = "host"
+)
+
+// getMatchPolicies Function (SecurityPoliciesLock should be held)
+func (fd *Feeder) getMatchPolicies(key string, log tp.Log) []tp.MatchPolicy {
+	matches := fd.SecurityPolicies[key]
+
+	if log.ContainerID == "" || key == fd.hostName || (!matches.HostPID && !matches.HostNetwork) {
+		return matches.Policies
+	}
+
+	// the last matched policy wins, so the host policies come first and the
+	// container policies (more specific) take precedence over them
+
+	secPolicies := []tp.MatchPolicy{}
+
+	for _, secPolicy := range fd.SecurityPolicies[fd.hostName].Policies {
+		// the allow posture of the host is not applied to containers
+		if secPolicy.Action == "Allow" || secPolicy.Action == "AllowWithAudit" {
+			continue
+		}
+
+		// process and file operations for hostPID, network operations for hostNetwork
+		if (matches.HostPID && (secPolicy.Operation == "Process" || secPolicy.Operation == "File")) ||
+			(matches.HostNetwork && secPolicy.Operation == "Network") {
+			// the host profiles are not enforced in containers, so the deny actions are only audited
+			if secPolicy.Action == "Block" || secPolicy.Action == "BlockWithAudit" || secPolicy.Action == "Quarantine" {
+				secPolicy.Action = "Audit"
+			}
+
+			secPolicy.PolicySet = PolicySetHost
+			secPolicies = append(secPolicies, secPolicy)
+		}
+	}
+
+	for _, secPolicy := range matches.Policies {
+		secPolicy.PolicySet = PolicySetContainer
+		secPolicies = append(secPolicies, secPolicy)
+	}
+
+	return secPolicies
+}
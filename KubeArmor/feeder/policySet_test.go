@@ -0,0 +1,105 @@
+package feeder
+
+import (
+	"sync"
+	"testing"
+
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+func TestMergedPolicySets(t *testing.T) {
+	fd := &Feeder{}
+	fd.hostName = "kubearmor-dev"
+	fd.SecurityPolicies = map[string]tp.MatchPolicies{}
+	fd.SecurityPoliciesLock = new(sync.RWMutex)
+	fd.Escalations = map[string]int{}
+	fd.EscalationsLock = new(sync.RWMutex)
+
+	// host policies
+
+	diffPolicy := tp.HostSecurityPolicy{Metadata: map[string]string{"policyName": "hsp-kubearmor-dev-proc-path-block"}}
+	diffPolicy.Spec.Severity = 5
+	diffPolicy.Spec.Process.MatchPaths = []tp.ProcessPathType{{Path: "/usr/bin/diff"}}
+	diffPolicy.Spec.Action = "Block"
+
+	bashPolicy := tp.HostSecurityPolicy{Metadata: map[string]string{"policyName": "hsp-kubearmor-dev-bash-block"}}
+	bashPolicy.Spec.Severity = 7
+	bashPolicy.Spec.Process.MatchPaths = []tp.ProcessPathType{{Path: "/bin/bash"}}
+	bashPolicy.Spec.Action = "Block"
+
+	fd.UpdateHostSecurityPolicies("ADDED", []tp.HostSecurityPolicy{diffPolicy, bashPolicy})
+
+	// container policies overlapping with the host policies
+
+	auditPolicy := tp.SecurityPolicy{Metadata: map[string]string{"policyName": "ksp-ubuntu-1-bash-audit"}}
+	auditPolicy.Spec.Severity = 3
+	auditPolicy.Spec.Process.MatchPaths = []tp.ProcessPathType{{Path: "/bin/bash"}}
+	auditPolicy.Spec.Action = "Audit"
+
+	// a hostPID pod and a normal pod
+
+	fd.UpdateSecurityPolicies("ADDED", tp.ContainerGroup{NamespaceName: "multiubuntu", ContainerGroupName: "ubuntu-1", HostPID: true, SecurityPolicies: []tp.SecurityPolicy{auditPolicy}})
+	fd.UpdateSecurityPolicies("ADDED", tp.ContainerGroup{NamespaceName: "multiubuntu", ContainerGroupName: "ubuntu-2", SecurityPolicies: []tp.SecurityPolicy{auditPolicy}})
+
+	newLog := func(podName, resource string) tp.Log {
+		return tp.Log{HostName: "kubearmor-dev", ContainerID: podName + "-container", NamespaceName: "multiubuntu", PodName: podName, Operation: "Process", Resource: resource, Result: "Passed"}
+	}
+
+	// only a host policy matches (not enforced in the container, so only audited)
+
+	log := fd.UpdateMatchedPolicy(newLog("ubuntu-1", "/usr/bin/diff"))
+	if log.Type != "MatchedPolicy" || log.PolicyName != "hsp-kubearmor-dev-proc-path-block" || log.Action != "Audit" || log.Result != "Passed" || log.PolicySet != PolicySetHost {
+		t.Errorf("[FAIL] Failed to evaluate a host policy for a hostPID container (%v)", log)
+		return
+	}
+
+	t.Log("[PASS] Evaluated a host policy for a hostPID container")
+
+	// the actions of host policies are kept for the host
+
+	fd.SecurityPoliciesLock.RLock()
+	for _, secPolicy := range fd.SecurityPolicies[fd.hostName].Policies {
+		if secPolicy.Action != "Block" {
+			t.Errorf("[FAIL] Changed the action of a host policy for the host (%v)", secPolicy)
+			fd.SecurityPoliciesLock.RUnlock()
+			return
+		}
+	}
+	fd.SecurityPoliciesLock.RUnlock()
+
+	t.Log("[PASS] Audited host deny actions only for a hostPID container")
+
+	// both policies match (the container policy is more specific)
+
+	log = fd.UpdateMatchedPolicy(newLog("ubuntu-1", "/bin/bash"))
+	if log.Type != "MatchedPolicy" || log.PolicyName != "ksp-ubuntu-1-bash-audit" || log.Action != "Audit" || log.Severity != "3" || log.PolicySet != PolicySetContainer {
+		t.Errorf("[FAIL] Failed to give precedence to a container policy (%v)", log)
+		return
+	}
+
+	t.Log("[PASS] Gave precedence to a container policy over a host policy")
+
+	// host policies are not evaluated for normal containers
+
+	if log := fd.UpdateMatchedPolicy(newLog("ubuntu-2", "/usr/bin/diff")); log.PolicyName != "" {
+		t.Errorf("[FAIL] Evaluated a host policy for a normal container (%v)", log)
+		return
+	}
+
+	if log := fd.UpdateMatchedPolicy(newLog("ubuntu-2", "/bin/bash")); log.PolicyName != "ksp-ubuntu-1-bash-audit" || log.PolicySet != "" {
+		t.Errorf("[FAIL] Failed to evaluate a container policy for a normal container (%v)", log)
+		return
+	}
+
+	t.Log("[PASS] Kept the policy sets separate for a normal container")
+
+	// host logs are evaluated only with host policies
+
+	hostLog := tp.Log{HostName: "kubearmor-dev", Operation: "Process", Resource: "/bin/bash", Result: "Permission denied"}
+	if log := fd.UpdateMatchedPolicy(hostLog); log.Type != "MatchedHostPolicy" || log.PolicyName != "hsp-kubearmor-dev-bash-block" || log.PolicySet != "" {
+		t.Errorf("[FAIL] Failed to evaluate a host log with host policies (%v)", log)
+		return
+	}
+
+	t.Log("[PASS] Evaluated a host log with host policies")
+}
@@ -0,0 +1,91 @@
+package feeder
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	kl "github.com/accuknox/KubeArmor/KubeArmor/common"
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+
+	pb "github.com/accuknox/KubeArmor/protobuf"
+)
+
+// ======================= //
+// == Process Tree Dump == //
+// ======================= //
+
+// MaxProcessTreeNodes for each snapshot
+const MaxProcessTreeNodes = 4096
+
+// SetProcessTree Function
+func (fd *Feeder) SetProcessTree(activePidMap *map[string]tp.PidMap, activePidMapLock **sync.RWMutex) {
+	fd.logService.ActivePidMap = activePidMap
+	fd.logService.ActivePidMapLock = activePidMapLock
+}
+
+// GetProcessTreeSnapshot Function
+func GetProcessTreeSnapshot(containerID string, pidMap tp.PidMap, limit int) *pb.ProcessTree {
+	tree := &pb.ProcessTree{ContainerID: containerID}
+
+	if limit <= 0 || limit > MaxProcessTreeNodes {
+		limit = MaxProcessTreeNodes
+	}
+
+	pids := []uint32{}
+	for pid := range pidMap {
+		pids = append(pids, pid)
+	}
+	sort.Slice(pids, func(i, j int) bool { return pids[i] < pids[j] })
+
+	if len(pids) > limit {
+		pids = pids[:limit]
+		tree.Truncated = true
+	}
+
+	for _, pid := range pids {
+		node := pidMap[pid]
+
+		pbNode := &pb.ProcessNode{
+			HostPID: node.HostPID,
+			PPID:    node.PPID,
+			PID:     node.PID,
+			UID:     node.UID,
+
+			Comm:     node.Comm,
+			ExecPath: node.ExecPath,
+
+			Exited: node.Exited,
+		}
+
+		// exited, but not reaped yet
+		if node.Exited {
+			pbNode.ExitedTime = node.ExitedTime.UTC().Format(kl.TimeFormUTC)
+		}
+
+		tree.Nodes = append(tree.Nodes, pbNode)
+	}
+
+	return tree
+}
+
+// GetProcessTree Function
+func (ls *LogService) GetProcessTree(ctx context.Context, req *pb.ProcessTreeRequest) (*pb.ProcessTree, error) {
+	if ls.ActivePidMap == nil || ls.ActivePidMapLock == nil {
+		return nil, errors.New("no process tree")
+	}
+
+	if req.ContainerID == "" {
+		return nil, errors.New("no container id")
+	}
+
+	ActivePidMap := *(ls.ActivePidMap)
+	ActivePidMapLock := *(ls.ActivePidMapLock)
+
+	// build the snapshot under the lock, as nodes are updated in place
+	ActivePidMapLock.RLock()
+	defer ActivePidMapLock.RUnlock()
+
+	return GetProcessTreeSnapshot(req.ContainerID, ActivePidMap[req.ContainerID], int(req.Limit)), nil
+}
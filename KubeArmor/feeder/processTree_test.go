@@ -0,0 +1,98 @@
+package feeder
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+
+	pb "github.com/accuknox/KubeArmor/protobuf"
+)
+
+func TestGetProcessTree(t *testing.T) {
+	ls := &LogService{}
+
+	// no process tree
+
+	if _, err := ls.GetProcessTree(context.Background(), &pb.ProcessTreeRequest{ContainerID: "ubuntu-1-container"}); err == nil {
+		t.Errorf("[FAIL] Got a process tree without the source")
+		return
+	}
+
+	t.Log("[PASS] Rejected a request without the source")
+
+	// build a process tree
+
+	activePidMap := map[string]tp.PidMap{
+		"ubuntu-1-container": {
+			1:  {HostPID: 1001, PPID: 0, PID: 1, Comm: "bash", ExecPath: "/bin/bash"},
+			7:  {HostPID: 1007, PPID: 1, PID: 7, Comm: "sleep", ExecPath: "/bin/sleep"},
+			9:  {HostPID: 1009, PPID: 1, PID: 9, Comm: "wc", ExecPath: "/usr/bin/wc", Exited: true, ExitedTime: time.Now()},
+			12: {HostPID: 1012, PPID: 7, PID: 12, UID: 1000, Comm: "cat", ExecPath: "/bin/cat"},
+		},
+		"ubuntu-2-container": {
+			1: {HostPID: 2001, PPID: 0, PID: 1, Comm: "bash", ExecPath: "/bin/bash"},
+		},
+	}
+	activePidMapLock := new(sync.RWMutex)
+
+	ls.ActivePidMap = &activePidMap
+	ls.ActivePidMapLock = &activePidMapLock
+
+	// get a snapshot
+
+	tree, err := ls.GetProcessTree(context.Background(), &pb.ProcessTreeRequest{ContainerID: "ubuntu-1-container"})
+	if err != nil {
+		t.Errorf("[FAIL] Failed to get a process tree (%s)", err.Error())
+		return
+	}
+
+	if tree.ContainerID != "ubuntu-1-container" || len(tree.Nodes) != 4 || tree.Truncated {
+		t.Errorf("[FAIL] Failed to get the whole process tree (%v)", tree)
+		return
+	}
+
+	expected := []uint32{1, 7, 9, 12}
+	for idx, node := range tree.Nodes {
+		if node.PID != expected[idx] || node.HostPID != 1000+expected[idx] {
+			t.Errorf("[FAIL] Got an unexpected node (%v)", node)
+			return
+		}
+	}
+
+	if tree.Nodes[3].PPID != 7 || tree.Nodes[3].Comm != "cat" || tree.Nodes[3].ExecPath != "/bin/cat" || tree.Nodes[3].UID != 1000 {
+		t.Errorf("[FAIL] Failed to keep the attributes of a node (%v)", tree.Nodes[3])
+		return
+	}
+
+	t.Log("[PASS] Got the process tree of a container")
+
+	if !tree.Nodes[2].Exited || tree.Nodes[2].ExitedTime == "" || tree.Nodes[0].Exited || tree.Nodes[0].ExitedTime != "" {
+		t.Errorf("[FAIL] Failed to keep the exited flags (%v)", tree.Nodes)
+		return
+	}
+
+	t.Log("[PASS] Got an exited-but-not-reaped process")
+
+	// bound the snapshot
+
+	tree, err = ls.GetProcessTree(context.Background(), &pb.ProcessTreeRequest{ContainerID: "ubuntu-1-container", Limit: 2})
+	if err != nil || len(tree.Nodes) != 2 || !tree.Truncated {
+		t.Errorf("[FAIL] Failed to bound the process tree (%v)", tree)
+		return
+	}
+
+	t.Log("[PASS] Bounded the process tree")
+
+	// an unknown container
+
+	tree, err = ls.GetProcessTree(context.Background(), &pb.ProcessTreeRequest{ContainerID: "unknown-container"})
+	if err != nil || len(tree.Nodes) != 0 {
+		t.Errorf("[FAIL] Got a process tree of an unknown container (%v)", tree)
+		return
+	}
+
+	t.Log("[PASS] Got an empty process tree of an unknown container")
+}
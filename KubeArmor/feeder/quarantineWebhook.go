@@ -0,0 +1,200 @@
+package feeder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	kg "github.com/accuknox/KubeArmor/KubeArmor/log"
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+// ======================== //
+// == Quarantine Webhook == //
+// ======================== //
+
+const (
+	// DefaultWebhookQueueSize for the notifications waiting to be sent
+	DefaultWebhookQueueSize = 256
+
+	// DefaultWebhookRetries for each notification
+	DefaultWebhookRetries = 3
+
+	// DefaultWebhookRetryInterval for the first retry (doubled for each retry)
+	DefaultWebhookRetryInterval = time.Second
+
+	// DefaultWebhookCooldown for the same policy, target, and resource
+	DefaultWebhookCooldown = time.Minute
+)
+
+// WebhookNotifier Structure
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+
+	maxRetries    int
+	retryInterval time.Duration
+	cooldown      time.Duration
+
+	// policy + container / host + resource -> the last notification
+	lastSent     map[string]time.Time
+	lastSentLock sync.Mutex
+
+	// the host PID of the daemon (to avoid notifying the events of the webhook requests)
+	selfPID int32
+
+	queue    chan []byte
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewWebhookNotifier Function
+func NewWebhookNotifier(webhookURL string) *WebhookNotifier {
+	wn := &WebhookNotifier{}
+
+	wn.url = webhookURL
+	wn.client = &http.Client{Timeout: time.Second * 5}
+
+	wn.maxRetries = DefaultWebhookRetries
+	wn.retryInterval = DefaultWebhookRetryInterval
+	wn.cooldown = DefaultWebhookCooldown
+
+	wn.lastSent = map[string]time.Time{}
+	wn.lastSentLock = sync.Mutex{}
+
+	wn.selfPID = int32(os.Getpid())
+
+	wn.queue = make(chan []byte, DefaultWebhookQueueSize)
+	wn.stopChan = make(chan struct{})
+
+	wn.wg.Add(1)
+	go wn.sendNotifications()
+
+	return wn
+}
+
+// Notify Function
+func (wn *WebhookNotifier) Notify(log tp.Log, now time.Time) bool {
+	if log.Action != "Quarantine" || (log.Type != "MatchedPolicy" && log.Type != "MatchedHostPolicy") {
+		return false
+	}
+
+	// the events caused by the daemon itself (e.g., the webhook requests)
+	if log.HostPID == wn.selfPID {
+		return false
+	}
+
+	key := getBlockKey(log)
+
+	wn.lastSentLock.Lock()
+	if last, ok := wn.lastSent[key]; ok && now.Sub(last) < wn.cooldown {
+		wn.lastSentLock.Unlock()
+		return false
+	}
+	wn.lastSent[key] = now
+
+	// clean up expired entries
+	for k, last := range wn.lastSent {
+		if now.Sub(last) >= wn.cooldown {
+			delete(wn.lastSent, k)
+		}
+	}
+	wn.lastSentLock.Unlock()
+
+	arr, err := json.Marshal(log)
+	if err != nil {
+		return false
+	}
+
+	// never block the hot path
+	select {
+	case wn.queue <- arr:
+		return true
+	default:
+		kg.Errf("Failed to queue a quarantine notification (%s)", key)
+		return false
+	}
+}
+
+// sendNotifications Function
+func (wn *WebhookNotifier) sendNotifications() {
+	defer wn.wg.Done()
+
+	for {
+		select {
+		case <-wn.stopChan:
+			return
+		case body := <-wn.queue:
+			if err := wn.send(body); err != nil {
+				kg.Errf("Failed to send a quarantine notification (%s)", err.Error())
+			}
+		}
+	}
+}
+
+// send Function
+func (wn *WebhookNotifier) send(body []byte) error {
+	var err error
+
+	interval := wn.retryInterval
+
+	for attempt := 0; attempt <= wn.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-wn.stopChan:
+				return err
+			case <-time.After(interval):
+			}
+			interval = interval * 2
+		}
+
+		var resp *http.Response
+
+		resp, err = wn.client.Post(wn.url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+
+		err = fmt.Errorf("%s returned %d", wn.url, resp.StatusCode)
+	}
+
+	return err
+}
+
+// Stop Function
+func (wn *WebhookNotifier) Stop() {
+	close(wn.stopChan)
+	wn.wg.Wait()
+}
+
+// SetQuarantineWebhook Function
+func (fd *Feeder) SetQuarantineWebhook(webhookURL string) error {
+	if fd.webhookNotifier != nil {
+		fd.webhookNotifier.Stop()
+		fd.webhookNotifier = nil
+	}
+
+	// disabled
+	if webhookURL == "" || webhookURL == "none" {
+		return nil
+	}
+
+	u, err := url.Parse(webhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook (%s)", webhookURL)
+	}
+
+	fd.webhookNotifier = NewWebhookNotifier(webhookURL)
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package feeder
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	kl "github.com/accuknox/KubeArmor/KubeArmor/common"
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+func TestSetQuarantineWebhook(t *testing.T) {
+	fd := &Feeder{}
+
+	for _, invalid := range []string{"localhost:8080", "ftp://localhost/hook", "http://"} {
+		if err := fd.SetQuarantineWebhook(invalid); err == nil {
+			t.Errorf("[FAIL] Set an invalid webhook (%s)", invalid)
+			return
+		}
+	}
+
+	if err := fd.SetQuarantineWebhook("none"); err != nil || fd.webhookNotifier != nil {
+		t.Errorf("[FAIL] Failed to disable the webhook (%v)", err)
+		return
+	}
+
+	t.Log("[PASS] Validated quarantine webhooks")
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	wn := NewWebhookNotifier("http://localhost:0/hook")
+	defer wn.Stop()
+
+	log := tp.Log{HostPID: 1234, ContainerID: "ubuntu-1-container", Type: "MatchedPolicy", PolicyName: "ksp-ubuntu-1-proc-path-quarantine", Operation: "Process", Resource: "/bin/sleep", Action: "Quarantine"}
+	now := time.Now()
+
+	if !wn.Notify(log, now) {
+		t.Error("[FAIL] Failed to notify a Quarantine decision")
+		return
+	}
+
+	// within the cooldown
+
+	if wn.Notify(log, now.Add(time.Second*30)) {
+		t.Error("[FAIL] Notified a Quarantine decision again within the cooldown")
+		return
+	}
+
+	// other resources are notified separately
+
+	other := log
+	other.Resource = "/bin/cat"
+
+	if !wn.Notify(other, now.Add(time.Second*30)) {
+		t.Error("[FAIL] Failed to notify a Quarantine decision for another resource")
+		return
+	}
+
+	// after the cooldown
+
+	if !wn.Notify(log, now.Add(DefaultWebhookCooldown)) {
+		t.Error("[FAIL] Failed to notify a Quarantine decision after the cooldown")
+		return
+	}
+
+	t.Log("[PASS] Notified Quarantine decisions once per cooldown")
+
+	// the events of the daemon itself
+
+	self := log
+	self.HostPID = wn.selfPID
+	self.Resource = "/usr/bin/curl"
+
+	if wn.Notify(self, now) {
+		t.Error("[FAIL] Notified a Quarantine decision caused by the daemon itself")
+		return
+	}
+
+	// other actions
+
+	block := log
+	block.Action = "Block"
+	block.Resource = "/bin/ls"
+
+	if wn.Notify(block, now) {
+		t.Error("[FAIL] Notified a Block decision")
+		return
+	}
+
+	t.Log("[PASS] Skipped the events of the daemon and other actions")
+}
+
+func TestPushLogWithQuarantineWebhook(t *testing.T) {
+	received := make(chan tp.Log, 10)
+
+	var attempts int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// fail the first attempt to check retries
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		log := tp.Log{}
+		if r.Header.Get("Content-Type") != "application/json" || json.NewDecoder(r.Body).Decode(&log) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		received <- log
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "kubearmor-quarantine")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a temporary directory (%s)", err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	logPath := filepath.Join(dir, "kubearmor.log")
+
+	// create Feeder
+	feeder := NewFeeder("32758", logPath, false)
+	if feeder == nil {
+		t.Error("[FAIL] Failed to create Feeder")
+		return
+	}
+	defer feeder.DestroyFeeder()
+
+	if err := feeder.SetQuarantineWebhook(server.URL + "/quarantine"); err != nil {
+		t.Errorf("[FAIL] Failed to set the quarantine webhook (%s)", err.Error())
+		return
+	}
+	feeder.webhookNotifier.retryInterval = time.Millisecond * 10
+
+	feeder.SecurityPolicies["multiubuntu_ubuntu-1"] = tp.MatchPolicies{Policies: []tp.MatchPolicy{
+		{PolicyName: "ksp-ubuntu-1-proc-path-quarantine", Severity: "7", Operation: "Process", Resource: "/bin/sleep", Action: "Quarantine"},
+	}}
+
+	// the operation is denied by the enforcer
+
+	feeder.PushLog(tp.Log{UpdatedTime: kl.GetDateTimeNow(), HostName: "kubearmor-dev", NamespaceName: "multiubuntu", PodName: "ubuntu-1", ContainerID: "ubuntu-1-container", HostPID: 1234, Operation: "Process", Resource: "/bin/sleep", Result: "Permission denied"})
+
+	select {
+	case log := <-received:
+		if log.PolicyName != "ksp-ubuntu-1-proc-path-quarantine" || log.Action != "Quarantine" || log.PodName != "ubuntu-1" || log.Resource != "/bin/sleep" || log.Result != "Permission denied" {
+			t.Errorf("[FAIL] Failed to send the full event to the webhook (%v)", log)
+			return
+		}
+	case <-time.After(time.Second * 5):
+		t.Errorf("[FAIL] Failed to notify the webhook (%d attempts)", atomic.LoadInt32(&attempts))
+		return
+	}
+
+	t.Log("[PASS] Notified the webhook after a retry")
+
+	content, err := ioutil.ReadFile(logPath)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to read logs (%s)", err.Error())
+		return
+	}
+
+	log := tp.Log{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(string(content))), &log); err != nil {
+		t.Errorf("[FAIL] Failed to parse a log (%s)", err.Error())
+		return
+	}
+
+	if log.Action != "Quarantine" || log.Result != "Permission denied" || !log.Notified {
+		t.Errorf("[FAIL] Failed to record the notification in the log (%v)", log)
+		return
+	}
+
+	t.Log("[PASS] Recorded the notification in the log")
+}
@@ -0,0 +1,139 @@
+package feeder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+// ========================== //
+// == Severity Escalations == //
+// ========================== //
+
+// SeverityEscalation Structure
+type SeverityEscalation struct {
+	// pod label (key=value)
+	Label string
+
+	// the number added to the severities of matched policies
+	Delta int
+}
+
+// ParseSeverityEscalations Function
+func ParseSeverityEscalations(rules string) ([]SeverityEscalation, error) {
+	escalations := []SeverityEscalation{}
+
+	if rules == "" || rules == "none" {
+		return escalations, nil
+	}
+
+	// e.g., tier=critical:+2,env=dev:-1
+	for _, rule := range strings.Split(rules, ",") {
+		idx := strings.LastIndex(rule, ":")
+		if idx < 0 {
+			return nil, fmt.Errorf("no severity delta (%s)", rule)
+		}
+
+		label := strings.TrimSpace(rule[:idx])
+		if kv := strings.SplitN(label, "=", 2); len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid label (%s)", rule)
+		}
+
+		delta, err := strconv.Atoi(strings.TrimSpace(rule[idx+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid severity delta (%s)", rule)
+		}
+
+		escalations = append(escalations, SeverityEscalation{Label: label, Delta: delta})
+	}
+
+	return escalations, nil
+}
+
+// SetSeverityEscalations Function
+func (fd *Feeder) SetSeverityEscalations(rules string) error {
+	escalations, err := ParseSeverityEscalations(rules)
+	if err != nil {
+		return err
+	}
+
+	fd.EscalationsLock.Lock()
+	fd.SeverityEscalations = escalations
+	fd.EscalationsLock.Unlock()
+
+	return nil
+}
+
+// UpdateSeverityEscalation Function
+func (fd *Feeder) UpdateSeverityEscalation(action string, conGroup tp.ContainerGroup) {
+	key := conGroup.NamespaceName + "_" + conGroup.ContainerGroupName
+
+	fd.EscalationsLock.Lock()
+	defer fd.EscalationsLock.Unlock()
+
+	delta := 0
+
+	if action != "DELETED" {
+		for _, escalation := range fd.SeverityEscalations {
+			for _, label := range conGroup.Labels {
+				if label == escalation.Label {
+					delta = delta + escalation.Delta
+				}
+			}
+		}
+	}
+
+	if delta != 0 {
+		fd.Escalations[key] = delta
+	} else {
+		delete(fd.Escalations, key)
+	}
+}
+
+// escalateSeverity Function
+func escalateSeverity(severity string, delta int) string {
+	severities := []string{}
+
+	// the severities of multiple policies are joined with commas
+	for _, sev := range strings.Split(severity, ",") {
+		val, err := strconv.Atoi(sev)
+		if err != nil {
+			severities = append(severities, sev)
+			continue
+		}
+
+		val = val + delta
+
+		if val < 1 {
+			val = 1
+		} else if val > 10 {
+			val = 10
+		}
+
+		severities = append(severities, strconv.Itoa(val))
+	}
+
+	return strings.Join(severities, ",")
+}
+
+// EscalateSeverity Function
+func (fd *Feeder) EscalateSeverity(log tp.Log) tp.Log {
+	if log.Type != "MatchedPolicy" || log.Severity == "" {
+		return log
+	}
+
+	fd.EscalationsLock.RLock()
+	delta, ok := fd.Escalations[log.NamespaceName+"_"+log.PodName]
+	fd.EscalationsLock.RUnlock()
+
+	if !ok {
+		return log
+	}
+
+	log.OriginalSeverity = log.Severity
+	log.Severity = escalateSeverity(log.Severity, delta)
+
+	return log
+}
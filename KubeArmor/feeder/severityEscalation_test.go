@@ -0,0 +1,106 @@
+package feeder
+
+import (
+	"sync"
+	"testing"
+
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+func TestParseSeverityEscalations(t *testing.T) {
+	escalations, err := ParseSeverityEscalations("tier=critical:+2, env=dev:-1")
+	if err != nil || len(escalations) != 2 || escalations[0] != (SeverityEscalation{Label: "tier=critical", Delta: 2}) || escalations[1] != (SeverityEscalation{Label: "env=dev", Delta: -1}) {
+		t.Errorf("[FAIL] Failed to parse severity escalations (%v, %v)", escalations, err)
+		return
+	}
+
+	t.Log("[PASS] Parsed severity escalations")
+
+	for _, rules := range []string{"tier=critical", "tier:+2", "tier=critical:high"} {
+		if _, err := ParseSeverityEscalations(rules); err == nil {
+			t.Errorf("[FAIL] Accepted invalid severity escalations (%s)", rules)
+			return
+		}
+	}
+
+	t.Log("[PASS] Rejected invalid severity escalations")
+}
+
+func TestEscalateSeverity(t *testing.T) {
+	fd := &Feeder{}
+	fd.SecurityPolicies = map[string]tp.MatchPolicies{}
+	fd.SecurityPoliciesLock = new(sync.RWMutex)
+	fd.Escalations = map[string]int{}
+	fd.EscalationsLock = new(sync.RWMutex)
+
+	if err := fd.SetSeverityEscalations("tier=critical:+2"); err != nil {
+		t.Errorf("[FAIL] Failed to set severity escalations (%s)", err.Error())
+		return
+	}
+
+	// the same policy for a critical pod and an unlabeled pod
+
+	secPolicy := tp.SecurityPolicy{Metadata: map[string]string{"policyName": "ksp-proc-path-block"}}
+	secPolicy.Spec.Severity = 5
+	secPolicy.Spec.Process.MatchPaths = []tp.ProcessPathType{{Path: "/bin/bash"}}
+	secPolicy.Spec.Action = "Block"
+
+	critical := tp.ContainerGroup{NamespaceName: "multiubuntu", ContainerGroupName: "ubuntu-1", Labels: []string{"container=ubuntu-1", "tier=critical"}, SecurityPolicies: []tp.SecurityPolicy{secPolicy}}
+	unlabeled := tp.ContainerGroup{NamespaceName: "multiubuntu", ContainerGroupName: "ubuntu-2", Labels: []string{"container=ubuntu-2"}, SecurityPolicies: []tp.SecurityPolicy{secPolicy}}
+
+	fd.UpdateSecurityPolicies("ADDED", critical)
+	fd.UpdateSecurityPolicies("ADDED", unlabeled)
+
+	matchLog := func(podName string) tp.Log {
+		log := tp.Log{ContainerID: podName + "-container", NamespaceName: "multiubuntu", PodName: podName, Operation: "Process", Resource: "/bin/bash", Result: "Permission denied"}
+		return fd.EscalateSeverity(fd.UpdateMatchedPolicy(log))
+	}
+
+	// a critical pod
+
+	if log := matchLog("ubuntu-1"); log.Severity != "7" || log.OriginalSeverity != "5" {
+		t.Errorf("[FAIL] Failed to escalate the severity for a critical pod (%s, %s)", log.Severity, log.OriginalSeverity)
+		return
+	}
+
+	t.Log("[PASS] Escalated the severity for a critical pod")
+
+	// an unlabeled pod
+
+	if log := matchLog("ubuntu-2"); log.Severity != "5" || log.OriginalSeverity != "" {
+		t.Errorf("[FAIL] Escalated the severity for an unlabeled pod (%s, %s)", log.Severity, log.OriginalSeverity)
+		return
+	}
+
+	t.Log("[PASS] Kept the severity for an unlabeled pod")
+
+	// clamp to the valid range
+
+	secPolicy.Spec.Severity = 9
+	critical.SecurityPolicies = []tp.SecurityPolicy{secPolicy}
+	fd.UpdateSecurityPolicies("MODIFIED", critical)
+
+	if log := matchLog("ubuntu-1"); log.Severity != "10" || log.OriginalSeverity != "9" {
+		t.Errorf("[FAIL] Failed to clamp the escalated severity (%s, %s)", log.Severity, log.OriginalSeverity)
+		return
+	}
+
+	if severity := escalateSeverity("2,5", -3); severity != "1,2" {
+		t.Errorf("[FAIL] Failed to clamp the escalated severities (%s)", severity)
+		return
+	}
+
+	t.Log("[PASS] Clamped escalated severities")
+
+	// the label is removed
+
+	critical.Labels = []string{"container=ubuntu-1"}
+	fd.UpdateSecurityPolicies("MODIFIED", critical)
+
+	if log := matchLog("ubuntu-1"); log.Severity != "9" || log.OriginalSeverity != "" {
+		t.Errorf("[FAIL] Escalated the severity after removing the label (%s, %s)", log.Severity, log.OriginalSeverity)
+		return
+	}
+
+	t.Log("[PASS] Stopped escalating the severity after removing the label")
+}
@@ -0,0 +1,281 @@
+package feeder
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// ====================== //
+// == Unix Socket Sink == //
+// ====================== //
+
+// UnixSocketSinkPrefix for the output mode writing logs to a unix socket
+const UnixSocketSinkPrefix = "unix://"
+
+// unixSocketClient Structure
+type unixSocketClient struct {
+	conn  net.Conn
+	queue chan string
+}
+
+// UnixSocketSink Structure
+type UnixSocketSink struct {
+	// socket path
+	path string
+
+	// socket permissions
+	mode os.FileMode
+
+	// the maximum number of logs queued for each client
+	queueSize int
+
+	// unix socket listener
+	listener net.Listener
+
+	// connected clients
+	clients     map[*unixSocketClient]struct{}
+	clientsLock sync.Mutex
+
+	// the number of logs dropped due to full client queues
+	dropCount uint64
+
+	// the number of dropped events by reason (shared with the feeder)
+	dropStats *DropStats
+
+	// closed or not
+	closed bool
+
+	// wait group for the accept and client routines
+	wg sync.WaitGroup
+}
+
+// NewUnixSocketSink Function
+//
+// output: unix:///var/run/kubearmor/logs.sock?mode=0660&queueSize=1024
+func NewUnixSocketSink(output string) (*UnixSocketSink, error) {
+	u, err := url.Parse(output)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Path == "" {
+		return nil, errors.New("no socket path")
+	}
+
+	us := &UnixSocketSink{}
+
+	us.path = u.Path
+	us.mode = 0660
+	us.queueSize = 1024
+
+	us.clients = map[*unixSocketClient]struct{}{}
+
+	query := u.Query()
+
+	if val := query.Get("mode"); val != "" {
+		mode, err := strconv.ParseUint(val, 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mode (%s)", val)
+		}
+		us.mode = os.FileMode(mode)
+	}
+
+	if val := query.Get("queueSize"); val != "" {
+		queueSize, err := strconv.Atoi(val)
+		if err != nil || queueSize < 1 {
+			return nil, fmt.Errorf("invalid queueSize (%s)", val)
+		}
+		us.queueSize = queueSize
+	}
+
+	if err := os.MkdirAll(filepath.Dir(us.path), 0755); err != nil {
+		return nil, err
+	}
+
+	// remove a stale socket left by a previous run
+	if info, err := os.Lstat(us.path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", us.path)
+		}
+		if err := os.Remove(us.path); err != nil {
+			return nil, err
+		}
+	}
+
+	listener, err := listenUnixSocket(us.path, us.mode)
+	if err != nil {
+		return nil, err
+	}
+
+	us.listener = listener
+
+	us.wg.Add(1)
+	go us.acceptClients()
+
+	return us, nil
+}
+
+// listenUnixSocket Function
+func listenUnixSocket(path string, mode os.FileMode) (net.Listener, error) {
+	// create the socket in a private (0700) directory so that no one can connect before it gets the given mode
+	tmpDir, err := ioutil.TempDir(filepath.Dir(path), ".kubearmor-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tmpPath := filepath.Join(tmpDir, filepath.Base(path))
+
+	listener, err := net.Listen("unix", tmpPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// the socket is removed by Close() at the final path
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	return listener, nil
+}
+
+// GetPath Function
+func (us *UnixSocketSink) GetPath() string {
+	return us.path
+}
+
+// GetClientCount Function
+func (us *UnixSocketSink) GetClientCount() int {
+	us.clientsLock.Lock()
+	defer us.clientsLock.Unlock()
+
+	return len(us.clients)
+}
+
+// GetDropCount Function
+func (us *UnixSocketSink) GetDropCount() uint64 {
+	us.clientsLock.Lock()
+	defer us.clientsLock.Unlock()
+
+	return us.dropCount
+}
+
+// acceptClients Function
+func (us *UnixSocketSink) acceptClients() {
+	defer us.wg.Done()
+
+	for {
+		conn, err := us.listener.Accept()
+		if err != nil {
+			return // closed
+		}
+
+		client := &unixSocketClient{conn: conn, queue: make(chan string, us.queueSize)}
+
+		us.clientsLock.Lock()
+		if us.closed {
+			us.clientsLock.Unlock()
+			conn.Close()
+			return
+		}
+		us.clients[client] = struct{}{}
+		us.clientsLock.Unlock()
+
+		us.wg.Add(2)
+		go us.sendLogs(client)
+		go us.watchClient(client)
+	}
+}
+
+// removeClient Function
+func (us *UnixSocketSink) removeClient(client *unixSocketClient) {
+	us.clientsLock.Lock()
+	defer us.clientsLock.Unlock()
+
+	if _, ok := us.clients[client]; ok {
+		delete(us.clients, client)
+		close(client.queue)
+		client.conn.Close()
+	}
+}
+
+// sendLogs Function
+func (us *UnixSocketSink) sendLogs(client *unixSocketClient) {
+	defer us.wg.Done()
+
+	for str := range client.queue {
+		if _, err := client.conn.Write([]byte(str + "\n")); err != nil {
+			us.removeClient(client)
+			return
+		}
+	}
+}
+
+// watchClient Function
+func (us *UnixSocketSink) watchClient(client *unixSocketClient) {
+	defer us.wg.Done()
+
+	// clients are not expected to send anything, so a read only returns on disconnect
+	buf := make([]byte, 1)
+	for {
+		if _, err := client.conn.Read(buf); err != nil {
+			us.removeClient(client)
+			return
+		}
+	}
+}
+
+// Write Function
+func (us *UnixSocketSink) Write(str string) {
+	us.clientsLock.Lock()
+	defer us.clientsLock.Unlock()
+
+	for client := range us.clients {
+		select {
+		case client.queue <- str:
+		default:
+			// never block the feeder for a slow client
+			us.dropCount++
+			us.dropStats.Add(DropReasonSocketQueue, 1)
+		}
+	}
+}
+
+// Close Function
+func (us *UnixSocketSink) Close() {
+	us.clientsLock.Lock()
+	us.closed = true
+	us.clientsLock.Unlock()
+
+	us.listener.Close()
+
+	us.clientsLock.Lock()
+	clients := []*unixSocketClient{}
+	for client := range us.clients {
+		clients = append(clients, client)
+	}
+	us.clientsLock.Unlock()
+
+	for _, client := range clients {
+		us.removeClient(client)
+	}
+
+	us.wg.Wait()
+
+	os.Remove(us.path)
+}
@@ -0,0 +1,118 @@
+package feeder
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+func TestUnixSocketSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubearmor-sock")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a temporary directory (%s)", err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	sockPath := filepath.Join(dir, "run", "logs.sock")
+
+	// create a unix socket sink
+
+	us, err := NewUnixSocketSink("unix://" + sockPath + "?mode=0600&queueSize=16")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a unix socket sink (%s)", err.Error())
+		return
+	}
+
+	info, err := os.Stat(sockPath)
+	if err != nil || info.Mode()&os.ModeSocket == 0 || info.Mode().Perm() != 0600 {
+		t.Errorf("[FAIL] Failed to create a socket file with the given permissions")
+		us.Close()
+		return
+	}
+
+	t.Log("[PASS] Created a unix socket sink")
+
+	// connect a client
+
+	conn, err := net.Dial("unix", sockPath)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to connect to %s (%s)", sockPath, err.Error())
+		us.Close()
+		return
+	}
+
+	for i := 0; i < 100 && us.GetClientCount() != 1; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if us.GetClientCount() != 1 {
+		t.Errorf("[FAIL] Failed to accept a client")
+		us.Close()
+		return
+	}
+
+	t.Log("[PASS] Connected a client")
+
+	// write logs
+
+	for _, name := range []string{"multiubuntu", "default"} {
+		arr, _ := json.Marshal(tp.Log{NamespaceName: name, Operation: "Process", Resource: "/bin/sleep"})
+		us.Write(string(arr))
+	}
+
+	conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	reader := bufio.NewReader(conn)
+
+	for _, name := range []string{"multiubuntu", "default"} {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			t.Errorf("[FAIL] Failed to receive a log (%s)", err.Error())
+			us.Close()
+			return
+		}
+
+		log := tp.Log{}
+		if err := json.Unmarshal(line, &log); err != nil || log.NamespaceName != name {
+			t.Errorf("[FAIL] Received an unexpected log (%s)", string(line))
+			us.Close()
+			return
+		}
+	}
+
+	t.Log("[PASS] Received logs over the unix socket")
+
+	// disconnect the client
+
+	conn.Close()
+
+	for i := 0; i < 100 && us.GetClientCount() != 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if us.GetClientCount() != 0 {
+		t.Errorf("[FAIL] Failed to remove a disconnected client")
+		us.Close()
+		return
+	}
+
+	t.Log("[PASS] Removed a disconnected client")
+
+	// close the sink
+
+	us.Close()
+
+	if _, err := os.Stat(sockPath); !os.IsNotExist(err) {
+		t.Errorf("[FAIL] Failed to remove the socket file")
+		return
+	}
+
+	t.Log("[PASS] Closed the unix socket sink")
+}
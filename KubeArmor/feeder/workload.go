@@ -0,0 +1,31 @@
+package feeder
+
+import (
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+// =============== //
+// == Workloads == //
+// =============== //
+
+// UpdateWorkload Function
+func (fd *Feeder) UpdateWorkload(action string, conGroup tp.ContainerGroup) {
+	key := conGroup.NamespaceName + "_" + conGroup.ContainerGroupName
+
+	fd.WorkloadsLock.Lock()
+	defer fd.WorkloadsLock.Unlock()
+
+	if action == "DELETED" || conGroup.WorkloadKind == "" { // bare pod
+		delete(fd.Workloads, key)
+	} else { // ADDED | MODIFIED
+		fd.Workloads[key] = conGroup.WorkloadKind + "/" + conGroup.WorkloadName
+	}
+}
+
+// GetWorkload Function
+func (fd *Feeder) GetWorkload(namespaceName, podName string) string {
+	fd.WorkloadsLock.RLock()
+	defer fd.WorkloadsLock.RUnlock()
+
+	return fd.Workloads[namespaceName+"_"+podName]
+}
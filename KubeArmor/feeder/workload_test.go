@@ -0,0 +1,82 @@
+package feeder
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	kl "github.com/accuknox/KubeArmor/KubeArmor/common"
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+func TestUpdateWorkload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubearmor-workload")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a temporary directory (%s)", err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	logPath := filepath.Join(dir, "kubearmor.log")
+
+	// create Feeder
+	feeder := NewFeeder("32764", logPath, true)
+	if feeder == nil {
+		t.Error("[FAIL] Failed to create Feeder")
+		return
+	}
+	defer feeder.DestroyFeeder()
+
+	// a deployment-owned pod and a bare pod
+
+	feeder.UpdateWorkload("ADDED", tp.ContainerGroup{NamespaceName: "multiubuntu", ContainerGroupName: "ubuntu-1-6d5c8f5b9-x2xzq", WorkloadKind: "Deployment", WorkloadName: "ubuntu-1"})
+	feeder.UpdateWorkload("ADDED", tp.ContainerGroup{NamespaceName: "multiubuntu", ContainerGroupName: "ubuntu-bare"})
+
+	for _, podName := range []string{"ubuntu-1-6d5c8f5b9-x2xzq", "ubuntu-bare"} {
+		feeder.PushLog(tp.Log{UpdatedTime: kl.GetDateTimeNow(), HostName: "kubearmor-dev", NamespaceName: "multiubuntu", PodName: podName, ContainerID: podName + "-container", Operation: "Process", Resource: "/bin/sleep", Result: "Passed"})
+	}
+
+	content, err := ioutil.ReadFile(logPath)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to read logs (%s)", err.Error())
+		return
+	}
+
+	workloads := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		log := tp.Log{}
+		if err := json.Unmarshal([]byte(line), &log); err != nil {
+			t.Errorf("[FAIL] Failed to parse a log (%s)", err.Error())
+			return
+		}
+		workloads[log.PodName] = log.Workload
+	}
+
+	if workloads["ubuntu-1-6d5c8f5b9-x2xzq"] != "Deployment/ubuntu-1" {
+		t.Errorf("[FAIL] Failed to enrich a log with the owning deployment (%v)", workloads)
+		return
+	}
+
+	t.Log("[PASS] Enriched a log with the owning deployment")
+
+	if workload, ok := workloads["ubuntu-bare"]; !ok || workload != "" {
+		t.Errorf("[FAIL] Enriched a log of a bare pod (%v)", workloads)
+		return
+	}
+
+	t.Log("[PASS] Skipped the workload of a bare pod")
+
+	// delete the pod
+
+	feeder.UpdateWorkload("DELETED", tp.ContainerGroup{NamespaceName: "multiubuntu", ContainerGroupName: "ubuntu-1-6d5c8f5b9-x2xzq"})
+
+	if workload := feeder.GetWorkload("multiubuntu", "ubuntu-1-6d5c8f5b9-x2xzq"); workload != "" {
+		t.Errorf("[FAIL] Failed to remove the workload of a deleted pod (%s)", workload)
+		return
+	}
+
+	t.Log("[PASS] Removed the workload of a deleted pod")
+}
@@ -21,6 +21,7 @@ require (
 	github.com/Microsoft/go-winio v0.4.16 // indirect
 	github.com/accuknox/KubeArmor/KubeArmor/audit v0.0.0-00010101000000-000000000000 // indirect
 	github.com/accuknox/KubeArmor/KubeArmor/core v0.0.0-00010101000000-000000000000
+	github.com/accuknox/KubeArmor/KubeArmor/feeder v0.0.0-00010101000000-000000000000
 	github.com/accuknox/KubeArmor/KubeArmor/log v0.0.0-00010101000000-000000000000
 	github.com/containerd/containerd v1.4.3
 	github.com/containerd/ttrpc v1.0.2 // indirect
@@ -10,6 +10,7 @@ import (
 	_ "net/http/pprof"
 
 	"github.com/accuknox/KubeArmor/KubeArmor/core"
+	fd "github.com/accuknox/KubeArmor/KubeArmor/feeder"
 	kg "github.com/accuknox/KubeArmor/KubeArmor/log"
 )
 
@@ -36,10 +37,30 @@ func main() {
 
 	// options
 	gRPCPtr := flag.String("gRPC", "32767", "gRPC port number")
-	logPathPtr := flag.String("logPath", "none", "log file path")
+	logPathPtr := flag.String("logPath", "none", "log file path, {path|stdout|namespace:///dir|unix:///socket|none}")
+	metricsPtr := flag.String("metrics", "none", "metrics port number (the gRPC port to share it), {port|none}")
+	tlsCertPtr := flag.String("tlsCert", "none", "TLS certificate path for gRPC and metrics")
+	tlsKeyPtr := flag.String("tlsKey", "none", "TLS key path for gRPC and metrics")
+	interpretersPtr := flag.String("interpreters", "sh,bash,dash,ash,zsh,ksh,python,perl,ruby,node,php", "interpreters to resolve scripts and inline commands for, {names|none}")
+	processLimitsPtr := flag.String("processLimits", "none", "the default limits of process trees in containers (overridden by the kubearmor-process-limits annotation), {maxDescendants=N,maxDepth=N,window=seconds|none}")
+	severityEscalationsPtr := flag.String("severityEscalations", "none", "severity deltas for the matched policies of pods with given labels, {key=value:delta,...|none}")
+	quarantineWebhookPtr := flag.String("quarantineWebhook", "none", "the webhook notified of the matches of Quarantine policies, {http(s)://host:port/path|none}")
+	policyDirPtr := flag.String("policyDir", "none", "the directory of policy files (YAML/JSON) loaded and hot-reloaded in standalone mode, {path|none}")
+	maxUnackedLogsPtr := flag.Int("maxUnackedLogs", 10000, "the maximum number of unacked logs kept for each acknowledging consumer (and of the logs queued while no client takes them)")
+	blockSummaryIntervalPtr := flag.Int("blockSummaryInterval", fd.DefaultBlockSummaryInterval, "the interval in seconds to summarize repeated identical Block, BlockWithAudit, and Quarantine decisions, {seconds|0 to disable}")
+	backfillSizePtr := flag.Int("backfillSize", fd.DefaultBackfillSize, "the maximum size in bytes of the log file tail kept across restarts and replayed to WatchLogs clients requesting backfill, {bytes|0 to disable}")
+	backfillAgePtr := flag.Int("backfillAge", fd.DefaultBackfillAge, "the maximum age in seconds of the logs replayed to WatchLogs clients requesting backfill")
+	maxDecisionEntriesPtr := flag.Int("maxDecisionEntries", fd.DefaultMaxDecisionEntries, "the maximum number of container/host + resource decisions tracked for the decision change stream, {number (e.g., 16384)|0 to disable}")
+	dropLogIntervalPtr := flag.Int("dropLogInterval", 0, "the interval in seconds to log the number of dropped events by reason, {seconds|0 to disable}")
+	processTreeTTLPtr := flag.Int("processTreeTTL", 10, "the time in seconds to keep exited processes in process trees for enrichment")
+	maxProcessTreeEntriesPtr := flag.Int("maxProcessTreeEntries", 65536, "the maximum number of processes in process trees before the oldest exited ones are evicted, {number|0 for no limit}")
 	enableAuditdPtr := flag.Bool("enableAuditd", false, "enabling Auditd")
 	enableHostPolicyPtr := flag.Bool("enableHostPolicy", false, "enabling host policies")
 	enableSystemLogPtr := flag.Bool("enableSystemLog", false, "enabling system logs")
+	enableWorkloadEnrichmentPtr := flag.Bool("enableWorkloadEnrichment", true, "enabling the owning workloads (Deployment, DaemonSet, ...) of pods in logs")
+	enableSharedPidNsPtr := flag.Bool("enableSharedPidNs", true, "enabling the per-process attribution of containers sharing a PID namespace")
+	enableLogStreamPtr := flag.Bool("enableLogStream", false, "enabling the NDJSON log stream at /logs of the metrics port (e.g., curl -N 'http://localhost:port/logs?type=policy&severity>=5')")
+	enableDecisionPointPtr := flag.Bool("enableDecisionPoint", false, "enabling the decision point (Kernel or Userspace) and the policy matching latency in matched policy logs")
 
 	// profile option
 	pprofPtr := flag.String("pprof", "none", "pprof port number")
@@ -54,7 +75,7 @@ func main() {
 
 	// == //
 
-	core.KubeArmor(*gRPCPtr, *logPathPtr, *enableAuditdPtr, *enableHostPolicyPtr, *enableSystemLogPtr)
+	core.KubeArmor(*gRPCPtr, *logPathPtr, *metricsPtr, *tlsCertPtr, *tlsKeyPtr, *interpretersPtr, *processLimitsPtr, *severityEscalationsPtr, *quarantineWebhookPtr, *policyDirPtr, *maxUnackedLogsPtr, *blockSummaryIntervalPtr, *backfillSizePtr, *backfillAgePtr, *maxDecisionEntriesPtr, *dropLogIntervalPtr, *processTreeTTLPtr, *maxProcessTreeEntriesPtr, *enableAuditdPtr, *enableHostPolicyPtr, *enableSystemLogPtr, *enableWorkloadEnrichmentPtr, *enableSharedPidNsPtr, *enableDecisionPointPtr, *enableLogStreamPtr)
 
 	// == //
 }
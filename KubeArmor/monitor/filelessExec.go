@@ -0,0 +1,56 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+// ======================== //
+// == Fileless Execution == //
+// ======================== //
+
+// FilelessSeverity for the logs of fileless executions
+const FilelessSeverity = "10"
+
+// GetFilelessExecPath Function
+func GetFilelessExecPath(hostPid uint32) (string, bool) {
+	// e.g., /memfd:payload (deleted)
+	exePath, err := os.Readlink(fmt.Sprintf("%s/%d/exe", procDir, hostPid))
+	if err != nil {
+		return "", false
+	}
+
+	if strings.HasPrefix(exePath, "/memfd:") || strings.HasSuffix(exePath, " (deleted)") {
+		return exePath, true
+	}
+
+	return "", false
+}
+
+// BuildFilelessLog Function
+func BuildFilelessLog(log tp.Log, hostPid uint32) (tp.Log, bool) {
+	exePath, fileless := GetFilelessExecPath(hostPid)
+	if !fileless {
+		return tp.Log{}, false
+	}
+
+	if log.ContainerID != "" {
+		log.Type = "MatchedPolicy"
+	} else {
+		log.Type = "MatchedHostPolicy"
+	}
+
+	log.Severity = FilelessSeverity
+	log.Tags = "fileless"
+	log.Message = "Fileless execution"
+
+	log.Operation = "Process"
+	log.Data = "exe=" + exePath
+
+	log.Action = "Audit"
+
+	return log, true
+}
@@ -0,0 +1,78 @@
+package monitor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+func TestBuildFilelessLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubearmor-proc")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a temporary directory (%s)", err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	procDir = dir
+	defer func() { procDir = "/proc" }()
+
+	// simulate the executables of processes
+
+	for pid, exePath := range map[string]string{"201": "/memfd:payload (deleted)", "202": "/usr/bin/wc", "203": "/tmp/dropper (deleted)"} {
+		if err := os.MkdirAll(filepath.Join(dir, pid), 0755); err != nil {
+			t.Errorf("[FAIL] Failed to create a process directory (%s)", err.Error())
+			return
+		}
+
+		if err := os.Symlink(exePath, filepath.Join(dir, pid, "exe")); err != nil {
+			t.Errorf("[FAIL] Failed to create an exe link (%s)", err.Error())
+			return
+		}
+	}
+
+	// a memfd exec
+
+	log := tp.Log{ContainerID: "ubuntu-1-container", HostPID: 201, Operation: "Process", Resource: "", Data: "fd=3 flag=AT_EMPTY_PATH", Result: "Passed"}
+
+	alert, ok := BuildFilelessLog(log, 201)
+	if !ok {
+		t.Errorf("[FAIL] Failed to detect a memfd exec")
+		return
+	}
+
+	if alert.Type != "MatchedPolicy" || alert.Operation != "Process" || alert.Tags != "fileless" || alert.Severity != FilelessSeverity || alert.Data != "exe=/memfd:payload (deleted)" {
+		t.Errorf("[FAIL] Failed to build the log of a memfd exec (%v)", alert)
+		return
+	}
+
+	t.Log("[PASS] Flagged a memfd exec")
+
+	// a deleted executable on the host
+
+	if alert, ok := BuildFilelessLog(tp.Log{HostPID: 203, Operation: "Process", Resource: "/tmp/dropper", Result: "Passed"}, 203); !ok || alert.Type != "MatchedHostPolicy" {
+		t.Errorf("[FAIL] Failed to detect a deleted executable (%v)", alert)
+		return
+	}
+
+	t.Log("[PASS] Flagged a deleted executable")
+
+	// a normal exec
+
+	if alert, ok := BuildFilelessLog(tp.Log{ContainerID: "ubuntu-1-container", HostPID: 202, Operation: "Process", Resource: "/usr/bin/wc", Result: "Passed"}, 202); ok {
+		t.Errorf("[FAIL] Flagged a normal exec (%v)", alert)
+		return
+	}
+
+	// a process that is gone
+
+	if alert, ok := BuildFilelessLog(tp.Log{ContainerID: "ubuntu-1-container", HostPID: 204, Operation: "Process", Resource: "/bin/sleep", Result: "Passed"}, 204); ok {
+		t.Errorf("[FAIL] Flagged an exited process (%v)", alert)
+		return
+	}
+
+	t.Log("[PASS] Did not flag normal execs")
+}
@@ -33,8 +33,10 @@ func (mon *SystemMonitor) BuildHostLogBase(msg ContextCombined) tp.Log {
 
 	if msg.ContextSys.EventID == SYS_EXECVE || msg.ContextSys.EventID == SYS_EXECVEAT {
 		log.Source = mon.GetHostExecPath(msg.ContextSys.PPID)
+		log.InterpretedCommand = mon.GetHostInterpretedCommand(msg.ContextSys.PPID)
 	} else {
 		log.Source = mon.GetHostExecPath(msg.ContextSys.PID)
+		log.InterpretedCommand = mon.GetHostInterpretedCommand(msg.ContextSys.PID)
 	}
 
 	if log.Source == "" {
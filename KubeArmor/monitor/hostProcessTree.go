@@ -1,8 +1,6 @@
 package monitor
 
 import (
-	"time"
-
 	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
 )
 
@@ -44,6 +42,23 @@ func (mon *SystemMonitor) GetHostExecPath(hostPid uint32) string {
 	return ""
 }
 
+// GetHostInterpretedCommand Function
+func (mon *SystemMonitor) GetHostInterpretedCommand(hostPid uint32) string {
+	ActiveHostMap := *(mon.ActiveHostMap)
+	ActiveHostMapLock := *(mon.ActiveHostMapLock)
+
+	ActiveHostMapLock.RLock()
+	defer ActiveHostMapLock.RUnlock()
+
+	if pidMap, ok := ActiveHostMap[hostPid]; ok {
+		if node, ok := pidMap[hostPid]; ok {
+			return node.InterpretedCommand
+		}
+	}
+
+	return ""
+}
+
 // DeleteActiveHostPid Function
 func (mon *SystemMonitor) DeleteActiveHostPid(hostPid uint32) {
 	ActiveHostMap := *(mon.ActiveHostMap)
@@ -56,7 +71,8 @@ func (mon *SystemMonitor) DeleteActiveHostPid(hostPid uint32) {
 	if pidMap, ok := ActiveHostMap[hostPid]; ok {
 		if node, ok := pidMap[hostPid]; ok {
 			node.Exited = true
-			node.ExitedTime = time.Now()
+			node.ExitedTime = mon.now()
+			pidMap[hostPid] = node
 		}
 	}
 }
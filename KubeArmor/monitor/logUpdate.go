@@ -38,6 +38,7 @@ func (mon *SystemMonitor) BuildLogBase(msg ContextCombined) tp.Log {
 
 	log.ContainerID = msg.ContainerID
 	log.NamespaceName, log.PodName, log.ContainerName = mon.GetNameFromContainerID(log.ContainerID)
+	log.Ambiguous = msg.Ambiguous
 
 	log.HostPID = int32(msg.ContextSys.HostPID)
 	log.PPID = int32(msg.ContextSys.PPID)
@@ -46,8 +47,10 @@ func (mon *SystemMonitor) BuildLogBase(msg ContextCombined) tp.Log {
 
 	if msg.ContextSys.EventID == SYS_EXECVE || msg.ContextSys.EventID == SYS_EXECVEAT {
 		log.Source = mon.GetExecPath(msg.ContainerID, msg.ContextSys.PPID)
+		log.InterpretedCommand = mon.GetInterpretedCommand(msg.ContainerID, msg.ContextSys.PPID)
 	} else {
 		log.Source = mon.GetExecPath(msg.ContainerID, msg.ContextSys.PID)
+		log.InterpretedCommand = mon.GetInterpretedCommand(msg.ContainerID, msg.ContextSys.PID)
 	}
 
 	if log.Source == "" {
@@ -232,6 +235,23 @@ func (mon *SystemMonitor) UpdateLogs() {
 				log.Resource = "syscall=" + getSyscallName(int32(msg.ContextSys.EventID))
 				log.Data = "fd=" + fd
 
+			case SYSCALL_VIOLATION: // syscall number
+				if len(msg.ContextArgs) != 1 {
+					continue
+				}
+
+				number, ok := msg.ContextArgs[0].(int32)
+				if !ok {
+					continue
+				}
+
+				// the allow-list may be updated after the event
+				if mon.IsSyscallAllowed(msg.ContainerID, number) {
+					continue
+				}
+
+				log = mon.BuildSyscallViolationLog(log, number)
+
 			default:
 				continue
 			}
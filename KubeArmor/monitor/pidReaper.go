@@ -0,0 +1,149 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+// ================ //
+// == PID Reaper == //
+// ================ //
+
+const (
+	// DefaultPidTTL in seconds to keep exited processes for enrichment
+	DefaultPidTTL = 10
+
+	// DefaultMaxPidEntries for each of the process maps
+	DefaultMaxPidEntries = 65536
+)
+
+// exitedPid Structure
+type exitedPid struct {
+	pidMap     tp.PidMap
+	pid        uint32
+	exitedTime time.Time
+}
+
+// now Function
+func (mon *SystemMonitor) now() time.Time {
+	if mon.Now == nil {
+		return time.Now()
+	}
+
+	return mon.Now()
+}
+
+// SetPidReaper Function
+func (mon *SystemMonitor) SetPidReaper(ttl, maxEntries int) error {
+	if ttl <= 0 {
+		return fmt.Errorf("invalid TTL (%d)", ttl)
+	}
+
+	if maxEntries < 0 {
+		return fmt.Errorf("invalid maximum number of entries (%d)", maxEntries)
+	}
+
+	mon.PidTTL = time.Second * time.Duration(ttl)
+	mon.MaxPidEntries = maxEntries
+
+	return nil
+}
+
+// ReapPidMaps Function
+func ReapPidMaps(pidMaps []tp.PidMap, now time.Time, ttl time.Duration, maxEntries int) int {
+	reaped := 0
+	total := 0
+
+	exited := []exitedPid{}
+
+	// remove the processes exited before the TTL
+
+	for _, pidMap := range pidMaps {
+		for pid, node := range pidMap {
+			if node.Exited && now.After(node.ExitedTime.Add(ttl)) {
+				delete(pidMap, pid)
+				reaped++
+				continue
+			}
+
+			if node.Exited {
+				exited = append(exited, exitedPid{pidMap: pidMap, pid: pid, exitedTime: node.ExitedTime})
+			}
+
+			total++
+		}
+	}
+
+	// evict the oldest exited processes under pressure (running processes are always kept)
+
+	if maxEntries <= 0 || total <= maxEntries {
+		return reaped
+	}
+
+	sort.Slice(exited, func(i, j int) bool {
+		return exited[i].exitedTime.Before(exited[j].exitedTime)
+	})
+
+	for _, entry := range exited {
+		if total <= maxEntries {
+			break
+		}
+
+		delete(entry.pidMap, entry.pid)
+		reaped++
+		total--
+	}
+
+	return reaped
+}
+
+// ReapExitedPids Function
+func (mon *SystemMonitor) ReapExitedPids() int {
+	now := mon.now()
+	reaped := 0
+
+	ActivePidMap := *(mon.ActivePidMap)
+	ActiveHostPidMap := *(mon.ActiveHostPidMap)
+	ActivePidMapLock := *(mon.ActivePidMapLock)
+
+	ActivePidMapLock.Lock()
+
+	pidMaps := []tp.PidMap{}
+	for _, pidMap := range ActivePidMap {
+		pidMaps = append(pidMaps, pidMap)
+	}
+	reaped = reaped + ReapPidMaps(pidMaps, now, mon.PidTTL, mon.MaxPidEntries)
+
+	pidMaps = []tp.PidMap{}
+	for _, pidMap := range ActiveHostPidMap {
+		pidMaps = append(pidMaps, pidMap)
+	}
+	reaped = reaped + ReapPidMaps(pidMaps, now, mon.PidTTL, mon.MaxPidEntries)
+
+	ActivePidMapLock.Unlock()
+
+	ActiveHostMap := *(mon.ActiveHostMap)
+	ActiveHostMapLock := *(mon.ActiveHostMapLock)
+
+	ActiveHostMapLock.Lock()
+
+	pidMaps = []tp.PidMap{}
+	for _, pidMap := range ActiveHostMap {
+		pidMaps = append(pidMaps, pidMap)
+	}
+	reaped = reaped + ReapPidMaps(pidMaps, now, mon.PidTTL, mon.MaxPidEntries)
+
+	// host pid -> pid map (empty once the process is reaped)
+	for hostPid, pidMap := range ActiveHostMap {
+		if len(pidMap) == 0 {
+			delete(ActiveHostMap, hostPid)
+		}
+	}
+
+	ActiveHostMapLock.Unlock()
+
+	return reaped
+}
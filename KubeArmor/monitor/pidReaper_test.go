@@ -0,0 +1,160 @@
+package monitor
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+func TestReapPidMaps(t *testing.T) {
+	now := time.Now()
+
+	pidMap := tp.PidMap{
+		1: {PID: 1, ExecPath: "/bin/bash"},
+		2: {PID: 2, ExecPath: "/bin/sleep"},
+		3: {PID: 3, ExecPath: "/bin/ls", Exited: true, ExitedTime: now},
+	}
+
+	// running processes are kept even over the limit
+
+	if reaped := ReapPidMaps([]tp.PidMap{pidMap}, now, time.Second*10, 1); reaped != 1 || len(pidMap) != 2 {
+		t.Errorf("[FAIL] Failed to evict exited processes only (%d reaped, %v)", reaped, pidMap)
+		return
+	}
+
+	t.Log("[PASS] Kept running processes under pressure")
+}
+
+func TestReapExitedPids(t *testing.T) {
+	// Set up Test Data
+
+	Containers := map[string]tp.Container{}
+	ContainersLock := new(sync.RWMutex)
+
+	ActivePidMap := map[string]tp.PidMap{}
+	ActiveHostPidMap := map[string]tp.PidMap{}
+	ActivePidMapLock := new(sync.RWMutex)
+
+	ActiveHostMap := map[uint32]tp.PidMap{}
+	ActiveHostMapLock := new(sync.RWMutex)
+
+	systemMonitor := NewSystemMonitor(nil, false, false, &Containers, &ContainersLock,
+		&ActivePidMap, &ActiveHostPidMap, &ActivePidMapLock, &ActiveHostMap, &ActiveHostMapLock)
+
+	if err := systemMonitor.SetPidReaper(0, 10); err == nil {
+		t.Error("[FAIL] Set an invalid TTL")
+		return
+	}
+
+	if err := systemMonitor.SetPidReaper(10, 0); err != nil {
+		t.Errorf("[FAIL] Failed to set the PID reaper (%s)", err.Error())
+		return
+	}
+
+	// fake clock
+
+	clock := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	systemMonitor.Now = func() time.Time { return clock }
+
+	// bash (1) -> ls (10), cat (11)
+
+	for _, node := range []tp.PidNode{
+		{HostPID: 1001, PPID: 0, PID: 1, ExecPath: "/bin/bash"},
+		{HostPID: 1010, PPID: 1, PID: 10, ExecPath: "/bin/ls"},
+		{HostPID: 1011, PPID: 1, PID: 11, ExecPath: "/bin/cat /etc/passwd"},
+	} {
+		systemMonitor.AddActivePid("ubuntu-1-container", node)
+	}
+
+	systemMonitor.AddActiveHostPid(2001, tp.PidNode{HostPID: 2001, PID: 2001, ExecPath: "/usr/bin/diff"})
+
+	systemMonitor.DeleteActivePid("ubuntu-1-container", SyscallContext{PID: 10, HostPID: 1010})
+	systemMonitor.DeleteActiveHostPid(2001)
+
+	clock = clock.Add(time.Second * 8)
+	systemMonitor.DeleteActivePid("ubuntu-1-container", SyscallContext{PID: 11, HostPID: 1011})
+
+	// within the TTL
+
+	if reaped := systemMonitor.ReapExitedPids(); reaped != 0 {
+		t.Errorf("[FAIL] Reaped processes within the TTL (%d)", reaped)
+		return
+	}
+
+	t.Log("[PASS] Kept exited processes within the TTL")
+
+	// after the TTL of ls and diff, but not cat
+
+	clock = clock.Add(time.Second * 3)
+
+	if reaped := systemMonitor.ReapExitedPids(); reaped != 3 {
+		t.Errorf("[FAIL] Failed to reap exited processes after the TTL (%d)", reaped)
+		return
+	}
+
+	if _, ok := ActivePidMap["ubuntu-1-container"][10]; ok {
+		t.Error("[FAIL] Failed to reap an exited process from ActivePidMap")
+		return
+	}
+
+	if _, ok := ActiveHostPidMap["ubuntu-1-container"][1010]; ok {
+		t.Error("[FAIL] Failed to reap an exited process from ActiveHostPidMap")
+		return
+	}
+
+	if _, ok := ActiveHostMap[2001]; ok {
+		t.Error("[FAIL] Failed to reap an exited process from ActiveHostMap")
+		return
+	}
+
+	t.Log("[PASS] Reaped exited processes after the TTL")
+
+	// in-flight enrichment
+
+	if execPath := systemMonitor.GetExecPath("ubuntu-1-container", 11); execPath != "/bin/cat /etc/passwd" {
+		t.Errorf("[FAIL] Failed to enrich an event of a recently exited process (%s)", execPath)
+		return
+	}
+
+	if execPath := systemMonitor.GetExecPath("ubuntu-1-container", 1); execPath != "/bin/bash" {
+		t.Errorf("[FAIL] Failed to enrich an event of a running process (%s)", execPath)
+		return
+	}
+
+	t.Log("[PASS] Enriched events of recent and running processes")
+
+	// under pressure (bash, cat, and 3 more processes in each map)
+
+	systemMonitor.MaxPidEntries = 3
+
+	for pid := uint32(20); pid < 23; pid++ {
+		systemMonitor.AddActivePid("ubuntu-2-container", tp.PidNode{HostPID: 1000 + pid, PPID: 1, PID: pid, ExecPath: "/bin/sleep"})
+
+		clock = clock.Add(time.Second)
+		systemMonitor.DeleteActivePid("ubuntu-2-container", SyscallContext{PID: pid, HostPID: 1000 + pid})
+	}
+
+	if reaped := systemMonitor.ReapExitedPids(); reaped != 4 {
+		t.Errorf("[FAIL] Failed to evict the oldest exited processes (%d)", reaped)
+		return
+	}
+
+	if _, ok := ActivePidMap["ubuntu-1-container"][11]; ok {
+		t.Error("[FAIL] Failed to evict the oldest exited process")
+		return
+	}
+
+	if _, ok := ActivePidMap["ubuntu-2-container"][20]; ok {
+		t.Error("[FAIL] Failed to evict the second oldest exited process")
+		return
+	}
+
+	if len(ActivePidMap["ubuntu-1-container"]) != 1 || len(ActivePidMap["ubuntu-2-container"]) != 2 || len(ActiveHostPidMap["ubuntu-2-container"]) != 2 {
+		t.Errorf("[FAIL] Evicted more processes than needed (%v)", ActivePidMap)
+		return
+	}
+
+	t.Log("[PASS] Evicted the oldest exited processes under pressure")
+}
@@ -0,0 +1,309 @@
+package monitor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+// ==================== //
+// == Process Limits == //
+// ==================== //
+
+// ProcessLimitSeverity for the logs of exceeded process limits
+const ProcessLimitSeverity = "10"
+
+// DefaultProcessLimitWindow in seconds
+const DefaultProcessLimitWindow = 10
+
+// maxProcessChain to bound the walk up to the ancestors
+const maxProcessChain = 1024
+
+// maxSpawnCounters to bound the ancestors tracked for each container
+const maxSpawnCounters = 4096
+
+// ProcessLimits Structure
+type ProcessLimits struct {
+	// the maximum number of descendants spawned by an ancestor within a window (0 to disable)
+	MaxDescendants int
+
+	// the maximum depth of a process tree (0 to disable)
+	MaxDepth int
+
+	Window time.Duration
+}
+
+// Enabled Function
+func (limits ProcessLimits) Enabled() bool {
+	return limits.MaxDescendants > 0 || limits.MaxDepth > 0
+}
+
+// ParseProcessLimits Function
+func ParseProcessLimits(str string) (ProcessLimits, error) {
+	limits := ProcessLimits{Window: time.Second * DefaultProcessLimitWindow}
+
+	// e.g., maxDescendants=100,maxDepth=16,window=10
+	if str == "" || str == "none" {
+		return limits, nil
+	}
+
+	for _, option := range strings.Split(str, ",") {
+		kv := strings.SplitN(strings.TrimSpace(option), "=", 2)
+		if len(kv) != 2 {
+			return ProcessLimits{}, fmt.Errorf("invalid option (%s)", option)
+		}
+
+		val, err := strconv.Atoi(kv[1])
+		if err != nil || val < 0 {
+			return ProcessLimits{}, fmt.Errorf("invalid value (%s)", option)
+		}
+
+		switch kv[0] {
+		case "maxDescendants":
+			limits.MaxDescendants = val
+		case "maxDepth":
+			limits.MaxDepth = val
+		case "window":
+			if val == 0 {
+				return ProcessLimits{}, fmt.Errorf("invalid window (%s)", option)
+			}
+			limits.Window = time.Second * time.Duration(val)
+		default:
+			return ProcessLimits{}, fmt.Errorf("unknown option (%s)", option)
+		}
+	}
+
+	return limits, nil
+}
+
+// spawnKey Structure (the started time tells a reused pid apart)
+type spawnKey struct {
+	pid         uint32
+	startedTime int64
+}
+
+// spawnCounter Structure
+type spawnCounter struct {
+	// the times of the latest spawns within a window (at most maxDescendants + 1)
+	times []time.Time
+}
+
+// add Function
+func (sc *spawnCounter) add(now time.Time, window time.Duration, maxTimes int) int {
+	since := now.Add(-window)
+
+	idx := 0
+	for idx < len(sc.times) && !sc.times[idx].After(since) {
+		idx++
+	}
+
+	sc.times = append(sc.times[idx:], now)
+	if len(sc.times) > maxTimes {
+		sc.times = sc.times[len(sc.times)-maxTimes:]
+	}
+
+	return len(sc.times)
+}
+
+// SetProcessLimits Function
+func (mon *SystemMonitor) SetProcessLimits(containerID string, limits ProcessLimits) {
+	mon.ProcessLimitsLock.Lock()
+	defer mon.ProcessLimitsLock.Unlock()
+
+	mon.ProcessLimits[containerID] = limits
+
+	// count again with the new limits
+	delete(mon.ProcessSpawns, containerID)
+}
+
+// DeleteProcessLimits Function
+func (mon *SystemMonitor) DeleteProcessLimits(containerID string) {
+	mon.ProcessLimitsLock.Lock()
+	defer mon.ProcessLimitsLock.Unlock()
+
+	delete(mon.ProcessLimits, containerID)
+	delete(mon.ProcessSpawns, containerID)
+
+	for key := range mon.ProcessLimitAlerts {
+		if strings.HasPrefix(key, containerID+"|") {
+			delete(mon.ProcessLimitAlerts, key)
+		}
+	}
+}
+
+// GetProcessLimits Function
+func (mon *SystemMonitor) GetProcessLimits(containerID string) ProcessLimits {
+	mon.ProcessLimitsLock.RLock()
+	defer mon.ProcessLimitsLock.RUnlock()
+
+	if limits, ok := mon.ProcessLimits[containerID]; ok {
+		return limits
+	}
+
+	return mon.DefaultProcessLimits
+}
+
+// GetProcessChain Function
+func GetProcessChain(pidMap tp.PidMap, pid uint32) []uint32 {
+	chain := []uint32{}
+
+	for len(chain) < maxProcessChain {
+		node, ok := pidMap[pid]
+		if !ok {
+			break
+		}
+
+		chain = append(chain, pid)
+
+		if node.PPID == pid {
+			break
+		}
+
+		pid = node.PPID
+	}
+
+	return chain
+}
+
+// countSpawns Function
+//
+// records a spawn for each ancestor and returns the spawns of their descendants within a window
+// (updated on every exec, so only the ancestors of a new process are visited)
+func (mon *SystemMonitor) countSpawns(containerID string, ancestors []tp.PidNode, limits ProcessLimits, now time.Time) []int {
+	mon.ProcessLimitsLock.Lock()
+	defer mon.ProcessLimitsLock.Unlock()
+
+	counters, ok := mon.ProcessSpawns[containerID]
+	if !ok {
+		counters = map[spawnKey]*spawnCounter{}
+		mon.ProcessSpawns[containerID] = counters
+	}
+
+	// forget the ancestors without recent spawns (e.g., exited ones)
+	if len(counters)+len(ancestors) > maxSpawnCounters {
+		since := now.Add(-limits.Window)
+		for key, counter := range counters {
+			if len(counter.times) == 0 || !counter.times[len(counter.times)-1].After(since) {
+				delete(counters, key)
+			}
+		}
+	}
+
+	counts := []int{}
+
+	for _, ancestor := range ancestors {
+		key := spawnKey{pid: ancestor.PID, startedTime: ancestor.StartedTime.UnixNano()}
+
+		counter, ok := counters[key]
+		if !ok {
+			if len(counters) >= maxSpawnCounters {
+				counts = append(counts, 0)
+				continue
+			}
+
+			counter = &spawnCounter{}
+			counters[key] = counter
+		}
+
+		counts = append(counts, counter.add(now, limits.Window, limits.MaxDescendants+1))
+	}
+
+	return counts
+}
+
+// allowProcessLimitAlert Function
+func (mon *SystemMonitor) allowProcessLimitAlert(key string, window time.Duration, now time.Time) bool {
+	mon.ProcessLimitsLock.Lock()
+	defer mon.ProcessLimitsLock.Unlock()
+
+	// alert once per window
+	if last, ok := mon.ProcessLimitAlerts[key]; ok && now.Before(last.Add(window)) {
+		return false
+	}
+
+	mon.ProcessLimitAlerts[key] = now
+
+	return true
+}
+
+// CheckProcessLimits Function
+func (mon *SystemMonitor) CheckProcessLimits(log tp.Log, pid uint32, now time.Time) []tp.Log {
+	alerts := []tp.Log{}
+
+	limits := mon.GetProcessLimits(log.ContainerID)
+	if !limits.Enabled() {
+		return alerts
+	}
+
+	ActivePidMap := *(mon.ActivePidMap)
+	ActivePidMapLock := *(mon.ActivePidMapLock)
+
+	ActivePidMapLock.RLock()
+
+	pidMap, ok := ActivePidMap[log.ContainerID]
+	if !ok {
+		ActivePidMapLock.RUnlock()
+		return alerts
+	}
+
+	chain := GetProcessChain(pidMap, pid)
+	if len(chain) == 0 {
+		ActivePidMapLock.RUnlock()
+		return alerts
+	}
+
+	nodes := []tp.PidNode{}
+	for _, ancestor := range chain {
+		nodes = append(nodes, pidMap[ancestor])
+	}
+
+	ActivePidMapLock.RUnlock()
+
+	counts := []int{}
+	if limits.MaxDescendants > 0 {
+		counts = mon.countSpawns(log.ContainerID, nodes[1:], limits, now)
+	}
+
+	alert := log
+
+	alert.Type = "MatchedPolicy"
+	alert.Severity = ProcessLimitSeverity
+	alert.Tags = "process-limit"
+	alert.Operation = "Process"
+	alert.Action = "Audit"
+
+	// too deep process tree
+
+	if limits.MaxDepth > 0 && len(chain) > limits.MaxDepth {
+		root := nodes[len(nodes)-1]
+
+		if mon.allowProcessLimitAlert(fmt.Sprintf("%s|depth|%d", log.ContainerID, root.PID), limits.Window, now) {
+			alert.Message = "Excessive process tree depth"
+			alert.Data = fmt.Sprintf("ancestor=%d (%s) depth=%d maxDepth=%d", root.PID, root.ExecPath, len(chain), limits.MaxDepth)
+			alerts = append(alerts, alert)
+		}
+	}
+
+	// too many descendants of an ancestor (the closest one)
+
+	for idx, count := range counts {
+		if count <= limits.MaxDescendants {
+			continue
+		}
+
+		ancestor := nodes[idx+1]
+
+		if mon.allowProcessLimitAlert(fmt.Sprintf("%s|descendants|%d", log.ContainerID, ancestor.PID), limits.Window, now) {
+			alert.Message = "Excessive number of descendants"
+			alert.Data = fmt.Sprintf("ancestor=%d (%s) descendants>%d window=%s", ancestor.PID, ancestor.ExecPath, limits.MaxDescendants, limits.Window)
+			alerts = append(alerts, alert)
+		}
+
+		break
+	}
+
+	return alerts
+}
@@ -0,0 +1,143 @@
+package monitor
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+func TestParseProcessLimits(t *testing.T) {
+	limits, err := ParseProcessLimits("maxDescendants=100,maxDepth=16,window=5")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to parse process limits (%s)", err.Error())
+		return
+	}
+
+	if limits.MaxDescendants != 100 || limits.MaxDepth != 16 || limits.Window != time.Second*5 {
+		t.Errorf("[FAIL] Failed to parse process limits (%v)", limits)
+		return
+	}
+
+	if limits, err := ParseProcessLimits("none"); err != nil || limits.Enabled() {
+		t.Errorf("[FAIL] Failed to disable process limits (%v)", limits)
+		return
+	}
+
+	for _, invalid := range []string{"maxDescendants", "maxDepth=-1", "window=0", "maxForks=10"} {
+		if _, err := ParseProcessLimits(invalid); err == nil {
+			t.Errorf("[FAIL] Parsed invalid process limits (%s)", invalid)
+			return
+		}
+	}
+
+	t.Log("[PASS] Parsed process limits")
+}
+
+func TestCheckProcessLimits(t *testing.T) {
+	// Set up Test Data
+
+	Containers := map[string]tp.Container{}
+	ContainersLock := new(sync.RWMutex)
+
+	ActivePidMap := map[string]tp.PidMap{}
+	ActiveHostPidMap := map[string]tp.PidMap{}
+	ActivePidMapLock := new(sync.RWMutex)
+
+	ActiveHostMap := map[uint32]tp.PidMap{}
+	ActiveHostMapLock := new(sync.RWMutex)
+
+	systemMonitor := NewSystemMonitor(nil, false, false, &Containers, &ContainersLock,
+		&ActivePidMap, &ActiveHostPidMap, &ActivePidMapLock, &ActiveHostMap, &ActiveHostMapLock)
+
+	now := time.Now()
+
+	// bash (1) -> sh (2) -> 20 children (100 ~ 119)
+
+	pidMap := tp.PidMap{}
+	pidMap[1] = tp.PidNode{PID: 1, PPID: 0, ExecPath: "/bin/bash", StartedTime: now.Add(-time.Minute)}
+	pidMap[2] = tp.PidNode{PID: 2, PPID: 1, ExecPath: "/bin/sh ./bomb.sh", StartedTime: now.Add(-time.Minute)}
+	for pid := uint32(100); pid < 120; pid++ {
+		pidMap[pid] = tp.PidNode{PID: pid, PPID: 2, ExecPath: "/bin/sh ./bomb.sh", StartedTime: now.Add(-time.Second)}
+	}
+	ActivePidMap["ubuntu-1-container"] = pidMap
+
+	log := tp.Log{ContainerID: "ubuntu-1-container", PID: 119, Operation: "Process", Resource: "/bin/sh ./bomb.sh", Result: "Passed"}
+
+	// the children are executed one by one
+	spawn := func(at time.Time) []tp.Log {
+		alerts := []tp.Log{}
+		for pid := uint32(100); pid < 120; pid++ {
+			alerts = append(alerts, systemMonitor.CheckProcessLimits(log, pid, at)...)
+		}
+		return alerts
+	}
+
+	// below the threshold
+
+	systemMonitor.SetProcessLimits("ubuntu-1-container", ProcessLimits{MaxDescendants: 20, Window: time.Second * 10})
+
+	if alerts := spawn(now); len(alerts) != 0 {
+		t.Errorf("[FAIL] Alerted below the descendant threshold (%v)", alerts)
+		return
+	}
+
+	t.Log("[PASS] Kept silent below the descendant threshold")
+
+	// above the threshold (alerted once per window for the same ancestor)
+
+	systemMonitor.SetProcessLimits("ubuntu-1-container", ProcessLimits{MaxDescendants: 10, Window: time.Second * 10})
+
+	alerts := spawn(now)
+	if len(alerts) != 1 {
+		t.Errorf("[FAIL] Failed to alert above the descendant threshold (%d alerts)", len(alerts))
+		return
+	}
+
+	alert := alerts[0]
+	if alert.Type != "MatchedPolicy" || alert.Severity != ProcessLimitSeverity || alert.Operation != "Process" ||
+		alert.Data != "ancestor=2 (/bin/sh ./bomb.sh) descendants>10 window=10s" {
+		t.Errorf("[FAIL] Failed to build an alert for the descendant threshold (%v)", alert)
+		return
+	}
+
+	if counters := len(systemMonitor.ProcessSpawns["ubuntu-1-container"]); counters != 2 {
+		t.Errorf("[FAIL] Failed to count spawns only for the ancestors (%d counters)", counters)
+		return
+	}
+
+	t.Log("[PASS] Alerted above the descendant threshold")
+
+	// spawns out of the window are not counted
+
+	if alerts := systemMonitor.CheckProcessLimits(log, 119, now.Add(time.Minute)); len(alerts) != 0 {
+		t.Errorf("[FAIL] Counted spawns out of the window (%v)", alerts)
+		return
+	}
+
+	t.Log("[PASS] Counted spawns only within a window")
+
+	// depth
+
+	systemMonitor.SetProcessLimits("ubuntu-1-container", ProcessLimits{MaxDepth: 2, Window: time.Second * 10})
+
+	alerts = systemMonitor.CheckProcessLimits(log, 119, now)
+	if len(alerts) != 1 || alerts[0].Data != "ancestor=1 (/bin/bash) depth=3 maxDepth=2" {
+		t.Errorf("[FAIL] Failed to alert above the depth threshold (%v)", alerts)
+		return
+	}
+
+	t.Log("[PASS] Alerted above the depth threshold")
+
+	// disabled by default
+
+	systemMonitor.DeleteProcessLimits("ubuntu-1-container")
+
+	if alerts := systemMonitor.CheckProcessLimits(log, 119, now.Add(time.Hour)); len(alerts) != 0 {
+		t.Errorf("[FAIL] Alerted without process limits (%v)", alerts)
+		return
+	}
+
+	t.Log("[PASS] Disabled process limits by default")
+}
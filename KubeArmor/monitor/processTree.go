@@ -4,9 +4,11 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
+	kl "github.com/accuknox/KubeArmor/KubeArmor/common"
 	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
 )
 
@@ -19,41 +21,16 @@ func (mon *SystemMonitor) LookupContainerID(pidns uint32, mntns uint32, pid uint
 	key := NsKey{PidNS: pidns, MntNS: mntns}
 
 	if pid == 1 {
-		containerID := "None"
-
 		// first shot: look up container id from cgroup
 
-		cgroup, err := os.Open(fmt.Sprintf("/proc/%d/cgroup", pid))
+		containerID, err := GetContainerIDFromCgroup(pid)
 		if err != nil {
 			return "" // this is nature, just meaning that the PID no longer exists
 		}
 
-		cgroupScanner := bufio.NewScanner(cgroup)
-		for cgroupScanner.Scan() {
-			line := cgroupScanner.Text()
-
-			// k8s
-			parts := kubePattern.FindStringSubmatch(line)
-			if parts != nil {
-				containerID = parts[1]
-				break
-			}
-
-			// docker
-			parts = dockerPattern.FindStringSubmatch(line)
-			if parts != nil {
-				containerID = parts[1]
-				break
-			}
-		}
-
-		cgroup.Close()
-
 		// update newly found container id
 		if containerID != "None" {
-			mon.NsMapLock.Lock()
-			mon.NsMap[key] = containerID
-			mon.NsMapLock.Unlock()
+			mon.UpdateNsMap(key, containerID)
 			return containerID
 		}
 
@@ -86,9 +63,7 @@ func (mon *SystemMonitor) LookupContainerID(pidns uint32, mntns uint32, pid uint
 
 		// update newly found container id
 		if containerID != "None" {
-			mon.NsMapLock.Lock()
-			mon.NsMap[key] = containerID
-			mon.NsMapLock.Unlock()
+			mon.UpdateNsMap(key, containerID)
 			return containerID
 		}
 	} else {
@@ -100,41 +75,16 @@ func (mon *SystemMonitor) LookupContainerID(pidns uint32, mntns uint32, pid uint
 		mon.NsMapLock.RUnlock()
 
 		if newProcess { // if new process, look up container id
-			containerID := "None"
-
 			// first shot: look up container id from cgroup
 
-			cgroup, err := os.Open(fmt.Sprintf("/proc/%d/cgroup", pid))
+			containerID, err := GetContainerIDFromCgroup(pid)
 			if err != nil {
 				return "" // this is nature, just meaning that the PID no longer exists
 			}
 
-			cgroupScanner := bufio.NewScanner(cgroup)
-			for cgroupScanner.Scan() {
-				line := cgroupScanner.Text()
-
-				// k8s
-				parts := kubePattern.FindStringSubmatch(line)
-				if parts != nil {
-					containerID = parts[1]
-					break
-				}
-
-				// docker
-				parts = dockerPattern.FindStringSubmatch(line)
-				if parts != nil {
-					containerID = parts[1]
-					break
-				}
-			}
-
-			cgroup.Close()
-
 			// update newly found container id
 			if containerID != "None" {
-				mon.NsMapLock.Lock()
-				mon.NsMap[key] = containerID
-				mon.NsMapLock.Unlock()
+				mon.UpdateNsMap(key, containerID)
 				return containerID
 			}
 
@@ -167,9 +117,7 @@ func (mon *SystemMonitor) LookupContainerID(pidns uint32, mntns uint32, pid uint
 
 			// update newly found container id
 			if containerID != "None" {
-				mon.NsMapLock.Lock()
-				mon.NsMap[key] = containerID
-				mon.NsMapLock.Unlock()
+				mon.UpdateNsMap(key, containerID)
 				return containerID
 			}
 		}
@@ -178,11 +126,34 @@ func (mon *SystemMonitor) LookupContainerID(pidns uint32, mntns uint32, pid uint
 	return ""
 }
 
+// UpdateNsMap Function
+func (mon *SystemMonitor) UpdateNsMap(key NsKey, containerID string) {
+	mon.NsMapLock.Lock()
+	defer mon.NsMapLock.Unlock()
+
+	// apply the syscall allow-list to the new namespaces
+	if val, ok := mon.NsMap[key]; !ok || val != containerID {
+		if allowList, ok := mon.GetSyscallAllowList(containerID); ok {
+			if err := mon.updateSyscallAllowMap(key, allowList); err != nil {
+				mon.LogFeeder.Errf("Failed to update the syscall allow-list of %s (%s)", containerID, err.Error())
+			}
+		}
+	}
+
+	mon.NsMap[key] = containerID
+
+	// keep track of the containers in each PID namespace
+	if !kl.ContainsElement(mon.PidNsMap[key.PidNS], containerID) {
+		mon.PidNsMap[key.PidNS] = append(mon.PidNsMap[key.PidNS], containerID)
+	}
+}
+
+// DeleteContainerIDFromNsMap Function
 func (mon *SystemMonitor) DeleteContainerIDFromNsMap(containerID string) {
 	ns := NsKey{}
 
-	mon.NsMapLock.RLock()
-	defer mon.NsMapLock.RUnlock()
+	mon.NsMapLock.Lock()
+	defer mon.NsMapLock.Unlock()
 
 	for key, val := range mon.NsMap {
 		if containerID == val {
@@ -193,6 +164,19 @@ func (mon *SystemMonitor) DeleteContainerIDFromNsMap(containerID string) {
 
 	if ns.PidNS != 0 && ns.MntNS != 0 {
 		delete(mon.NsMap, ns)
+
+		containerIDs := []string{}
+		for _, id := range mon.PidNsMap[ns.PidNS] {
+			if id != containerID {
+				containerIDs = append(containerIDs, id)
+			}
+		}
+
+		if len(containerIDs) > 0 {
+			mon.PidNsMap[ns.PidNS] = containerIDs
+		} else {
+			delete(mon.PidNsMap, ns.PidNS)
+		}
 	}
 }
 
@@ -220,11 +204,125 @@ func (mon *SystemMonitor) BuildPidNode(ctx SyscallContext, execPath string, args
 		}
 	}
 
+	node.InterpretedCommand = mon.ParseInterpretedCommand(execPath, args)
+
+	node.StartedTime = time.Now()
+
 	node.Exited = false
 
 	return node
 }
 
+// interpreterOptions Structure
+type interpreterOptions struct {
+	// options followed by inline code or a module (e.g., sh -c, python -m)
+	Inline []string
+
+	// options followed by their own arguments (e.g., sh -o pipefail, python -W ignore)
+	WithArg []string
+
+	// combined short options and +options (e.g., sh -ec, sh +o posix)
+	ShellStyle bool
+}
+
+// shellOptions for sh-compatible shells
+var shellOptions = interpreterOptions{
+	Inline:     []string{"-c"},
+	WithArg:    []string{"-o", "+o", "-O", "+O", "--rcfile", "--init-file"},
+	ShellStyle: true,
+}
+
+// interpreterOptionTable for known interpreters
+var interpreterOptionTable = map[string]interpreterOptions{
+	"sh":   shellOptions,
+	"bash": shellOptions,
+	"dash": shellOptions,
+	"ash":  shellOptions,
+	"zsh":  shellOptions,
+	"ksh":  shellOptions,
+
+	"python": {Inline: []string{"-c", "-m"}, WithArg: []string{"-W", "-X", "-Q"}},
+	"perl":   {Inline: []string{"-e", "-E"}, WithArg: []string{"-x"}},
+	"ruby":   {Inline: []string{"-e"}, WithArg: []string{"-r", "-I", "-C", "-E", "--encoding"}},
+	"node":   {Inline: []string{"-e", "--eval", "-p", "--print"}, WithArg: []string{"-r", "--require", "--input-type"}},
+	"php":    {Inline: []string{"-r", "-f"}, WithArg: []string{"-c", "-d", "-z"}},
+}
+
+// defaultInterpreterOptions for the other interpreters
+var defaultInterpreterOptions = interpreterOptions{
+	Inline: []string{"-c", "-e", "--eval"},
+}
+
+// getInterpreterName Function
+func getInterpreterName(execPath string) string {
+	// python3.8 -> python
+	return strings.TrimRight(filepath.Base(execPath), "0123456789.")
+}
+
+// IsInterpreter Function
+func (mon *SystemMonitor) IsInterpreter(execPath string) bool {
+	name := getInterpreterName(execPath)
+
+	for _, interpreter := range mon.Interpreters {
+		if name == interpreter || filepath.Base(execPath) == interpreter {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseInterpretedCommand Function
+func (mon *SystemMonitor) ParseInterpretedCommand(execPath string, args []string) string {
+	if !mon.IsInterpreter(execPath) {
+		return ""
+	}
+
+	options, ok := interpreterOptionTable[getInterpreterName(execPath)]
+	if !ok {
+		options = defaultInterpreterOptions
+	}
+
+	for idx := 1; idx < len(args); idx++ {
+		arg := args[idx]
+
+		if arg == "--" {
+			if idx+1 < len(args) {
+				return strings.Join(args[idx+1:], " ")
+			}
+			break
+		}
+
+		if strings.HasPrefix(arg, "-") || (options.ShellStyle && strings.HasPrefix(arg, "+") && len(arg) > 1) {
+			inline := kl.ContainsElement(options.Inline, arg)
+			withArg := kl.ContainsElement(options.WithArg, arg)
+
+			// combined shell options (e.g., sh -ec "...", sh -eo pipefail)
+			if options.ShellStyle && !strings.HasPrefix(arg, "--") && len(arg) > 2 {
+				inline = strings.HasPrefix(arg, "-") && strings.Contains(arg[1:], "c")
+				withArg = strings.HasSuffix(arg, "o") || strings.HasSuffix(arg, "O")
+			}
+
+			// inline code (sh -c, python -c, perl -e, php -r, ...) or module (python -m)
+			if inline && idx+1 < len(args) && !strings.HasPrefix(args[idx+1], "-") {
+				return args[idx+1]
+			}
+
+			// skip the argument of the option
+			if withArg {
+				idx++
+			}
+
+			continue
+		}
+
+		// script (with its arguments)
+		return strings.Join(args[idx:], " ")
+	}
+
+	return ""
+}
+
 // AddActivePid Function
 func (mon *SystemMonitor) AddActivePid(containerID string, node tp.PidNode) {
 	ActivePidMap := *(mon.ActivePidMap)
@@ -269,6 +367,23 @@ func (mon *SystemMonitor) GetExecPath(containerID string, pid uint32) string {
 	return ""
 }
 
+// GetInterpretedCommand Function
+func (mon *SystemMonitor) GetInterpretedCommand(containerID string, pid uint32) string {
+	ActivePidMap := *(mon.ActivePidMap)
+	ActivePidMapLock := *(mon.ActivePidMapLock)
+
+	ActivePidMapLock.RLock()
+	defer ActivePidMapLock.RUnlock()
+
+	if pidMap, ok := ActivePidMap[containerID]; ok {
+		if node, ok := pidMap[pid]; ok {
+			return node.InterpretedCommand
+		}
+	}
+
+	return ""
+}
+
 // GetExecPathWithHostPID Function
 func (mon *SystemMonitor) GetExecPathWithHostPID(containerID string, hostPid uint32) string {
 	ActiveHostPidMap := *(mon.ActiveHostPidMap)
@@ -298,7 +413,8 @@ func (mon *SystemMonitor) DeleteActivePid(containerID string, ctx SyscallContext
 	if pidMap, ok := ActivePidMap[containerID]; ok {
 		if node, ok := pidMap[ctx.PID]; ok {
 			node.Exited = true
-			node.ExitedTime = time.Now()
+			node.ExitedTime = mon.now()
+			pidMap[ctx.PID] = node
 		}
 	}
 
@@ -308,7 +424,8 @@ func (mon *SystemMonitor) DeleteActivePid(containerID string, ctx SyscallContext
 	if pidMap, ok := ActiveHostPidMap[containerID]; ok {
 		if node, ok := pidMap[ctx.HostPID]; ok {
 			node.Exited = true
-			node.ExitedTime = time.Now()
+			node.ExitedTime = mon.now()
+			pidMap[ctx.HostPID] = node
 		}
 	}
 }
@@ -316,52 +433,6 @@ func (mon *SystemMonitor) DeleteActivePid(containerID string, ctx SyscallContext
 // CleanUpExitedHostPids Function
 func (mon *SystemMonitor) CleanUpExitedHostPids() {
 	for range mon.Ticker.C {
-		now := time.Now()
-
-		ActivePidMap := *(mon.ActivePidMap)
-		ActivePidMapLock := *(mon.ActivePidMapLock)
-
-		ActivePidMapLock.Lock()
-
-		for _, pidMap := range ActivePidMap {
-			for pid, pidNode := range pidMap {
-				if pidNode.Exited {
-					if now.After(pidNode.ExitedTime.Add(time.Second * 5)) {
-						delete(pidMap, pid)
-					}
-				}
-			}
-		}
-
-		ActiveHostPidMap := *(mon.ActiveHostPidMap)
-
-		for _, pidMap := range ActiveHostPidMap {
-			for pid, pidNode := range pidMap {
-				if pidNode.Exited {
-					if now.After(pidNode.ExitedTime.Add(time.Second * 5)) {
-						delete(pidMap, pid)
-					}
-				}
-			}
-		}
-
-		ActivePidMapLock.Unlock()
-
-		ActiveHostMap := *(mon.ActiveHostMap)
-		ActiveHostMapLock := *(mon.ActiveHostMapLock)
-
-		ActiveHostMapLock.Lock()
-
-		for _, pidMap := range ActiveHostMap {
-			for pid, pidNode := range pidMap {
-				if pidNode.Exited {
-					if now.After(pidNode.ExitedTime.Add(time.Second * 10)) {
-						delete(pidMap, pid)
-					}
-				}
-			}
-		}
-
-		ActiveHostMapLock.Unlock()
+		mon.ReapExitedPids()
 	}
 }
@@ -0,0 +1,85 @@
+package monitor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+)
+
+// =========================== //
+// == Shared PID Namespaces == //
+// =========================== //
+
+// procDir for the information of processes
+var procDir = "/proc"
+
+// GetContainerIDFromCgroup Function
+func GetContainerIDFromCgroup(hostPid uint32) (string, error) {
+	cgroup, err := os.Open(fmt.Sprintf("%s/%d/cgroup", procDir, hostPid))
+	if err != nil {
+		return "", err
+	}
+	defer cgroup.Close()
+
+	cgroupScanner := bufio.NewScanner(cgroup)
+	for cgroupScanner.Scan() {
+		line := cgroupScanner.Text()
+
+		// k8s
+		parts := kubePattern.FindStringSubmatch(line)
+		if parts != nil {
+			return parts[1], nil
+		}
+
+		// docker
+		parts = dockerPattern.FindStringSubmatch(line)
+		if parts != nil {
+			return parts[1], nil
+		}
+	}
+
+	return "None", nil
+}
+
+// IsSharedPidNs Function
+func (mon *SystemMonitor) IsSharedPidNs(pidns uint32) bool {
+	mon.NsMapLock.RLock()
+	defer mon.NsMapLock.RUnlock()
+
+	return len(mon.PidNsMap[pidns]) > 1
+}
+
+// LookupSharedContainerID Function
+func (mon *SystemMonitor) LookupSharedContainerID(pidns uint32, hostPid uint32, containerID string) (string, bool) {
+	if !mon.EnableSharedPidNs || !mon.IsSharedPidNs(pidns) {
+		return containerID, false
+	}
+
+	mon.SharedPidMapLock.RLock()
+	if val, ok := mon.SharedPidMap[hostPid]; ok {
+		mon.SharedPidMapLock.RUnlock()
+		return val, false
+	}
+	mon.SharedPidMapLock.RUnlock()
+
+	// the cgroup of the process tells the container that it belongs to
+	cgroupContainerID, err := GetContainerIDFromCgroup(hostPid)
+	if err != nil || cgroupContainerID == "None" {
+		// the process is gone, so the container id from the namespaces may be wrong
+		return containerID, true
+	}
+
+	mon.SharedPidMapLock.Lock()
+	mon.SharedPidMap[hostPid] = cgroupContainerID
+	mon.SharedPidMapLock.Unlock()
+
+	return cgroupContainerID, false
+}
+
+// DeleteSharedPid Function
+func (mon *SystemMonitor) DeleteSharedPid(hostPid uint32) {
+	mon.SharedPidMapLock.Lock()
+	defer mon.SharedPidMapLock.Unlock()
+
+	delete(mon.SharedPidMap, hostPid)
+}
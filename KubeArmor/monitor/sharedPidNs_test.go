@@ -0,0 +1,146 @@
+package monitor
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+func TestLookupSharedContainerID(t *testing.T) {
+	// Set up Test Data
+
+	Containers := map[string]tp.Container{}
+	ContainersLock := new(sync.RWMutex)
+
+	ActivePidMap := map[string]tp.PidMap{}
+	ActiveHostPidMap := map[string]tp.PidMap{}
+	ActivePidMapLock := new(sync.RWMutex)
+
+	ActiveHostMap := map[uint32]tp.PidMap{}
+	ActiveHostMapLock := new(sync.RWMutex)
+
+	systemMonitor := NewSystemMonitor(nil, false, false, &Containers, &ContainersLock,
+		&ActivePidMap, &ActiveHostPidMap, &ActivePidMapLock, &ActiveHostMap, &ActiveHostMapLock)
+	systemMonitor.EnableSharedPidNs = true
+
+	// two containers in a pod with shareProcessNamespace
+
+	containerA := strings.Repeat("a", 64)
+	containerB := strings.Repeat("b", 64)
+
+	dir, err := ioutil.TempDir("", "kubearmor-proc")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a temporary directory (%s)", err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	procDir = dir
+	defer func() { procDir = "/proc" }()
+
+	for hostPid, containerID := range map[uint32]string{101: containerA, 102: containerB} {
+		if err := os.MkdirAll(filepath.Join(dir, fmt.Sprint(hostPid)), 0755); err != nil {
+			t.Errorf("[FAIL] Failed to create a process directory (%s)", err.Error())
+			return
+		}
+
+		cgroup := fmt.Sprintf("12:pids:/kubepods/besteffort/pod5e6a1b2c/%s\n1:name=systemd:/kubepods/besteffort/pod5e6a1b2c/%s\n", containerID, containerID)
+		if err := ioutil.WriteFile(filepath.Join(dir, fmt.Sprint(hostPid), "cgroup"), []byte(cgroup), 0644); err != nil {
+			t.Errorf("[FAIL] Failed to write a cgroup file (%s)", err.Error())
+			return
+		}
+	}
+
+	systemMonitor.UpdateNsMap(NsKey{PidNS: 4026532001, MntNS: 4026532101}, containerA)
+
+	// a single container in the PID namespace
+
+	if containerID, ambiguous := systemMonitor.LookupSharedContainerID(4026532001, 102, containerA); containerID != containerA || ambiguous {
+		t.Errorf("[FAIL] Changed the container of an unshared PID namespace (%s, %v)", containerID, ambiguous)
+		return
+	}
+
+	t.Log("[PASS] Kept the container of an unshared PID namespace")
+
+	// the second container joins the PID namespace
+
+	systemMonitor.UpdateNsMap(NsKey{PidNS: 4026532001, MntNS: 4026532102}, containerB)
+
+	if !systemMonitor.IsSharedPidNs(4026532001) {
+		t.Errorf("[FAIL] Failed to detect a shared PID namespace")
+		return
+	}
+
+	t.Log("[PASS] Detected a shared PID namespace")
+
+	// per-process attribution
+
+	if containerID, ambiguous := systemMonitor.LookupSharedContainerID(4026532001, 101, containerB); containerID != containerA || ambiguous {
+		t.Errorf("[FAIL] Failed to attribute a process to container A (%s, %v)", containerID, ambiguous)
+		return
+	}
+
+	if containerID, ambiguous := systemMonitor.LookupSharedContainerID(4026532001, 102, containerA); containerID != containerB || ambiguous {
+		t.Errorf("[FAIL] Failed to attribute a process to container B (%s, %v)", containerID, ambiguous)
+		return
+	}
+
+	t.Log("[PASS] Attributed processes to their own containers")
+
+	// a process that is gone
+
+	if containerID, ambiguous := systemMonitor.LookupSharedContainerID(4026532001, 103, containerA); containerID != containerA || !ambiguous {
+		t.Errorf("[FAIL] Failed to mark an ambiguous process (%s, %v)", containerID, ambiguous)
+		return
+	}
+
+	t.Log("[PASS] Marked an ambiguous process")
+
+	// cached until the process exits
+
+	os.RemoveAll(filepath.Join(dir, "102"))
+
+	if containerID, ambiguous := systemMonitor.LookupSharedContainerID(4026532001, 102, containerA); containerID != containerB || ambiguous {
+		t.Errorf("[FAIL] Failed to use the cached container of a process (%s, %v)", containerID, ambiguous)
+		return
+	}
+
+	systemMonitor.DeleteSharedPid(102)
+
+	if _, ambiguous := systemMonitor.LookupSharedContainerID(4026532001, 102, containerA); !ambiguous {
+		t.Errorf("[FAIL] Used the container of an exited process")
+		return
+	}
+
+	t.Log("[PASS] Cached the container of a process until it exits")
+
+	// disabled
+
+	systemMonitor.EnableSharedPidNs = false
+
+	if containerID, ambiguous := systemMonitor.LookupSharedContainerID(4026532001, 101, containerB); containerID != containerB || ambiguous {
+		t.Errorf("[FAIL] Changed the container with the option disabled (%s, %v)", containerID, ambiguous)
+		return
+	}
+
+	systemMonitor.EnableSharedPidNs = true
+
+	t.Log("[PASS] Kept the container with the option disabled")
+
+	// the second container is removed
+
+	systemMonitor.DeleteContainerIDFromNsMap(containerB)
+
+	if systemMonitor.IsSharedPidNs(4026532001) {
+		t.Errorf("[FAIL] Failed to update a PID namespace after removing a container")
+		return
+	}
+
+	t.Log("[PASS] Updated a PID namespace after removing a container")
+}
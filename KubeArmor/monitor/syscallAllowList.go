@@ -0,0 +1,260 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+
+	fd "github.com/accuknox/KubeArmor/KubeArmor/feeder"
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+	"github.com/iovisor/gobpf/bcc"
+)
+
+// ========================= //
+// == Syscall Allow-lists == //
+// ========================= //
+
+// SyscallViolationSeverity for the logs of the syscalls out of allow-lists
+const SyscallViolationSeverity = "8"
+
+// maxSyscallNumber should be matched with MAX_SYSCALL_NR in system_monitor.c
+const maxSyscallNumber = 512
+
+// SyscallTemplates for the base sets of syscalls (e.g., @base)
+var SyscallTemplates = map[string][]string{
+	// syscalls commonly used by container runtimes and language runtimes
+	"base": {
+		"read", "write", "open", "openat", "close", "stat", "fstat", "lstat", "newfstatat", "statx",
+		"poll", "lseek", "mmap", "mprotect", "munmap", "brk", "rt_sigaction", "rt_sigprocmask",
+		"rt_sigreturn", "ioctl", "pread64", "pwrite64", "readv", "writev", "access", "faccessat",
+		"pipe", "pipe2", "select", "pselect6", "sched_yield", "mremap", "madvise", "dup", "dup2",
+		"dup3", "nanosleep", "clock_nanosleep", "getpid", "gettid", "clone", "fork", "vfork",
+		"execve", "exit", "exit_group", "wait4", "kill", "tgkill", "uname", "fcntl", "flock",
+		"fsync", "fdatasync", "getcwd", "chdir", "fchdir", "rename", "renameat", "mkdir",
+		"mkdirat", "rmdir", "unlink", "unlinkat", "readlink", "readlinkat", "chmod", "fchmod",
+		"umask", "gettimeofday", "clock_gettime", "getrlimit", "prlimit64", "getrusage",
+		"sysinfo", "getuid", "getgid", "geteuid", "getegid", "getppid", "getpgrp", "setpgid",
+		"setsid", "getgroups", "sigaltstack", "arch_prctl", "prctl", "futex", "set_tid_address",
+		"set_robust_list", "get_robust_list", "getdents", "getdents64", "epoll_create",
+		"epoll_create1", "epoll_ctl", "epoll_wait", "epoll_pwait", "eventfd", "eventfd2",
+		"timerfd_create", "timerfd_settime", "timerfd_gettime", "getrandom", "sched_getaffinity",
+		"rseq", "restart_syscall",
+	},
+
+	// syscalls for network clients and servers
+	"network": {
+		"socket", "connect", "accept", "accept4", "bind", "listen", "sendto", "recvfrom",
+		"sendmsg", "recvmsg", "sendmmsg", "recvmmsg", "shutdown", "getsockname", "getpeername",
+		"socketpair", "setsockopt", "getsockopt", "sendfile",
+	},
+}
+
+// GetSyscallNumber Function
+func GetSyscallNumber(name string) (int32, bool) {
+	for number, syscallName := range syscalls {
+		if number >= maxSyscallNumber || !strings.HasPrefix(syscallName, "SYS_") {
+			continue
+		}
+
+		if strings.ToLower(strings.TrimPrefix(syscallName, "SYS_")) == name {
+			return number, true
+		}
+	}
+
+	return -1, false
+}
+
+// ParseSyscallAllowList Function
+func ParseSyscallAllowList(str string) ([]int32, error) {
+	allowed := map[int32]bool{}
+
+	// e.g., @base,@network,ptrace
+	for _, item := range strings.Split(str, ",") {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item == "" {
+			continue
+		}
+
+		names := []string{item}
+
+		if strings.HasPrefix(item, "@") {
+			template, ok := SyscallTemplates[strings.TrimPrefix(item, "@")]
+			if !ok {
+				return nil, fmt.Errorf("unknown template (%s)", item)
+			}
+			names = template
+		}
+
+		for _, name := range names {
+			number, ok := GetSyscallNumber(name)
+			if !ok {
+				// the templates may have syscalls which do not exist in this architecture
+				if item != name {
+					continue
+				}
+				return nil, fmt.Errorf("unknown syscall (%s)", name)
+			}
+			allowed[number] = true
+		}
+	}
+
+	if len(allowed) == 0 {
+		return nil, fmt.Errorf("empty allow-list (%s)", str)
+	}
+
+	numbers := []int32{}
+	for number := range allowed {
+		numbers = append(numbers, number)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+
+	return numbers, nil
+}
+
+// attachSyscallAllowProbe Function (SyscallAllowListsLock should be held)
+//
+// hooks every syscall, so it is only attached when a container has an allow-list
+func (mon *SystemMonitor) attachSyscallAllowProbe() error {
+	if mon.SyscallAllowProbe || mon.BpfModule == nil {
+		return nil
+	}
+
+	tpFd, err := mon.BpfModule.LoadTracepoint("tracepoint__raw_syscalls__sys_enter")
+	if err != nil {
+		return fmt.Errorf("error loading tracepoint raw_syscalls:sys_enter: %v", err)
+	}
+
+	if err := mon.BpfModule.AttachTracepoint("raw_syscalls:sys_enter", tpFd); err != nil {
+		return fmt.Errorf("error attaching tracepoint raw_syscalls:sys_enter: %v", err)
+	}
+
+	mon.SyscallAllowProbe = true
+
+	return nil
+}
+
+// SetSyscallAllowList Function
+func (mon *SystemMonitor) SetSyscallAllowList(containerID string, allowList []int32) {
+	mon.SyscallAllowListsLock.Lock()
+	mon.SyscallAllowLists[containerID] = allowList
+	if err := mon.attachSyscallAllowProbe(); err != nil {
+		mon.LogFeeder.Errf("Failed to enable syscall allow-lists (%s)", err.Error())
+	}
+	mon.SyscallAllowListsLock.Unlock()
+
+	for _, key := range mon.getNsKeys(containerID) {
+		if err := mon.updateSyscallAllowMap(key, allowList); err != nil {
+			mon.LogFeeder.Errf("Failed to update the syscall allow-list of %s (%s)", containerID, err.Error())
+		}
+	}
+}
+
+// DeleteSyscallAllowList Function
+func (mon *SystemMonitor) DeleteSyscallAllowList(containerID string) {
+	mon.SyscallAllowListsLock.Lock()
+	if _, ok := mon.SyscallAllowLists[containerID]; !ok {
+		mon.SyscallAllowListsLock.Unlock()
+		return
+	}
+	delete(mon.SyscallAllowLists, containerID)
+	mon.SyscallAllowListsLock.Unlock()
+
+	for _, key := range mon.getNsKeys(containerID) {
+		if err := mon.updateSyscallAllowMap(key, nil); err != nil {
+			mon.LogFeeder.Errf("Failed to delete the syscall allow-list of %s (%s)", containerID, err.Error())
+		}
+	}
+}
+
+// GetSyscallAllowList Function
+func (mon *SystemMonitor) GetSyscallAllowList(containerID string) ([]int32, bool) {
+	mon.SyscallAllowListsLock.RLock()
+	defer mon.SyscallAllowListsLock.RUnlock()
+
+	allowList, ok := mon.SyscallAllowLists[containerID]
+	return allowList, ok
+}
+
+// IsSyscallAllowed Function
+func (mon *SystemMonitor) IsSyscallAllowed(containerID string, number int32) bool {
+	allowList, ok := mon.GetSyscallAllowList(containerID)
+	if !ok {
+		return true
+	}
+
+	idx := sort.Search(len(allowList), func(i int) bool { return allowList[i] >= number })
+	return idx < len(allowList) && allowList[idx] == number
+}
+
+// getNsKeys Function
+func (mon *SystemMonitor) getNsKeys(containerID string) []NsKey {
+	keys := []NsKey{}
+
+	mon.NsMapLock.RLock()
+	defer mon.NsMapLock.RUnlock()
+
+	for key, val := range mon.NsMap {
+		if val == containerID {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// updateSyscallAllowMap Function
+func (mon *SystemMonitor) updateSyscallAllowMap(key NsKey, allowList []int32) error {
+	if mon.BpfModule == nil {
+		return nil
+	}
+
+	table := bcc.NewTable(mon.BpfModule.TableId("syscall_allow_map"), mon.BpfModule)
+
+	keyBuf := new(bytes.Buffer)
+	if err := binary.Write(keyBuf, mon.HostByteOrder, key); err != nil {
+		return err
+	}
+
+	if allowList == nil {
+		return table.Delete(keyBuf.Bytes())
+	}
+
+	bitmap := [maxSyscallNumber / 64]uint64{}
+	for _, number := range allowList {
+		if number >= 0 && number < maxSyscallNumber {
+			bitmap[number/64] |= 1 << uint(number%64)
+		}
+	}
+
+	leafBuf := new(bytes.Buffer)
+	if err := binary.Write(leafBuf, mon.HostByteOrder, bitmap); err != nil {
+		return err
+	}
+
+	return table.Set(keyBuf.Bytes(), leafBuf.Bytes())
+}
+
+// BuildSyscallViolationLog Function
+func (mon *SystemMonitor) BuildSyscallViolationLog(log tp.Log, number int32) tp.Log {
+	name := strings.ToLower(strings.TrimPrefix(getSyscallName(number), "SYS_"))
+
+	log.Type = "MatchedPolicy"
+	log.Severity = SyscallViolationSeverity
+	log.Tags = "syscall-allowlist"
+	log.Message = "Syscall out of the allow-list"
+
+	log.Operation = "Syscall"
+	log.Resource = name
+	log.Data = fmt.Sprintf("syscall=%s nr=%d", name, number)
+
+	// allow-lists are alert-only: tracepoints cannot deny a syscall, so the syscall is only reported
+	// (at most once per second for each container and syscall, see syscall_violation_map)
+	log.Action = "Audit"
+
+	// filtered by eBPF
+	log.DecisionPoint = fd.DecisionPointKernel
+
+	return log
+}
@@ -0,0 +1,117 @@
+package monitor
+
+import (
+	"sync"
+	"testing"
+
+	tp "github.com/accuknox/KubeArmor/KubeArmor/types"
+)
+
+func TestParseSyscallAllowList(t *testing.T) {
+	allowList, err := ParseSyscallAllowList("@base, @network, ptrace")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to parse a syscall allow-list (%s)", err.Error())
+		return
+	}
+
+	for _, name := range []string{"read", "execve", "connect", "ptrace"} {
+		number, _ := GetSyscallNumber(name)
+
+		found := false
+		for _, allowed := range allowList {
+			if allowed == number {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Errorf("[FAIL] Failed to include %s in a syscall allow-list", name)
+			return
+		}
+	}
+
+	for _, invalid := range []string{"", "@unknown", "read,nosuchcall"} {
+		if _, err := ParseSyscallAllowList(invalid); err == nil {
+			t.Errorf("[FAIL] Parsed an invalid syscall allow-list (%s)", invalid)
+			return
+		}
+	}
+
+	t.Log("[PASS] Parsed syscall allow-lists")
+}
+
+func TestSyscallAllowList(t *testing.T) {
+	// Set up Test Data
+
+	Containers := map[string]tp.Container{}
+	ContainersLock := new(sync.RWMutex)
+
+	ActivePidMap := map[string]tp.PidMap{}
+	ActiveHostPidMap := map[string]tp.PidMap{}
+	ActivePidMapLock := new(sync.RWMutex)
+
+	ActiveHostMap := map[uint32]tp.PidMap{}
+	ActiveHostMapLock := new(sync.RWMutex)
+
+	systemMonitor := NewSystemMonitor(nil, false, false, &Containers, &ContainersLock,
+		&ActivePidMap, &ActiveHostPidMap, &ActivePidMapLock, &ActiveHostMap, &ActiveHostMapLock)
+
+	read, _ := GetSyscallNumber("read")
+	ptrace, _ := GetSyscallNumber("ptrace")
+
+	// no allow-list
+
+	if !systemMonitor.IsSyscallAllowed("ubuntu-1-container", ptrace) {
+		t.Errorf("[FAIL] Flagged a syscall without an allow-list")
+		return
+	}
+
+	t.Log("[PASS] Allowed all syscalls without an allow-list")
+
+	// allow-list
+
+	allowList, err := ParseSyscallAllowList("@base")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to parse a syscall allow-list (%s)", err.Error())
+		return
+	}
+
+	systemMonitor.SetSyscallAllowList("ubuntu-1-container", allowList)
+
+	if !systemMonitor.IsSyscallAllowed("ubuntu-1-container", read) {
+		t.Errorf("[FAIL] Flagged an allowed syscall (read)")
+		return
+	}
+
+	t.Log("[PASS] Passed an allowed syscall")
+
+	if systemMonitor.IsSyscallAllowed("ubuntu-1-container", ptrace) {
+		t.Errorf("[FAIL] Failed to flag a disallowed syscall (ptrace)")
+		return
+	}
+
+	log := systemMonitor.BuildSyscallViolationLog(tp.Log{ContainerID: "ubuntu-1-container", Result: "Passed"}, ptrace)
+	if log.Type != "MatchedPolicy" || log.Operation != "Syscall" || log.Resource != "ptrace" || log.Action != "Audit" {
+		t.Errorf("[FAIL] Failed to build a log for a disallowed syscall (%v)", log)
+		return
+	}
+
+	t.Log("[PASS] Flagged a disallowed syscall")
+
+	// other containers are not affected
+
+	if !systemMonitor.IsSyscallAllowed("ubuntu-2-container", ptrace) {
+		t.Errorf("[FAIL] Flagged a syscall of another container")
+		return
+	}
+
+	systemMonitor.DeleteSyscallAllowList("ubuntu-1-container")
+
+	if !systemMonitor.IsSyscallAllowed("ubuntu-1-container", ptrace) {
+		t.Errorf("[FAIL] Flagged a syscall after deleting an allow-list")
+		return
+	}
+
+	t.Log("[PASS] Deleted a syscall allow-list")
+}
@@ -48,6 +48,9 @@ const (
 	SYS_EXECVE   = 59
 	SYS_EXECVEAT = 322
 	DO_EXIT      = 351
+
+	// syscall allow-lists
+	SYSCALL_VIOLATION = 353
 )
 
 const (
@@ -98,6 +101,9 @@ type ContextCombined struct {
 	ContainerID string
 	ContextSys  SyscallContext
 	ContextArgs []interface{}
+
+	// the container id may be wrong (shared PID namespace)
+	Ambiguous bool
 }
 
 // ======================= //
@@ -117,12 +123,16 @@ type SystemMonitor struct {
 	// logs
 	LogFeeder *fd.Feeder
 
+	// the number of dropped events by reason (shared with the feeder)
+	DropStats *fd.DropStats
+
 	// host name
 	HostName string
 
 	// options
-	EnableAuditd     bool
-	EnableHostPolicy bool
+	EnableAuditd      bool
+	EnableHostPolicy  bool
+	EnableSharedPidNs bool
 
 	// container id -> cotnainer
 	Containers     *map[string]tp.Container
@@ -137,6 +147,13 @@ type SystemMonitor struct {
 	NsMap     map[NsKey]string
 	NsMapLock *sync.RWMutex
 
+	// PidID -> container ids (protected by NsMapLock)
+	PidNsMap map[uint32][]string
+
+	// host pid -> container id (only for shared PID namespaces)
+	SharedPidMap     map[uint32]string
+	SharedPidMapLock *sync.RWMutex
+
 	// system monitor (for container)
 	BpfModule *bcc.Module
 
@@ -166,12 +183,40 @@ type SystemMonitor struct {
 	// lists to skip
 	UntrackedNamespaces []string
 
+	// interpreters whose scripts or inline commands are recorded
+	Interpreters []string
+
+	// container id -> process limits (the default limits otherwise)
+	DefaultProcessLimits ProcessLimits
+	ProcessLimits        map[string]ProcessLimits
+	ProcessLimitsLock    *sync.RWMutex
+
+	// container id + ancestor -> the time of the last alert (protected by ProcessLimitsLock)
+	ProcessLimitAlerts map[string]time.Time
+
+	// container id -> ancestor -> the recent spawns of descendants (protected by ProcessLimitsLock)
+	ProcessSpawns map[string]map[spawnKey]*spawnCounter
+
+	// container id -> allowed syscalls (no allow-list otherwise)
+	SyscallAllowLists     map[string][]int32
+	SyscallAllowListsLock *sync.RWMutex
+
+	// raw_syscalls:sys_enter is attached once the first allow-list is given (protected by SyscallAllowListsLock)
+	SyscallAllowProbe bool
+
 	UptimeTimeStamp float64
 	HostByteOrder   binary.ByteOrder
 
 	// ticker to clean up exited pids
 	Ticker *time.Ticker
 
+	// the time to keep exited pids and the maximum number of pids in each map (0 for no limit)
+	PidTTL        time.Duration
+	MaxPidEntries int
+
+	// clock for the exited time of pids (time.Now if nil)
+	Now func() time.Time
+
 	// GKE
 	IsCOS bool
 }
@@ -185,6 +230,10 @@ func NewSystemMonitor(feeder *fd.Feeder, enableAuditd, enableHostPolicy bool,
 
 	mon.LogFeeder = feeder
 
+	if feeder != nil {
+		mon.DropStats = feeder.DropStats
+	}
+
 	mon.HostName = kl.GetHostName()
 
 	mon.EnableAuditd = enableAuditd
@@ -203,16 +252,36 @@ func NewSystemMonitor(feeder *fd.Feeder, enableAuditd, enableHostPolicy bool,
 	mon.NsMap = make(map[NsKey]string)
 	mon.NsMapLock = new(sync.RWMutex)
 
+	mon.PidNsMap = make(map[uint32][]string)
+
+	mon.SharedPidMap = make(map[uint32]string)
+	mon.SharedPidMapLock = new(sync.RWMutex)
+
 	mon.ContextChan = make(chan ContextCombined, 4096)
 	mon.HostContextChan = make(chan ContextCombined, 4096)
 
 	mon.UntrackedNamespaces = []string{"kube-system"}
 
+	mon.Interpreters = []string{}
+
+	mon.DefaultProcessLimits = ProcessLimits{Window: time.Second * DefaultProcessLimitWindow}
+	mon.ProcessLimits = map[string]ProcessLimits{}
+	mon.ProcessLimitsLock = new(sync.RWMutex)
+
+	mon.SyscallAllowLists = map[string][]int32{}
+	mon.SyscallAllowListsLock = new(sync.RWMutex)
+	mon.ProcessLimitAlerts = map[string]time.Time{}
+	mon.ProcessSpawns = map[string]map[spawnKey]*spawnCounter{}
+
 	mon.UptimeTimeStamp = kl.GetUptimeTimestamp()
 	mon.HostByteOrder = bcc.GetHostByteOrder()
 
 	mon.Ticker = time.NewTicker(time.Second * 1)
 
+	mon.PidTTL = time.Second * DefaultPidTTL
+	mon.MaxPidEntries = DefaultMaxPidEntries
+	mon.Now = time.Now
+
 	mon.IsCOS = false
 
 	return mon
@@ -328,6 +397,15 @@ func (mon *SystemMonitor) InitBPF() error {
 		return fmt.Errorf("error initializing events perf map: %v", err)
 	}
 
+	// syscall allow-lists given before the eBPF program is loaded
+	mon.SyscallAllowListsLock.Lock()
+	if len(mon.SyscallAllowLists) > 0 {
+		if err := mon.attachSyscallAllowProbe(); err != nil {
+			mon.LogFeeder.Errf("Failed to enable syscall allow-lists (%s)", err.Error())
+		}
+	}
+	mon.SyscallAllowListsLock.Unlock()
+
 	if mon.EnableHostPolicy {
 		for _, syscallName := range systemCalls {
 			kp, err := mon.HostBpfModule.LoadKprobe(fmt.Sprintf("syscall__%s", syscallName))
@@ -415,6 +493,42 @@ func (mon *SystemMonitor) DestroySystemMonitor() error {
 // == System Call Trace == //
 // ======================= //
 
+// IsUntrackedContainer Function
+func (mon *SystemMonitor) IsUntrackedContainer(containerID string) bool {
+	Containers := *(mon.Containers)
+	ContainersLock := *(mon.ContainersLock)
+
+	ContainersLock.RLock()
+	namespace := Containers[containerID].NamespaceName
+	ContainersLock.RUnlock()
+
+	if kl.ContainsElement(mon.UntrackedNamespaces, namespace) {
+		mon.DropStats.Add(fd.DropReasonNamespace, 1)
+		return true
+	}
+
+	return false
+}
+
+// pushExecAlerts Function
+func (mon *SystemMonitor) pushExecAlerts(log tp.Log, ctx SyscallContext) {
+	if ctx.Retval < 0 || mon.LogFeeder == nil {
+		return
+	}
+
+	// alert if the process runs from memory (e.g., memfd)
+	if alert, ok := BuildFilelessLog(log, ctx.HostPID); ok {
+		go mon.LogFeeder.PushLog(alert)
+	}
+
+	// alert if the process tree of a container exceeds the process limits
+	if log.ContainerID != "" {
+		for _, alert := range mon.CheckProcessLimits(log, ctx.PID, time.Now()) {
+			go mon.LogFeeder.PushLog(alert)
+		}
+	}
+}
+
 // TraceSyscall Function
 func (mon *SystemMonitor) TraceSyscall() {
 	if mon.SyscallPerfMap != nil {
@@ -423,9 +537,6 @@ func (mon *SystemMonitor) TraceSyscall() {
 		return
 	}
 
-	Containers := *(mon.Containers)
-	ContainersLock := *(mon.ContainersLock)
-
 	execLogMap := map[uint32]tp.Log{}
 
 	for {
@@ -452,6 +563,7 @@ func (mon *SystemMonitor) TraceSyscall() {
 			// get container id
 
 			containerID := ""
+			ambiguous := false
 
 			if ctx.PidID != 0 && ctx.MntID != 0 {
 				if ctx.EventID == SYS_EXECVE || ctx.EventID == SYS_EXECVEAT {
@@ -460,14 +572,13 @@ func (mon *SystemMonitor) TraceSyscall() {
 					containerID = mon.LookupContainerID(ctx.PidID, ctx.MntID, ctx.HostPID, false)
 				}
 
+				// containers sharing a PID namespace
 				if containerID != "" {
-					ContainersLock.RLock()
-					namespace := Containers[containerID].NamespaceName
-					if kl.ContainsElement(mon.UntrackedNamespaces, namespace) {
-						ContainersLock.RUnlock()
-						continue
-					}
-					ContainersLock.RUnlock()
+					containerID, ambiguous = mon.LookupSharedContainerID(ctx.PidID, ctx.HostPID, containerID)
+				}
+
+				if containerID != "" && mon.IsUntrackedContainer(containerID) {
+					continue
 				}
 			}
 
@@ -492,7 +603,7 @@ func (mon *SystemMonitor) TraceSyscall() {
 
 					// generate a log with the base information
 
-					log := mon.BuildLogBase(ContextCombined{ContainerID: containerID, ContextSys: ctx})
+					log := mon.BuildLogBase(ContextCombined{ContainerID: containerID, ContextSys: ctx, Ambiguous: ambiguous})
 
 					// add arguments
 
@@ -550,6 +661,10 @@ func (mon *SystemMonitor) TraceSyscall() {
 					if mon.LogFeeder != nil {
 						go mon.LogFeeder.PushLog(log)
 					}
+
+					// alert the execution if needed (e.g., fileless)
+
+					mon.pushExecAlerts(log, ctx)
 				}
 
 				continue
@@ -562,7 +677,7 @@ func (mon *SystemMonitor) TraceSyscall() {
 
 					// generate a log with the base information
 
-					log := mon.BuildLogBase(ContextCombined{ContainerID: containerID, ContextSys: ctx})
+					log := mon.BuildLogBase(ContextCombined{ContainerID: containerID, ContextSys: ctx, Ambiguous: ambiguous})
 
 					// add arguments
 
@@ -631,18 +746,24 @@ func (mon *SystemMonitor) TraceSyscall() {
 					if mon.LogFeeder != nil {
 						go mon.LogFeeder.PushLog(log)
 					}
+
+					// alert the execution if needed (e.g., fileless)
+
+					mon.pushExecAlerts(log, ctx)
 				}
 
 				continue
 			} else if ctx.EventID == DO_EXIT {
 				mon.DeleteActivePid(containerID, ctx)
+				mon.DeleteSharedPid(ctx.HostPID)
 				continue
 			}
 
 			// push the context to the channel for logging
-			mon.ContextChan <- ContextCombined{ContainerID: containerID, ContextSys: ctx, ContextArgs: args}
+			mon.ContextChan <- ContextCombined{ContainerID: containerID, ContextSys: ctx, ContextArgs: args, Ambiguous: ambiguous}
 
-		case _ = <-mon.SyscallLostChannel:
+		case lost := <-mon.SyscallLostChannel:
+			mon.DropStats.Add(fd.DropReasonLostEvent, lost)
 			continue
 		}
 	}
@@ -754,6 +875,10 @@ func (mon *SystemMonitor) TraceHostSyscall() {
 					if mon.LogFeeder != nil {
 						go mon.LogFeeder.PushLog(log)
 					}
+
+					// alert the execution if needed (e.g., fileless)
+
+					mon.pushExecAlerts(log, ctx)
 				}
 
 				continue
@@ -835,6 +960,10 @@ func (mon *SystemMonitor) TraceHostSyscall() {
 					if mon.LogFeeder != nil {
 						go mon.LogFeeder.PushLog(log)
 					}
+
+					// alert the execution if needed (e.g., fileless)
+
+					mon.pushExecAlerts(log, ctx)
 				}
 
 				continue
@@ -846,7 +975,8 @@ func (mon *SystemMonitor) TraceHostSyscall() {
 			// push the context to the channel for logging
 			mon.HostContextChan <- ContextCombined{ContainerID: "", ContextSys: ctx, ContextArgs: args}
 
-		case _ = <-mon.HostSyscallLostChannel:
+		case lost := <-mon.HostSyscallLostChannel:
+			mon.DropStats.Add(fd.DropReasonLostEvent, lost)
 			continue
 		}
 	}
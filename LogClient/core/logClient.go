@@ -7,6 +7,7 @@ import (
 	"math/rand"
 	"strings"
 	"sync"
+	"time"
 
 	ll "github.com/accuknox/KubeArmor/LogClient/common"
 
@@ -172,28 +173,56 @@ func (lc *LogClient) WatchLogs(logPath string, jsonFormat bool) error {
 			if res.NamespaceName != "" {
 				str = str + fmt.Sprintf("Namespace Name: %s\n", res.NamespaceName)
 				str = str + fmt.Sprintf("Pod Name: %s\n", res.PodName)
+				if len(res.Workload) > 0 {
+					str = str + fmt.Sprintf("Workload: %s\n", res.Workload)
+				}
 				str = str + fmt.Sprintf("Container ID: %s\n", res.ContainerID)
 				str = str + fmt.Sprintf("Container Name: %s\n", res.ContainerName)
+
+				if res.Ambiguous {
+					str = str + "Ambiguous: true\n"
+				}
 			}
 
 			if len(res.PolicyName) > 0 {
 				str = str + fmt.Sprintf("Policy Name: %s\n", res.PolicyName)
 			}
 
+			if len(res.PolicySet) > 0 {
+				str = str + fmt.Sprintf("Policy Set: %s\n", res.PolicySet)
+			}
+
 			if len(res.Severity) > 0 {
 				str = str + fmt.Sprintf("Severity: %s\n", res.Severity)
 			}
 
+			if len(res.OriginalSeverity) > 0 {
+				str = str + fmt.Sprintf("Original Severity: %s\n", res.OriginalSeverity)
+			}
+
 			if len(res.Tags) > 0 {
 				str = str + fmt.Sprintf("Tags: %s\n", res.Tags)
 			}
 
+			if len(res.ComplianceTags) > 0 {
+				str = str + fmt.Sprintf("Compliance Tags: %s\n", strings.Join(res.ComplianceTags, ","))
+			}
+
+			if len(res.MitreTechniques) > 0 {
+				str = str + fmt.Sprintf("MITRE Techniques: %s\n", strings.Join(res.MitreTechniques, ","))
+			}
+
 			if len(res.Message) > 0 {
 				str = str + fmt.Sprintf("Message: %s\n", res.Message)
 			}
 
 			str = str + fmt.Sprintf("Type: %s\n", res.Type)
 			str = str + fmt.Sprintf("Source: %s\n", res.Source)
+
+			if len(res.InterpretedCommand) > 0 {
+				str = str + fmt.Sprintf("Interpreted Command: %s\n", res.InterpretedCommand)
+			}
+
 			str = str + fmt.Sprintf("Operation: %s\n", res.Operation)
 			str = str + fmt.Sprintf("Resource: %s\n", res.Resource)
 
@@ -206,6 +235,18 @@ func (lc *LogClient) WatchLogs(logPath string, jsonFormat bool) error {
 			}
 
 			str = str + fmt.Sprintf("Result: %s\n", res.Result)
+
+			if res.Notified {
+				str = str + "Notified: true\n"
+			}
+
+			if len(res.DecisionPoint) > 0 {
+				str = str + fmt.Sprintf("Decision Point: %s (%s)\n", res.DecisionPoint, time.Duration(res.MatchLatency))
+			}
+
+			if res.Count > 0 {
+				str = str + fmt.Sprintf("Count: %d\n", res.Count)
+			}
 		}
 
 		if logPath == "stdout" {
@@ -48,7 +48,7 @@ func main() {
 	gRPCPtr := flag.String("gRPC", "localhost:32767", "gRPC server information")
 	msgPathPtr := flag.String("msgPath", "none", "Output location for messages, {path|stdout|none}")
 	logPathPtr := flag.String("logPath", "stdout", "Output location for alerts and logs, {path|stdout|none}")
-	logFilterPtr := flag.String("logFilter", "policy", "Filter for what kinds of alerts and logs to receive, {policy|system|all}")
+	logFilterPtr := flag.String("logFilter", "policy", "Filter for what kinds of alerts and logs to receive, {policy|system|decision|all}")
 	jsonPtr := flag.Bool("json", false, "Flag to print alerts and logs in the JSON format")
 	flag.Parse()
 
@@ -57,7 +57,7 @@ func main() {
 		return
 	}
 
-	if *logFilterPtr != "all" && *logFilterPtr != "policy" && *logFilterPtr != "system" {
+	if *logFilterPtr != "all" && *logFilterPtr != "policy" && *logFilterPtr != "system" && *logFilterPtr != "decision" {
 		flag.PrintDefaults()
 		return
 	}
@@ -38,6 +38,9 @@ type MatchPathType string
 // +kubebuilder:validation:Pattern=^\/([A-z0-9-_.]+\/)*([A-z0-9-_.]+)+\/$
 type MatchDirectoryType string
 
+// +kubebuilder:validation:Pattern=^[A-Fa-f0-9]{64}$
+type MatchHashType string
+
 type MatchSourceType struct {
 	Path      MatchPathType      `json:"path,omitempty"`
 	Directory MatchDirectoryType `json:"dir,omitempty"`
@@ -49,6 +52,9 @@ type MatchSourceType struct {
 type ProcessPathType struct {
 	Path MatchPathType `json:"path"`
 
+	// +kubebuilder:validation:Optional
+	SHA256 MatchHashType `json:"sha256,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	OwnerOnly bool `json:"ownerOnly,omitempty"`
 
@@ -146,9 +152,12 @@ type CapabilitiesType struct {
 	MatchCapabilities []MatchCapabilitiesType `json:"matchCapabilities,omitempty"`
 }
 
-// +kubebuilder:validation:Enum=Audit;Allow;Block;AllowWithAudit;BlockWithAudit
+// +kubebuilder:validation:Enum=Audit;Allow;Block;AllowWithAudit;BlockWithAudit;Quarantine
 type ActionType string
 
+// +kubebuilder:validation:Pattern=^[Tt][0-9]{4}(\.[0-9]{3})?$
+type MitreTechniqueType string
+
 // KubeArmorHostPolicySpec defines the desired state of KubeArmorHostPolicy
 type KubeArmorHostPolicySpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
@@ -159,6 +168,12 @@ type KubeArmorHostPolicySpec struct {
 	Tags    []string `json:"tags,omitempty"`
 	Message string   `json:"message,omitempty"`
 
+	// +kubebuilder:validation:Optional
+	ComplianceTags []string `json:"complianceTags,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	MitreTechniques []MitreTechniqueType `json:"mitreTechniques,omitempty"`
+
 	NodeSelector NodeSelectorType `json:"nodeSelector"`
 
 	Process      ProcessType      `json:"process,omitempty"`
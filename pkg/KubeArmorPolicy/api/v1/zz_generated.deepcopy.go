@@ -202,6 +202,16 @@ func (in *KubeArmorPolicySpec) DeepCopyInto(out *KubeArmorPolicySpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ComplianceTags != nil {
+		in, out := &in.ComplianceTags, &out.ComplianceTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MitreTechniques != nil {
+		in, out := &in.MitreTechniques, &out.MitreTechniques
+		*out = make([]MitreTechniqueType, len(*in))
+		copy(*out, *in)
+	}
 	in.Selector.DeepCopyInto(&out.Selector)
 	in.Process.DeepCopyInto(&out.Process)
 	in.File.DeepCopyInto(&out.File)
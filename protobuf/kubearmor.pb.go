@@ -166,28 +166,40 @@ type Log struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	UpdatedTime   string `protobuf:"bytes,1,opt,name=UpdatedTime,proto3" json:"UpdatedTime,omitempty"`
-	ClusterName   string `protobuf:"bytes,2,opt,name=ClusterName,proto3" json:"ClusterName,omitempty"`
-	HostName      string `protobuf:"bytes,3,opt,name=HostName,proto3" json:"HostName,omitempty"`
-	NamespaceName string `protobuf:"bytes,4,opt,name=NamespaceName,proto3" json:"NamespaceName,omitempty"`
-	PodName       string `protobuf:"bytes,5,opt,name=PodName,proto3" json:"PodName,omitempty"`
-	ContainerID   string `protobuf:"bytes,6,opt,name=ContainerID,proto3" json:"ContainerID,omitempty"`
-	ContainerName string `protobuf:"bytes,7,opt,name=ContainerName,proto3" json:"ContainerName,omitempty"`
-	HostPID       int32  `protobuf:"varint,8,opt,name=HostPID,proto3" json:"HostPID,omitempty"`
-	PPID          int32  `protobuf:"varint,9,opt,name=PPID,proto3" json:"PPID,omitempty"`
-	PID           int32  `protobuf:"varint,10,opt,name=PID,proto3" json:"PID,omitempty"`
-	UID           int32  `protobuf:"varint,11,opt,name=UID,proto3" json:"UID,omitempty"`
-	PolicyName    string `protobuf:"bytes,12,opt,name=PolicyName,proto3" json:"PolicyName,omitempty"`
-	Severity      string `protobuf:"bytes,13,opt,name=Severity,proto3" json:"Severity,omitempty"`
-	Tags          string `protobuf:"bytes,14,opt,name=Tags,proto3" json:"Tags,omitempty"`
-	Message       string `protobuf:"bytes,15,opt,name=Message,proto3" json:"Message,omitempty"`
-	Type          string `protobuf:"bytes,16,opt,name=Type,proto3" json:"Type,omitempty"`
-	Source        string `protobuf:"bytes,17,opt,name=Source,proto3" json:"Source,omitempty"`
-	Operation     string `protobuf:"bytes,18,opt,name=Operation,proto3" json:"Operation,omitempty"`
-	Resource      string `protobuf:"bytes,19,opt,name=Resource,proto3" json:"Resource,omitempty"`
-	Data          string `protobuf:"bytes,20,opt,name=Data,proto3" json:"Data,omitempty"`
-	Action        string `protobuf:"bytes,21,opt,name=Action,proto3" json:"Action,omitempty"`
-	Result        string `protobuf:"bytes,22,opt,name=Result,proto3" json:"Result,omitempty"`
+	UpdatedTime        string   `protobuf:"bytes,1,opt,name=UpdatedTime,proto3" json:"UpdatedTime,omitempty"`
+	ClusterName        string   `protobuf:"bytes,2,opt,name=ClusterName,proto3" json:"ClusterName,omitempty"`
+	HostName           string   `protobuf:"bytes,3,opt,name=HostName,proto3" json:"HostName,omitempty"`
+	NamespaceName      string   `protobuf:"bytes,4,opt,name=NamespaceName,proto3" json:"NamespaceName,omitempty"`
+	PodName            string   `protobuf:"bytes,5,opt,name=PodName,proto3" json:"PodName,omitempty"`
+	ContainerID        string   `protobuf:"bytes,6,opt,name=ContainerID,proto3" json:"ContainerID,omitempty"`
+	ContainerName      string   `protobuf:"bytes,7,opt,name=ContainerName,proto3" json:"ContainerName,omitempty"`
+	HostPID            int32    `protobuf:"varint,8,opt,name=HostPID,proto3" json:"HostPID,omitempty"`
+	PPID               int32    `protobuf:"varint,9,opt,name=PPID,proto3" json:"PPID,omitempty"`
+	PID                int32    `protobuf:"varint,10,opt,name=PID,proto3" json:"PID,omitempty"`
+	UID                int32    `protobuf:"varint,11,opt,name=UID,proto3" json:"UID,omitempty"`
+	PolicyName         string   `protobuf:"bytes,12,opt,name=PolicyName,proto3" json:"PolicyName,omitempty"`
+	Severity           string   `protobuf:"bytes,13,opt,name=Severity,proto3" json:"Severity,omitempty"`
+	Tags               string   `protobuf:"bytes,14,opt,name=Tags,proto3" json:"Tags,omitempty"`
+	Message            string   `protobuf:"bytes,15,opt,name=Message,proto3" json:"Message,omitempty"`
+	Type               string   `protobuf:"bytes,16,opt,name=Type,proto3" json:"Type,omitempty"`
+	Source             string   `protobuf:"bytes,17,opt,name=Source,proto3" json:"Source,omitempty"`
+	Operation          string   `protobuf:"bytes,18,opt,name=Operation,proto3" json:"Operation,omitempty"`
+	Resource           string   `protobuf:"bytes,19,opt,name=Resource,proto3" json:"Resource,omitempty"`
+	Data               string   `protobuf:"bytes,20,opt,name=Data,proto3" json:"Data,omitempty"`
+	Action             string   `protobuf:"bytes,21,opt,name=Action,proto3" json:"Action,omitempty"`
+	Result             string   `protobuf:"bytes,22,opt,name=Result,proto3" json:"Result,omitempty"`
+	InterpretedCommand string   `protobuf:"bytes,23,opt,name=InterpretedCommand,proto3" json:"InterpretedCommand,omitempty"`
+	Seq                uint64   `protobuf:"varint,24,opt,name=Seq,proto3" json:"Seq,omitempty"`
+	Workload           string   `protobuf:"bytes,25,opt,name=Workload,proto3" json:"Workload,omitempty"`
+	Count              int32    `protobuf:"varint,26,opt,name=Count,proto3" json:"Count,omitempty"`
+	Ambiguous          bool     `protobuf:"varint,27,opt,name=Ambiguous,proto3" json:"Ambiguous,omitempty"`
+	ComplianceTags     []string `protobuf:"bytes,28,rep,name=ComplianceTags,proto3" json:"ComplianceTags,omitempty"`
+	MitreTechniques    []string `protobuf:"bytes,29,rep,name=MitreTechniques,proto3" json:"MitreTechniques,omitempty"`
+	OriginalSeverity   string   `protobuf:"bytes,30,opt,name=OriginalSeverity,proto3" json:"OriginalSeverity,omitempty"`
+	DecisionPoint      string   `protobuf:"bytes,31,opt,name=DecisionPoint,proto3" json:"DecisionPoint,omitempty"`
+	MatchLatency       int64    `protobuf:"varint,32,opt,name=MatchLatency,proto3" json:"MatchLatency,omitempty"`
+	PolicySet          string   `protobuf:"bytes,33,opt,name=PolicySet,proto3" json:"PolicySet,omitempty"`
+	Notified           bool     `protobuf:"varint,34,opt,name=Notified,proto3" json:"Notified,omitempty"`
 }
 
 func (x *Log) Reset() {
@@ -376,13 +388,98 @@ func (x *Log) GetResult() string {
 	return ""
 }
 
+func (x *Log) GetInterpretedCommand() string {
+	if x != nil {
+		return x.InterpretedCommand
+	}
+	return ""
+}
+
+func (x *Log) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *Log) GetWorkload() string {
+	if x != nil {
+		return x.Workload
+	}
+	return ""
+}
+
+func (x *Log) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *Log) GetAmbiguous() bool {
+	if x != nil {
+		return x.Ambiguous
+	}
+	return false
+}
+
+func (x *Log) GetComplianceTags() []string {
+	if x != nil {
+		return x.ComplianceTags
+	}
+	return nil
+}
+
+func (x *Log) GetMitreTechniques() []string {
+	if x != nil {
+		return x.MitreTechniques
+	}
+	return nil
+}
+
+func (x *Log) GetOriginalSeverity() string {
+	if x != nil {
+		return x.OriginalSeverity
+	}
+	return ""
+}
+
+func (x *Log) GetDecisionPoint() string {
+	if x != nil {
+		return x.DecisionPoint
+	}
+	return ""
+}
+
+func (x *Log) GetMatchLatency() int64 {
+	if x != nil {
+		return x.MatchLatency
+	}
+	return 0
+}
+
+func (x *Log) GetPolicySet() string {
+	if x != nil {
+		return x.PolicySet
+	}
+	return ""
+}
+
+func (x *Log) GetNotified() bool {
+	if x != nil {
+		return x.Notified
+	}
+	return false
+}
+
 // request message
 type RequestMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Filter string `protobuf:"bytes,1,opt,name=Filter,proto3" json:"Filter,omitempty"`
+	Filter   string `protobuf:"bytes,1,opt,name=Filter,proto3" json:"Filter,omitempty"`
+	Backfill bool   `protobuf:"varint,2,opt,name=Backfill,proto3" json:"Backfill,omitempty"`
 }
 
 func (x *RequestMessage) Reset() {
@@ -424,6 +521,77 @@ func (x *RequestMessage) GetFilter() string {
 	return ""
 }
 
+func (x *RequestMessage) GetBackfill() bool {
+	if x != nil {
+		return x.Backfill
+	}
+	return false
+}
+
+// log ack message (the first message subscribes a consumer)
+type LogAckMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ConsumerID string   `protobuf:"bytes,1,opt,name=ConsumerID,proto3" json:"ConsumerID,omitempty"`
+	Filter     string   `protobuf:"bytes,2,opt,name=Filter,proto3" json:"Filter,omitempty"`
+	Seqs       []uint64 `protobuf:"varint,3,rep,packed,name=Seqs,proto3" json:"Seqs,omitempty"`
+}
+
+func (x *LogAckMessage) Reset() {
+	*x = LogAckMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_kubearmor_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LogAckMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogAckMessage) ProtoMessage() {}
+
+func (x *LogAckMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_kubearmor_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogAckMessage.ProtoReflect.Descriptor instead.
+func (*LogAckMessage) Descriptor() ([]byte, []int) {
+	return file_kubearmor_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *LogAckMessage) GetConsumerID() string {
+	if x != nil {
+		return x.ConsumerID
+	}
+	return ""
+}
+
+func (x *LogAckMessage) GetFilter() string {
+	if x != nil {
+		return x.Filter
+	}
+	return ""
+}
+
+func (x *LogAckMessage) GetSeqs() []uint64 {
+	if x != nil {
+		return x.Seqs
+	}
+	return nil
+}
+
 // reply message
 type ReplyMessage struct {
 	state         protoimpl.MessageState
@@ -436,7 +604,7 @@ type ReplyMessage struct {
 func (x *ReplyMessage) Reset() {
 	*x = ReplyMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_kubearmor_proto_msgTypes[4]
+		mi := &file_kubearmor_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -449,7 +617,7 @@ func (x *ReplyMessage) String() string {
 func (*ReplyMessage) ProtoMessage() {}
 
 func (x *ReplyMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_kubearmor_proto_msgTypes[4]
+	mi := &file_kubearmor_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -462,7 +630,7 @@ func (x *ReplyMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReplyMessage.ProtoReflect.Descriptor instead.
 func (*ReplyMessage) Descriptor() ([]byte, []int) {
-	return file_kubearmor_proto_rawDescGZIP(), []int{4}
+	return file_kubearmor_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *ReplyMessage) GetRetval() int32 {
@@ -472,6 +640,230 @@ func (x *ReplyMessage) GetRetval() int32 {
 	return 0
 }
 
+// process tree request
+type ProcessTreeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ContainerID string `protobuf:"bytes,1,opt,name=ContainerID,proto3" json:"ContainerID,omitempty"`
+	Limit       int32  `protobuf:"varint,2,opt,name=Limit,proto3" json:"Limit,omitempty"`
+}
+
+func (x *ProcessTreeRequest) Reset() {
+	*x = ProcessTreeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_kubearmor_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProcessTreeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessTreeRequest) ProtoMessage() {}
+
+func (x *ProcessTreeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kubearmor_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessTreeRequest.ProtoReflect.Descriptor instead.
+func (*ProcessTreeRequest) Descriptor() ([]byte, []int) {
+	return file_kubearmor_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ProcessTreeRequest) GetContainerID() string {
+	if x != nil {
+		return x.ContainerID
+	}
+	return ""
+}
+
+func (x *ProcessTreeRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// process node struct
+type ProcessNode struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HostPID    uint32 `protobuf:"varint,1,opt,name=HostPID,proto3" json:"HostPID,omitempty"`
+	PPID       uint32 `protobuf:"varint,2,opt,name=PPID,proto3" json:"PPID,omitempty"`
+	PID        uint32 `protobuf:"varint,3,opt,name=PID,proto3" json:"PID,omitempty"`
+	UID        uint32 `protobuf:"varint,4,opt,name=UID,proto3" json:"UID,omitempty"`
+	Comm       string `protobuf:"bytes,5,opt,name=Comm,proto3" json:"Comm,omitempty"`
+	ExecPath   string `protobuf:"bytes,6,opt,name=ExecPath,proto3" json:"ExecPath,omitempty"`
+	Exited     bool   `protobuf:"varint,7,opt,name=Exited,proto3" json:"Exited,omitempty"`
+	ExitedTime string `protobuf:"bytes,8,opt,name=ExitedTime,proto3" json:"ExitedTime,omitempty"`
+}
+
+func (x *ProcessNode) Reset() {
+	*x = ProcessNode{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_kubearmor_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProcessNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessNode) ProtoMessage() {}
+
+func (x *ProcessNode) ProtoReflect() protoreflect.Message {
+	mi := &file_kubearmor_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessNode.ProtoReflect.Descriptor instead.
+func (*ProcessNode) Descriptor() ([]byte, []int) {
+	return file_kubearmor_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ProcessNode) GetHostPID() uint32 {
+	if x != nil {
+		return x.HostPID
+	}
+	return 0
+}
+
+func (x *ProcessNode) GetPPID() uint32 {
+	if x != nil {
+		return x.PPID
+	}
+	return 0
+}
+
+func (x *ProcessNode) GetPID() uint32 {
+	if x != nil {
+		return x.PID
+	}
+	return 0
+}
+
+func (x *ProcessNode) GetUID() uint32 {
+	if x != nil {
+		return x.UID
+	}
+	return 0
+}
+
+func (x *ProcessNode) GetComm() string {
+	if x != nil {
+		return x.Comm
+	}
+	return ""
+}
+
+func (x *ProcessNode) GetExecPath() string {
+	if x != nil {
+		return x.ExecPath
+	}
+	return ""
+}
+
+func (x *ProcessNode) GetExited() bool {
+	if x != nil {
+		return x.Exited
+	}
+	return false
+}
+
+func (x *ProcessNode) GetExitedTime() string {
+	if x != nil {
+		return x.ExitedTime
+	}
+	return ""
+}
+
+// process tree snapshot
+type ProcessTree struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ContainerID string         `protobuf:"bytes,1,opt,name=ContainerID,proto3" json:"ContainerID,omitempty"`
+	Nodes       []*ProcessNode `protobuf:"bytes,2,rep,name=Nodes,proto3" json:"Nodes,omitempty"`
+	Truncated   bool           `protobuf:"varint,3,opt,name=Truncated,proto3" json:"Truncated,omitempty"`
+}
+
+func (x *ProcessTree) Reset() {
+	*x = ProcessTree{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_kubearmor_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProcessTree) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessTree) ProtoMessage() {}
+
+func (x *ProcessTree) ProtoReflect() protoreflect.Message {
+	mi := &file_kubearmor_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessTree.ProtoReflect.Descriptor instead.
+func (*ProcessTree) Descriptor() ([]byte, []int) {
+	return file_kubearmor_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ProcessTree) GetContainerID() string {
+	if x != nil {
+		return x.ContainerID
+	}
+	return ""
+}
+
+func (x *ProcessTree) GetNodes() []*ProcessNode {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+func (x *ProcessTree) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
 var File_kubearmor_proto protoreflect.FileDescriptor
 
 var file_kubearmor_proto_rawDesc = []byte{
@@ -490,7 +882,7 @@ var file_kubearmor_proto_rawDesc = []byte{
 	0x74, 0x49, 0x50, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x05, 0x20, 0x01,
 	0x28, 0x09, 0x52, 0x05, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x4d, 0x65, 0x73,
 	0x73, 0x61, 0x67, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x22, 0xd3, 0x04, 0x0a, 0x03, 0x4c, 0x6f, 0x67, 0x12, 0x20, 0x0a, 0x0b, 0x55,
+	0x61, 0x67, 0x65, 0x22, 0xe7, 0x07, 0x0a, 0x03, 0x4c, 0x6f, 0x67, 0x12, 0x20, 0x0a, 0x0b, 0x55,
 	0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
 	0x52, 0x0b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x20, 0x0a,
 	0x0b, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
@@ -527,27 +919,93 @@ var file_kubearmor_proto_rawDesc = []byte{
 	0x01, 0x28, 0x09, 0x52, 0x04, 0x44, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x63, 0x74,
 	0x69, 0x6f, 0x6e, 0x18, 0x15, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f,
 	0x6e, 0x12, 0x16, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x16, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x28, 0x0a, 0x0e, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x46,
-	0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x46, 0x69, 0x6c,
-	0x74, 0x65, 0x72, 0x22, 0x26, 0x0a, 0x0c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x52, 0x65, 0x74, 0x76, 0x61, 0x6c, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x06, 0x52, 0x65, 0x74, 0x76, 0x61, 0x6c, 0x32, 0xb7, 0x01, 0x0a, 0x0a,
-	0x4c, 0x6f, 0x67, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x39, 0x0a, 0x0b, 0x48, 0x65,
-	0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x12, 0x14, 0x2e, 0x66, 0x65, 0x65, 0x64,
-	0x65, 0x72, 0x2e, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a,
-	0x14, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x4d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x3a, 0x0a, 0x0d, 0x57, 0x61, 0x74, 0x63, 0x68, 0x4d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x12, 0x16, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x65, 0x72, 0x2e,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x0f,
-	0x2e, 0x66, 0x65, 0x65, 0x64, 0x65, 0x72, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x30,
-	0x01, 0x12, 0x32, 0x0a, 0x09, 0x57, 0x61, 0x74, 0x63, 0x68, 0x4c, 0x6f, 0x67, 0x73, 0x12, 0x16,
-	0x2e, 0x66, 0x65, 0x65, 0x64, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x0b, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x65, 0x72, 0x2e,
-	0x4c, 0x6f, 0x67, 0x30, 0x01, 0x42, 0x28, 0x5a, 0x26, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
-	0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x63, 0x63, 0x75, 0x6b, 0x6e, 0x6f, 0x78, 0x2f, 0x4b, 0x75, 0x62,
-	0x65, 0x41, 0x72, 0x6d, 0x6f, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x09, 0x52, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x2e, 0x0a, 0x12, 0x49, 0x6e, 0x74,
+	0x65, 0x72, 0x70, 0x72, 0x65, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18,
+	0x17, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x72, 0x65, 0x74,
+	0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x53, 0x65, 0x71,
+	0x18, 0x18, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x53, 0x65, 0x71, 0x12, 0x1a, 0x0a, 0x08, 0x57,
+	0x6f, 0x72, 0x6b, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x19, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x57,
+	0x6f, 0x72, 0x6b, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x1a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1c, 0x0a,
+	0x09, 0x41, 0x6d, 0x62, 0x69, 0x67, 0x75, 0x6f, 0x75, 0x73, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x09, 0x41, 0x6d, 0x62, 0x69, 0x67, 0x75, 0x6f, 0x75, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x43,
+	0x6f, 0x6d, 0x70, 0x6c, 0x69, 0x61, 0x6e, 0x63, 0x65, 0x54, 0x61, 0x67, 0x73, 0x18, 0x1c, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x0e, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x69, 0x61, 0x6e, 0x63, 0x65, 0x54,
+	0x61, 0x67, 0x73, 0x12, 0x28, 0x0a, 0x0f, 0x4d, 0x69, 0x74, 0x72, 0x65, 0x54, 0x65, 0x63, 0x68,
+	0x6e, 0x69, 0x71, 0x75, 0x65, 0x73, 0x18, 0x1d, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x4d, 0x69,
+	0x74, 0x72, 0x65, 0x54, 0x65, 0x63, 0x68, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x73, 0x12, 0x2a, 0x0a,
+	0x10, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x53, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74,
+	0x79, 0x18, 0x1e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61,
+	0x6c, 0x53, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x12, 0x24, 0x0a, 0x0d, 0x44, 0x65, 0x63,
+	0x69, 0x73, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x1f, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x44, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12,
+	0x22, 0x0a, 0x0c, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18,
+	0x20, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x4c, 0x61, 0x74, 0x65,
+	0x6e, 0x63, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x53, 0x65, 0x74,
+	0x18, 0x21, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x53, 0x65,
+	0x74, 0x12, 0x1a, 0x0a, 0x08, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x65, 0x64, 0x18, 0x22, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x65, 0x64, 0x22, 0x44, 0x0a,
+	0x0e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x42, 0x61, 0x63, 0x6b, 0x66,
+	0x69, 0x6c, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x42, 0x61, 0x63, 0x6b, 0x66,
+	0x69, 0x6c, 0x6c, 0x22, 0x5b, 0x0a, 0x0d, 0x4c, 0x6f, 0x67, 0x41, 0x63, 0x6b, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x43, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x72,
+	0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x43, 0x6f, 0x6e, 0x73, 0x75, 0x6d,
+	0x65, 0x72, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04,
+	0x53, 0x65, 0x71, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x04, 0x52, 0x04, 0x53, 0x65, 0x71, 0x73,
+	0x22, 0x26, 0x0a, 0x0c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x52, 0x65, 0x74, 0x76, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x06, 0x52, 0x65, 0x74, 0x76, 0x61, 0x6c, 0x22, 0x4c, 0x0a, 0x12, 0x50, 0x72, 0x6f, 0x63,
+	0x65, 0x73, 0x73, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20,
+	0x0a, 0x0b, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x44,
+	0x12, 0x14, 0x0a, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0xc7, 0x01, 0x0a, 0x0b, 0x50, 0x72, 0x6f, 0x63, 0x65,
+	0x73, 0x73, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x48, 0x6f, 0x73, 0x74, 0x50, 0x49,
+	0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x48, 0x6f, 0x73, 0x74, 0x50, 0x49, 0x44,
+	0x12, 0x12, 0x0a, 0x04, 0x50, 0x50, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04,
+	0x50, 0x50, 0x49, 0x44, 0x12, 0x10, 0x0a, 0x03, 0x50, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x03, 0x50, 0x49, 0x44, 0x12, 0x10, 0x0a, 0x03, 0x55, 0x49, 0x44, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x03, 0x55, 0x49, 0x44, 0x12, 0x12, 0x0a, 0x04, 0x43, 0x6f, 0x6d, 0x6d,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x43, 0x6f, 0x6d, 0x6d, 0x12, 0x1a, 0x0a, 0x08,
+	0x45, 0x78, 0x65, 0x63, 0x50, 0x61, 0x74, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x45, 0x78, 0x65, 0x63, 0x50, 0x61, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x78, 0x69, 0x74,
+	0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x45, 0x78, 0x69, 0x74, 0x65, 0x64,
+	0x12, 0x1e, 0x0a, 0x0a, 0x45, 0x78, 0x69, 0x74, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x45, 0x78, 0x69, 0x74, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65,
+	0x22, 0x78, 0x0a, 0x0b, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x54, 0x72, 0x65, 0x65, 0x12,
+	0x20, 0x0a, 0x0b, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49,
+	0x44, 0x12, 0x29, 0x0a, 0x05, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x13, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x65, 0x72, 0x2e, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73,
+	0x73, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x05, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09,
+	0x54, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x09, 0x54, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x64, 0x32, 0xb6, 0x02, 0x0a, 0x0a, 0x4c,
+	0x6f, 0x67, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x39, 0x0a, 0x0b, 0x48, 0x65, 0x61,
+	0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x12, 0x14, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x65,
+	0x72, 0x2e, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x14,
+	0x2e, 0x66, 0x65, 0x65, 0x64, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x3a, 0x0a, 0x0d, 0x57, 0x61, 0x74, 0x63, 0x68, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x73, 0x12, 0x16, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x65, 0x72, 0x2e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x0f, 0x2e,
+	0x66, 0x65, 0x65, 0x64, 0x65, 0x72, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x30, 0x01,
+	0x12, 0x32, 0x0a, 0x09, 0x57, 0x61, 0x74, 0x63, 0x68, 0x4c, 0x6f, 0x67, 0x73, 0x12, 0x16, 0x2e,
+	0x66, 0x65, 0x65, 0x64, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x0b, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x65, 0x72, 0x2e, 0x4c,
+	0x6f, 0x67, 0x30, 0x01, 0x12, 0x3a, 0x0a, 0x10, 0x57, 0x61, 0x74, 0x63, 0x68, 0x4c, 0x6f, 0x67,
+	0x73, 0x57, 0x69, 0x74, 0x68, 0x41, 0x63, 0x6b, 0x12, 0x15, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x65,
+	0x72, 0x2e, 0x4c, 0x6f, 0x67, 0x41, 0x63, 0x6b, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a,
+	0x0b, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x65, 0x72, 0x2e, 0x4c, 0x6f, 0x67, 0x28, 0x01, 0x30, 0x01,
+	0x12, 0x41, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x54, 0x72,
+	0x65, 0x65, 0x12, 0x1a, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x65, 0x72, 0x2e, 0x50, 0x72, 0x6f, 0x63,
+	0x65, 0x73, 0x73, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13,
+	0x2e, 0x66, 0x65, 0x65, 0x64, 0x65, 0x72, 0x2e, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x54,
+	0x72, 0x65, 0x65, 0x42, 0x28, 0x5a, 0x26, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x61, 0x63, 0x63, 0x75, 0x6b, 0x6e, 0x6f, 0x78, 0x2f, 0x4b, 0x75, 0x62, 0x65, 0x41,
+	0x72, 0x6d, 0x6f, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -562,26 +1020,35 @@ func file_kubearmor_proto_rawDescGZIP() []byte {
 	return file_kubearmor_proto_rawDescData
 }
 
-var file_kubearmor_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_kubearmor_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_kubearmor_proto_goTypes = []interface{}{
-	(*NonceMessage)(nil),   // 0: feeder.NonceMessage
-	(*Message)(nil),        // 1: feeder.Message
-	(*Log)(nil),            // 2: feeder.Log
-	(*RequestMessage)(nil), // 3: feeder.RequestMessage
-	(*ReplyMessage)(nil),   // 4: feeder.ReplyMessage
+	(*NonceMessage)(nil),       // 0: feeder.NonceMessage
+	(*Message)(nil),            // 1: feeder.Message
+	(*Log)(nil),                // 2: feeder.Log
+	(*RequestMessage)(nil),     // 3: feeder.RequestMessage
+	(*LogAckMessage)(nil),      // 4: feeder.LogAckMessage
+	(*ReplyMessage)(nil),       // 5: feeder.ReplyMessage
+	(*ProcessTreeRequest)(nil), // 6: feeder.ProcessTreeRequest
+	(*ProcessNode)(nil),        // 7: feeder.ProcessNode
+	(*ProcessTree)(nil),        // 8: feeder.ProcessTree
 }
 var file_kubearmor_proto_depIdxs = []int32{
-	0, // 0: feeder.LogService.HealthCheck:input_type -> feeder.NonceMessage
-	3, // 1: feeder.LogService.WatchMessages:input_type -> feeder.RequestMessage
-	3, // 2: feeder.LogService.WatchLogs:input_type -> feeder.RequestMessage
-	4, // 3: feeder.LogService.HealthCheck:output_type -> feeder.ReplyMessage
-	1, // 4: feeder.LogService.WatchMessages:output_type -> feeder.Message
-	2, // 5: feeder.LogService.WatchLogs:output_type -> feeder.Log
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	7, // 0: feeder.ProcessTree.Nodes:type_name -> feeder.ProcessNode
+	0, // 1: feeder.LogService.HealthCheck:input_type -> feeder.NonceMessage
+	3, // 2: feeder.LogService.WatchMessages:input_type -> feeder.RequestMessage
+	3, // 3: feeder.LogService.WatchLogs:input_type -> feeder.RequestMessage
+	4, // 4: feeder.LogService.WatchLogsWithAck:input_type -> feeder.LogAckMessage
+	6, // 5: feeder.LogService.GetProcessTree:input_type -> feeder.ProcessTreeRequest
+	5, // 6: feeder.LogService.HealthCheck:output_type -> feeder.ReplyMessage
+	1, // 7: feeder.LogService.WatchMessages:output_type -> feeder.Message
+	2, // 8: feeder.LogService.WatchLogs:output_type -> feeder.Log
+	2, // 9: feeder.LogService.WatchLogsWithAck:output_type -> feeder.Log
+	8, // 10: feeder.LogService.GetProcessTree:output_type -> feeder.ProcessTree
+	6, // [6:11] is the sub-list for method output_type
+	1, // [1:6] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_kubearmor_proto_init() }
@@ -639,6 +1106,18 @@ func file_kubearmor_proto_init() {
 			}
 		}
 		file_kubearmor_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LogAckMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_kubearmor_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ReplyMessage); i {
 			case 0:
 				return &v.state
@@ -650,6 +1129,42 @@ func file_kubearmor_proto_init() {
 				return nil
 			}
 		}
+		file_kubearmor_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProcessTreeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_kubearmor_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProcessNode); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_kubearmor_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProcessTree); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -657,7 +1172,7 @@ func file_kubearmor_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_kubearmor_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -686,6 +1201,8 @@ type LogServiceClient interface {
 	HealthCheck(ctx context.Context, in *NonceMessage, opts ...grpc.CallOption) (*ReplyMessage, error)
 	WatchMessages(ctx context.Context, in *RequestMessage, opts ...grpc.CallOption) (LogService_WatchMessagesClient, error)
 	WatchLogs(ctx context.Context, in *RequestMessage, opts ...grpc.CallOption) (LogService_WatchLogsClient, error)
+	WatchLogsWithAck(ctx context.Context, opts ...grpc.CallOption) (LogService_WatchLogsWithAckClient, error)
+	GetProcessTree(ctx context.Context, in *ProcessTreeRequest, opts ...grpc.CallOption) (*ProcessTree, error)
 }
 
 type logServiceClient struct {
@@ -769,11 +1286,53 @@ func (x *logServiceWatchLogsClient) Recv() (*Log, error) {
 	return m, nil
 }
 
+func (c *logServiceClient) WatchLogsWithAck(ctx context.Context, opts ...grpc.CallOption) (LogService_WatchLogsWithAckClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LogService_serviceDesc.Streams[2], "/feeder.LogService/WatchLogsWithAck", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &logServiceWatchLogsWithAckClient{stream}
+	return x, nil
+}
+
+type LogService_WatchLogsWithAckClient interface {
+	Send(*LogAckMessage) error
+	Recv() (*Log, error)
+	grpc.ClientStream
+}
+
+type logServiceWatchLogsWithAckClient struct {
+	grpc.ClientStream
+}
+
+func (x *logServiceWatchLogsWithAckClient) Send(m *LogAckMessage) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *logServiceWatchLogsWithAckClient) Recv() (*Log, error) {
+	m := new(Log)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *logServiceClient) GetProcessTree(ctx context.Context, in *ProcessTreeRequest, opts ...grpc.CallOption) (*ProcessTree, error) {
+	out := new(ProcessTree)
+	err := c.cc.Invoke(ctx, "/feeder.LogService/GetProcessTree", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LogServiceServer is the server API for LogService service.
 type LogServiceServer interface {
 	HealthCheck(context.Context, *NonceMessage) (*ReplyMessage, error)
 	WatchMessages(*RequestMessage, LogService_WatchMessagesServer) error
 	WatchLogs(*RequestMessage, LogService_WatchLogsServer) error
+	WatchLogsWithAck(LogService_WatchLogsWithAckServer) error
+	GetProcessTree(context.Context, *ProcessTreeRequest) (*ProcessTree, error)
 }
 
 // UnimplementedLogServiceServer can be embedded to have forward compatible implementations.
@@ -789,6 +1348,12 @@ func (*UnimplementedLogServiceServer) WatchMessages(*RequestMessage, LogService_
 func (*UnimplementedLogServiceServer) WatchLogs(*RequestMessage, LogService_WatchLogsServer) error {
 	return status.Errorf(codes.Unimplemented, "method WatchLogs not implemented")
 }
+func (*UnimplementedLogServiceServer) WatchLogsWithAck(LogService_WatchLogsWithAckServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchLogsWithAck not implemented")
+}
+func (*UnimplementedLogServiceServer) GetProcessTree(context.Context, *ProcessTreeRequest) (*ProcessTree, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProcessTree not implemented")
+}
 
 func RegisterLogServiceServer(s *grpc.Server, srv LogServiceServer) {
 	s.RegisterService(&_LogService_serviceDesc, srv)
@@ -854,6 +1419,50 @@ func (x *logServiceWatchLogsServer) Send(m *Log) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _LogService_WatchLogsWithAck_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LogServiceServer).WatchLogsWithAck(&logServiceWatchLogsWithAckServer{stream})
+}
+
+type LogService_WatchLogsWithAckServer interface {
+	Send(*Log) error
+	Recv() (*LogAckMessage, error)
+	grpc.ServerStream
+}
+
+type logServiceWatchLogsWithAckServer struct {
+	grpc.ServerStream
+}
+
+func (x *logServiceWatchLogsWithAckServer) Send(m *Log) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *logServiceWatchLogsWithAckServer) Recv() (*LogAckMessage, error) {
+	m := new(LogAckMessage)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _LogService_GetProcessTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProcessTreeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogServiceServer).GetProcessTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/feeder.LogService/GetProcessTree",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogServiceServer).GetProcessTree(ctx, req.(*ProcessTreeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _LogService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "feeder.LogService",
 	HandlerType: (*LogServiceServer)(nil),
@@ -862,6 +1471,10 @@ var _LogService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "HealthCheck",
 			Handler:    _LogService_HealthCheck_Handler,
 		},
+		{
+			MethodName: "GetProcessTree",
+			Handler:    _LogService_GetProcessTree_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -874,6 +1487,12 @@ var _LogService_serviceDesc = grpc.ServiceDesc{
 			Handler:       _LogService_WatchLogs_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchLogsWithAck",
+			Handler:       _LogService_WatchLogsWithAck_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "kubearmor.proto",
 }